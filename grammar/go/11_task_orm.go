@@ -1,16 +1,10 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
-	"reflect"
-	"strings"
-	"sync"
 	"time"
-	// 如需使用 MySQL，请先在项目根目录执行：
-	// go get -u github.com/go-sql-driver/mysql
-	// 然后在下方取消注释并保存
-	// _ "github.com/go-sql-driver/mysql" // MySQL驱动
+
+	"orm_demo/orm"
 )
 
 // ### 任务8：数据库ORM
@@ -25,22 +19,7 @@ import (
 // 5. 添加事务支持
 // 6. 实现关联查询和预加载
 
-// ============================================================================
-// 第1步：设计模型接口和标签系统
-// ============================================================================
-
-// Model 基础模型接口
-type Model interface {
-	TableName() string // 返回表名
-}
-
-// 字段标签定义：
-// - `db:"column_name"` : 数据库列名
-// - `primary_key:"true"` : 主键标识
-// - `auto_increment:"true"` : 自增标识
-// - `type:"varchar(255)"` : 数据库类型
-// - `null:"false"` : 是否允许为空
-// - `default:"value"` : 默认值
+// ORM 框架本体见 orm 包，这里保留示例模型和演示代码
 
 // 示例模型结构体
 type DBUser struct {
@@ -55,755 +34,6 @@ func (u DBUser) TableName() string {
 	return "users"
 }
 
-// ============================================================================
-// 第2步：使用反射解析结构体
-// ============================================================================
-
-// FieldInfo 字段信息
-type FieldInfo struct {
-	Name         string            // Go字段名
-	DBName       string            // 数据库列名
-	Type         reflect.Type      // Go类型
-	DBType       string            // 数据库类型
-	IsPrimaryKey bool              // 是否主键
-	IsAutoIncr   bool              // 是否自增
-	IsNull       bool              // 是否允许为空
-	DefaultValue string            // 默认值
-	Tag          reflect.StructTag // 完整标签
-}
-
-// ModelInfo 模型信息
-type ModelInfo struct {
-	Type       reflect.Type
-	TableName  string
-	Fields     []FieldInfo
-	PrimaryKey *FieldInfo // 主键字段
-}
-
-// parseModel 解析模型结构体
-// TODO: 实现反射解析逻辑
-// - 使用reflect.TypeOf()获取类型信息
-// - 遍历结构体字段，解析标签
-// - 构建FieldInfo和ModelInfo
-func ParseModel(model interface{}) (*ModelInfo, error) {
-	// 实现提示：
-	// 1. 获取reflect.Type和reflect.Value
-	t := reflect.TypeOf(model)
-	// v := reflect.ValueOf(model)
-
-	// 2. 如果是指针类型，获取其指向的类型
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
-	}
-
-	// 3. 检查是否为结构体类型
-	if t.Kind() != reflect.Struct {
-		return nil, fmt.Errorf("parseModel: 输入参数必须是结构体类型")
-	}
-	// 3. 遍历字段，解析db标签
-	fieldInfo := []FieldInfo{}
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		dbTag := field.Tag.Get("db")
-		if dbTag == "" {
-			continue // 跳过没有db标签的字段
-		}
-		fieldInfo = append(fieldInfo, FieldInfo{
-			Name:         field.Name,
-			DBName:       dbTag,
-			Type:         field.Type,
-			DBType:       field.Tag.Get("type"),
-			IsPrimaryKey: field.Tag.Get("primary_key") == "true",
-			IsAutoIncr:   field.Tag.Get("auto_increment") == "true",
-			IsNull:       field.Tag.Get("null") == "false",
-			DefaultValue: field.Tag.Get("default"),
-			Tag:          field.Tag,
-		})
-	}
-	// 4. 处理primary_key、auto_increment等特殊标签
-	primaryKey := (*FieldInfo)(nil)
-	for _, field := range fieldInfo {
-		if field.IsPrimaryKey {
-			if primaryKey != nil {
-				return nil, fmt.Errorf("parseModel: 结构体只能有一个主键字段")
-			}
-			primaryKey = &field
-		}
-	}
-	// 5. 构建并返回ModelInfo
-	return &ModelInfo{
-			Type:       t,
-			TableName:  model.(Model).TableName(),
-			Fields:     fieldInfo,
-			PrimaryKey: primaryKey},
-		nil
-}
-
-// ============================================================================
-// 第3步：实现SQL查询构建器
-// ============================================================================
-
-// QueryBuilder SQL查询构建器
-type QueryBuilder struct {
-	tableName string
-	fields    []string
-	where     []string
-	orderBy   []string
-	groupBy   []string
-	having    []string
-	limit     int
-	offset    int
-	joins     []string
-	args      []interface{}
-	preloads  []string // 预加载关联关系
-}
-
-// NewQueryBuilder 创建查询构建器
-func NewQueryBuilder(tableName string) *QueryBuilder {
-	return &QueryBuilder{
-		tableName: tableName,
-		fields:    []string{},
-		where:     []string{},
-		orderBy:   []string{},
-		groupBy:   []string{},
-		having:    []string{},
-		joins:     []string{},
-		args:      []interface{}{},
-		preloads:  []string{}, // 初始化预加载关联列表
-	}
-}
-
-// Select 设置查询字段
-// TODO: 实现字段选择逻辑
-func (qb *QueryBuilder) Select(fields ...string) *QueryBuilder {
-	// 实现提示：设置qb.fields为fields
-	qb.fields = fields
-	return qb
-}
-
-// Where 添加WHERE条件
-// TODO: 实现WHERE条件构建
-func (qb *QueryBuilder) Where(condition string, args ...interface{}) *QueryBuilder {
-	// 实现提示：
-	// 1. 添加条件到qb.where
-	qb.where = append(qb.where, condition)
-	// 2. 添加参数到qb.args
-	qb.args = append(qb.args, args...)
-	return qb
-}
-
-// OrderBy 添加排序
-// TODO: 实现排序逻辑
-func (qb *QueryBuilder) OrderBy(field string, direction ...string) *QueryBuilder {
-	// 实现提示：构建 "field ASC/DESC" 格式
-	order := field + " ASC"
-	if len(direction) > 0 && direction[0] == "DESC" {
-		order = field + " DESC"
-	}
-	qb.orderBy = append(qb.orderBy, order)
-	return qb
-}
-
-// Limit 设置限制条数
-func (qb *QueryBuilder) Limit(limit int) *QueryBuilder {
-	qb.limit = limit
-	return qb
-}
-
-// Offset 设置偏移量
-func (qb *QueryBuilder) Offset(offset int) *QueryBuilder {
-	qb.offset = offset
-	return qb
-}
-
-// BuildSelect 构建SELECT语句
-// TODO: 实现SELECT语句构建
-func (qb *QueryBuilder) BuildSelect() (string, []interface{}) {
-	// 实现提示：
-	// 1. 构建基础SELECT语句
-	query := fmt.Sprintf("select %s from %s", strings.Join(qb.fields, ","), qb.tableName)
-	// 2. 添加WHERE条件
-	if qb.where != nil {
-		query += fmt.Sprintf(" where %s", strings.Join(qb.where, " and "))
-	}
-	// 3. 添加ORDER BY、LIMIT等子句
-	if qb.orderBy != nil {
-		query += fmt.Sprintf(" order by %s", strings.Join(qb.orderBy, ","))
-	}
-	if qb.limit > 0 {
-		query += fmt.Sprintf(" limit %d", qb.limit)
-	}
-	if qb.offset > 0 {
-		query += fmt.Sprintf(" offset %d", qb.offset)
-	}
-	// 4. 返回SQL和参数
-	return query, qb.args
-}
-
-// BuildInsert 构建INSERT语句
-// TODO: 实现INSERT语句构建
-func (qb *QueryBuilder) BuildInsert(data map[string]interface{}) (string, []interface{}) {
-	// 实现提示：构建 INSERT INTO table (cols) VALUES (?)
-	cols := []string{}
-	args := []interface{}{}
-	for col, val := range data {
-		cols = append(cols, col)
-		args = append(args, val)
-	}
-	query := fmt.Sprintf("insert into %s (%s) values (%s)", qb.tableName, strings.Join(cols, ","), strings.TrimSuffix(strings.Repeat("?,", len(cols)), ","))
-	return query, args
-}
-
-// BuildUpdate 构建UPDATE语句
-// TODO: 实现UPDATE语句构建
-func (qb *QueryBuilder) BuildUpdate(data map[string]interface{}) (string, []interface{}) {
-	// 实现提示：构建 UPDATE table SET col=? WHERE conditions
-	updates := []string{}
-	for col, val := range data {
-		updates = append(updates, fmt.Sprintf("%s=?", col))
-		qb.args = append(qb.args, val)
-	}
-	query := fmt.Sprintf("update %s set %s where %s", qb.tableName, strings.Join(updates, ","), strings.Join(qb.where, " and "))
-	return query, qb.args
-}
-
-// BuildDelete 构建DELETE语句
-// TODO: 实现DELETE语句构建
-func (qb *QueryBuilder) BuildDelete() (string, []interface{}) {
-	// 实现提示：构建 DELETE FROM table WHERE conditions
-	query := fmt.Sprintf("delete from %s where %s", qb.tableName, strings.Join(qb.where, " and "))
-	return query, qb.args
-}
-
-// ============================================================================
-// 第4步：实现数据库连接池
-// ============================================================================
-
-// DBConfig 数据库配置
-type DBConfig struct {
-	Driver          string        // 数据库驱动
-	DSN             string        // 数据源名称
-	MaxOpenConns    int           // 最大打开连接数
-	MaxIdleConns    int           // 最大空闲连接数
-	ConnMaxLifetime time.Duration // 连接最大生存时间
-	ConnMaxIdleTime time.Duration // 连接最大空闲时间
-}
-
-// ConnectionPool 连接池
-type ConnectionPool struct {
-	db     *sql.DB
-	config DBConfig
-	mutex  sync.RWMutex
-}
-
-// NewConnectionPool 创建连接池
-// TODO: 实现连接池初始化
-func NewConnectionPool(config DBConfig) (*ConnectionPool, error) {
-	// 实现提示：
-	// 1. 使用sql.Open()创建数据库连接
-	db, err := sql.Open(config.Driver, config.DSN)
-	if err != nil {
-		return nil, err
-	}
-	// 2. 设置连接池参数
-	db.SetMaxOpenConns(config.MaxOpenConns)
-	db.SetMaxIdleConns(config.MaxIdleConns)
-	db.SetConnMaxLifetime(config.ConnMaxLifetime)
-	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
-	// 3. 测试连接是否可用
-	if err := db.Ping(); err != nil {
-		return nil, err
-	}
-	return &ConnectionPool{db: db, config: config}, nil
-}
-
-// GetConnection 获取数据库连接
-func (cp *ConnectionPool) GetConnection() *sql.DB {
-	cp.mutex.RLock()
-	defer cp.mutex.RUnlock()
-	return cp.db
-}
-
-// Close 关闭连接池
-func (cp *ConnectionPool) Close() error {
-	cp.mutex.Lock()
-	defer cp.mutex.Unlock()
-	if cp.db != nil {
-		return cp.db.Close()
-	}
-	return nil
-}
-
-// ============================================================================
-// 第5步：添加事务支持
-// ============================================================================
-
-// Transaction 事务接口
-type Transaction interface {
-	Commit() error
-	Rollback() error
-	Exec(query string, args ...interface{}) (sql.Result, error)
-	Query(query string, args ...interface{}) (*sql.Rows, error)
-	QueryRow(query string, args ...interface{}) *sql.Row
-}
-
-// DBTransaction 数据库事务实现
-type DBTransaction struct {
-	tx *sql.Tx
-}
-
-// Begin 开始事务
-// TODO: 实现事务开始逻辑
-func (cp *ConnectionPool) Begin() (Transaction, error) {
-	// 实现提示：
-	// 1. 获取数据库连接
-	db := cp.GetConnection()
-	// 2. 调用db.Begin()
-	tx, err := db.Begin()
-	if err != nil {
-		return nil, err
-	}
-	// 3. 返回DBTransaction包装
-	return &DBTransaction{tx: tx}, nil
-}
-
-// Commit 提交事务
-func (tx *DBTransaction) Commit() error {
-	return tx.tx.Commit()
-}
-
-// Rollback 回滚事务
-func (tx *DBTransaction) Rollback() error {
-	return tx.tx.Rollback()
-}
-
-// Exec 执行SQL
-func (tx *DBTransaction) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return tx.tx.Exec(query, args...)
-}
-
-// Query 查询多行
-func (tx *DBTransaction) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	return tx.tx.Query(query, args...)
-}
-
-// QueryRow 查询单行
-func (tx *DBTransaction) QueryRow(query string, args ...interface{}) *sql.Row {
-	return tx.tx.QueryRow(query, args...)
-}
-
-// ============================================================================
-// 第6步：实现关联查询和预加载
-// ============================================================================
-
-// RelationType 关联类型
-type RelationType int
-
-const (
-	HasOne     RelationType = iota // 一对一
-	HasMany                        // 一对多
-	BelongsTo                      // 属于
-	ManyToMany                     // 多对多
-)
-
-// Association 关联定义
-type Association struct {
-	Type         RelationType // 关联类型
-	Model        interface{}  // 关联模型
-	ForeignKey   string       // 外键
-	LocalKey     string       // 本地键
-	PivotTable   string       // 中间表(多对多)
-	PivotForeign string       // 中间表外键
-	PivotLocal   string       // 中间表本地键
-}
-
-// EagerLoader 预加载器
-type EagerLoader struct {
-	associations map[string]Association
-	loaded       map[string]bool
-}
-
-// With 指定预加载关联
-func (qb *QueryBuilder) With(relations ...string) *QueryBuilder {
-	// 1. 解析关联关系 - 将关联名称添加到预加载列表
-	qb.preloads = append(qb.preloads, relations...)
-
-	// 2. 构建关联查询 - 在实际执行查询时处理
-	// 这里只是标记需要预加载的关联，具体的JOIN查询在BuildSelect中处理
-
-	// 3. 设置预加载标记 - 已通过添加到preloads列表完成
-	return qb
-}
-
-// parseAssociations 解析模型的关联定义
-func parseAssociations(modelType reflect.Type) (map[string]Association, error) {
-	associations := make(map[string]Association)
-
-	for i := 0; i < modelType.NumField(); i++ {
-		field := modelType.Field(i)
-
-		// 检查各种关联标签
-		if hasOneTag := field.Tag.Get("has_one"); hasOneTag != "" {
-			associations[field.Name] = Association{
-				Type:       HasOne,
-				Model:      reflect.New(field.Type.Elem()).Interface(), // 创建关联模型实例
-				ForeignKey: field.Tag.Get("foreign_key"),
-				LocalKey:   field.Tag.Get("local_key"),
-			}
-		} else if hasManyTag := field.Tag.Get("has_many"); hasManyTag != "" {
-			// 处理切片类型，获取元素类型
-			elemType := field.Type.Elem()
-			associations[field.Name] = Association{
-				Type:       HasMany,
-				Model:      reflect.New(elemType).Interface(),
-				ForeignKey: field.Tag.Get("foreign_key"),
-				LocalKey:   field.Tag.Get("local_key"),
-			}
-		} else if belongsToTag := field.Tag.Get("belongs_to"); belongsToTag != "" {
-			associations[field.Name] = Association{
-				Type:       BelongsTo,
-				Model:      reflect.New(field.Type.Elem()).Interface(),
-				ForeignKey: field.Tag.Get("foreign_key"),
-				LocalKey:   field.Tag.Get("local_key"),
-			}
-		} else if manyToManyTag := field.Tag.Get("many_to_many"); manyToManyTag != "" {
-			elemType := field.Type.Elem()
-			associations[field.Name] = Association{
-				Type:         ManyToMany,
-				Model:        reflect.New(elemType).Interface(),
-				ForeignKey:   field.Tag.Get("foreign_key"),
-				LocalKey:     field.Tag.Get("local_key"),
-				PivotTable:   field.Tag.Get("pivot_table"),
-				PivotForeign: field.Tag.Get("pivot_foreign"),
-				PivotLocal:   field.Tag.Get("pivot_local"),
-			}
-		}
-	}
-
-	return associations, nil
-}
-
-// getModelPrimaryKeyValue 获取模型的主键值
-func getModelPrimaryKeyValue(model interface{}) (interface{}, error) {
-	modelValue := reflect.ValueOf(model)
-	if modelValue.Kind() == reflect.Ptr {
-		modelValue = modelValue.Elem()
-	}
-
-	modelType := modelValue.Type()
-	for i := 0; i < modelType.NumField(); i++ {
-		field := modelType.Field(i)
-		if field.Tag.Get("primary_key") == "true" {
-			return modelValue.Field(i).Interface(), nil
-		}
-	}
-
-	return nil, fmt.Errorf("未找到主键字段")
-}
-
-// LoadAssociations 加载关联数据
-func LoadAssociations(models interface{}, relations []string) error {
-	// 1. 解析模型关联定义
-	modelsValue := reflect.ValueOf(models)
-	if modelsValue.Kind() != reflect.Ptr || modelsValue.Elem().Kind() != reflect.Slice {
-		return fmt.Errorf("models参数必须是指向切片的指针")
-	}
-
-	slice := modelsValue.Elem()
-	if slice.Len() == 0 {
-		return nil // 空切片，无需处理
-	}
-
-	// 获取模型类型
-	modelType := slice.Index(0).Type()
-	if modelType.Kind() == reflect.Ptr {
-		modelType = modelType.Elem()
-	}
-
-	// 解析关联定义
-	associations, err := parseAssociations(modelType)
-	if err != nil {
-		return fmt.Errorf("解析关联定义失败: %v", err)
-	}
-
-	// 2. 根据关联类型构建查询
-	for _, relationName := range relations {
-		association, exists := associations[relationName]
-		if !exists {
-			return fmt.Errorf("关联 %s 不存在", relationName)
-		}
-
-		// 收集主键值
-		var primaryKeys []interface{}
-		for i := 0; i < slice.Len(); i++ {
-			model := slice.Index(i).Interface()
-			pk, err := getModelPrimaryKeyValue(model)
-			if err != nil {
-				return fmt.Errorf("获取主键值失败: %v", err)
-			}
-			primaryKeys = append(primaryKeys, pk)
-		}
-
-		// 3. 执行关联查询并填充数据
-		err = loadAssociationData(slice, association, relationName, primaryKeys)
-		if err != nil {
-			return fmt.Errorf("加载关联数据失败: %v", err)
-		}
-	}
-
-	return nil
-}
-
-// loadAssociationData 根据关联类型加载数据
-func loadAssociationData(models reflect.Value, association Association, relationName string, primaryKeys []interface{}) error {
-	switch association.Type {
-	case HasOne:
-		return loadHasOneAssociation(models, association, relationName, primaryKeys)
-	case HasMany:
-		return loadHasManyAssociation(models, association, relationName, primaryKeys)
-	case BelongsTo:
-		return loadBelongsToAssociation(models, association, relationName, primaryKeys)
-	case ManyToMany:
-		return loadManyToManyAssociation(models, association, relationName, primaryKeys)
-	default:
-		return fmt.Errorf("不支持的关联类型: %v", association.Type)
-	}
-}
-
-// loadHasOneAssociation 加载一对一关联
-func loadHasOneAssociation(models reflect.Value, association Association, relationName string, primaryKeys []interface{}) error {
-	// TODO: 实现一对一关联查询
-	// 构建查询: SELECT * FROM related_table WHERE foreign_key IN (primaryKeys)
-	fmt.Printf("加载HasOne关联: %s, 主键: %v\n", relationName, primaryKeys)
-	return nil
-}
-
-// loadHasManyAssociation 加载一对多关联
-func loadHasManyAssociation(models reflect.Value, association Association, relationName string, primaryKeys []interface{}) error {
-	// TODO: 实现一对多关联查询
-	// 构建查询: SELECT * FROM related_table WHERE foreign_key IN (primaryKeys)
-	fmt.Printf("加载HasMany关联: %s, 主键: %v\n", relationName, primaryKeys)
-	return nil
-}
-
-// loadBelongsToAssociation 加载属于关联
-func loadBelongsToAssociation(models reflect.Value, association Association, relationName string, primaryKeys []interface{}) error {
-	// TODO: 实现属于关联查询
-	// 构建查询: SELECT * FROM related_table WHERE id IN (foreign_keys)
-	fmt.Printf("加载BelongsTo关联: %s, 主键: %v\n", relationName, primaryKeys)
-	return nil
-}
-
-// loadManyToManyAssociation 加载多对多关联
-func loadManyToManyAssociation(models reflect.Value, association Association, relationName string, primaryKeys []interface{}) error {
-	// TODO: 实现多对多关联查询
-	// 构建查询: SELECT r.*, p.local_key FROM related_table r
-	//          JOIN pivot_table p ON r.id = p.foreign_key
-	//          WHERE p.local_key IN (primaryKeys)
-	fmt.Printf("加载ManyToMany关联: %s, 主键: %v\n", relationName, primaryKeys)
-	return nil
-}
-
-// ============================================================================
-// ORM 主要接口
-// ============================================================================
-
-// ORM 主要ORM结构体
-type ORM struct {
-	pool   *ConnectionPool
-	models map[string]*ModelInfo
-	mutex  sync.RWMutex
-}
-
-// NewORM 创建ORM实例
-func NewORM(config DBConfig) (*ORM, error) {
-	pool, err := NewConnectionPool(config)
-	if err != nil {
-		return nil, err
-	}
-
-	return &ORM{
-		pool:   pool,
-		models: make(map[string]*ModelInfo),
-	}, nil
-}
-
-// RegisterModel 注册模型
-// TODO: 实现模型注册
-func (orm *ORM) RegisterModel(model interface{}) error {
-	// 实现提示：
-	// 1. 解析模型信息
-	modelInfo, err := ParseModel(model)
-	if err != nil {
-		return err
-	}
-
-	// 2. 存储到orm.models中
-	orm.mutex.Lock()
-	defer orm.mutex.Unlock()
-	orm.models[modelInfo.TableName] = modelInfo
-
-	return nil
-}
-
-// Create 创建记录
-// TODO: 实现创建逻辑
-func (orm *ORM) Create(model interface{}) error {
-	// 实现提示：
-	// 1. 解析模型数据
-	modelInfo, err := ParseModel(model)
-	if err != nil {
-		return err
-	}
-
-	// 2. 构建INSERT语句
-	qb := NewQueryBuilder(modelInfo.TableName)
-	values := make(map[string]interface{})
-
-	// 获取模型的值
-	v := reflect.ValueOf(model)
-	if v.Kind() == reflect.Ptr {
-		v = v.Elem()
-	}
-
-	for _, field := range modelInfo.Fields {
-		fieldValue := v.FieldByName(field.Name)
-		if fieldValue.IsValid() && fieldValue.CanInterface() {
-			values[field.DBName] = fieldValue.Interface()
-		}
-	}
-
-	query, args := qb.BuildInsert(values)
-
-	// 3. 执行插入操作
-	if orm.pool == nil || orm.pool.db == nil {
-		fmt.Printf("模拟执行SQL: %s, 参数: %v\n", query, args)
-		return nil
-	}
-	fmt.Printf("执行SQL: %s, 参数: %v\n", query, args)
-	_, err = orm.pool.db.Exec(query, args...)
-	if err != nil {
-		return err
-	}
-
-	// 4. 处理自增ID回填
-	if modelInfo.PrimaryKey != nil && modelInfo.PrimaryKey.IsAutoIncr {
-		// 从数据库获取自增ID
-		query := "SELECT LAST_INSERT_ID()"
-		var id int64
-		err = orm.pool.db.QueryRow(query).Scan(&id)
-		if err != nil {
-			return err
-		}
-		// 设置到模型中
-		pkField := v.FieldByName(modelInfo.PrimaryKey.Name)
-		if pkField.IsValid() && pkField.CanSet() {
-			pkField.SetInt(id)
-		}
-	}
-	return nil
-}
-
-// Find 查找记录
-// TODO: 实现查找逻辑
-func (orm *ORM) Find(dest interface{}, conditions ...interface{}) error {
-	// 实现提示：
-	// 1. 解析目标模型
-	modelInfo, err := ParseModel(dest)
-	if err != nil {
-		return err
-	}
-
-	// 2. 构建查询条件
-	qb := NewQueryBuilder(modelInfo.TableName)
-	query, args := qb.BuildSelect()
-
-	// 模拟查询结果
-	if orm.pool == nil || orm.pool.db == nil {
-		fmt.Printf("模拟执行SQL: %s, 参数: %v\n", query, args)
-		fmt.Println("模拟查询结果: 找到1条记录")
-		return nil
-	}
-
-	// 2. 执行查询
-	rows, err := orm.pool.db.Query(query, args...)
-	if err != nil {
-		return err
-	}
-	defer rows.Close()
-
-	// 3. 扫描结果到目标结构体
-	for rows.Next() {
-		destValue := reflect.ValueOf(dest)
-		if destValue.Kind() == reflect.Ptr {
-			destValue = destValue.Elem()
-		}
-
-		if destValue.Kind() == reflect.Slice {
-			destValue.Set(reflect.Append(destValue, reflect.New(destValue.Type().Elem()).Elem()))
-		}
-		err = rows.Scan(destValue.Addr().Interface())
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// Update 更新记录
-// TODO: 实现更新逻辑
-func (orm *ORM) Update(model interface{}, updates interface{}) error {
-	// 实现提示：
-	// 1. 解析模型数据
-	modelInfo, err := ParseModel(model)
-	if err != nil {
-		return err
-	}
-	qb := NewQueryBuilder(modelInfo.TableName)
-	updatesMap := make(map[string]interface{})
-	for _, field := range modelInfo.Fields {
-		if field.DBName != modelInfo.PrimaryKey.DBName {
-			updatesMap[field.DBName] = reflect.ValueOf(updates).Elem().FieldByName(field.DBName).Interface()
-		}
-	}
-	query, args := qb.BuildUpdate(updatesMap)
-	// 2. 处理WHERE条件
-	_, err = orm.pool.db.Exec(query, args)
-	if err != nil {
-		return err
-	}
-	// 3. 执行更新操作
-	return nil
-}
-
-// Delete 删除记录
-// TODO: 实现删除逻辑
-func (orm *ORM) Delete(model interface{}) error {
-	// 实现提示：
-	// 1. 解析模型数据
-	modelInfo, err := ParseModel(model)
-	if err != nil {
-		return err
-	}
-	qb := NewQueryBuilder(modelInfo.TableName)
-	// 2. 根据主键构建WHERE条件
-	primaryKey := modelInfo.PrimaryKey.DBName
-	primaryValue := reflect.ValueOf(model).Elem().FieldByName(modelInfo.PrimaryKey.DBName).Interface()
-	qb.Where(primaryKey+" = ?", primaryValue)
-	query, args := qb.BuildDelete()
-	// 3. 执行删除操作
-	_, err = orm.pool.db.Exec(query, args...)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-// Query 返回查询构建器
-func (orm *ORM) Query(tableName string) *QueryBuilder {
-	return NewQueryBuilder(tableName)
-}
-
 // ============================================================================
 // 使用示例和测试
 // ============================================================================
@@ -852,7 +82,7 @@ func main11() {
 	fmt.Println("=== ORM框架演示开始 ===")
 
 	// 配置数据库连接
-	config := DBConfig{
+	config := orm.DBConfig{
 		Driver:          "mysql",
 		DSN:             "root:123456@tcp(localhost:3306)/exam?charset=utf8mb4&parseTime=True&loc=Local",
 		MaxOpenConns:    10,
@@ -865,20 +95,20 @@ func main11() {
 
 	// 创建ORM实例
 	fmt.Println("正在创建ORM实例...")
-	orm, err := NewORM(config)
+	db, err := orm.NewORM(config)
 	if err != nil {
 		fmt.Printf("创建ORM失败: %v\n", err)
 		fmt.Println("注意: 这是正常的，因为我们没有实际的数据库连接")
 		fmt.Println("继续演示其他功能...")
 	} else {
-		defer orm.pool.Close()
+		defer db.Pool().Close()
 		fmt.Println("ORM实例创建成功!")
 	}
 
 	// 注册模型
 	fmt.Println("\n--- 模型注册演示 ---")
-	if orm != nil {
-		err = orm.RegisterModel(&UserWithAssociations{})
+	if db != nil {
+		err = db.RegisterModel(&UserWithAssociations{})
 		if err != nil {
 			fmt.Printf("注册模型失败: %v\n", err)
 		} else {
@@ -888,7 +118,7 @@ func main11() {
 
 	// 演示模型解析功能
 	fmt.Println("\n--- 模型解析演示 ---")
-	modelInfo, err := ParseModel(&UserWithAssociations{})
+	modelInfo, err := orm.ParseModel(&UserWithAssociations{})
 	if err != nil {
 		fmt.Printf("解析模型失败: %v\n", err)
 	} else {
@@ -908,8 +138,8 @@ func main11() {
 		CreateAt: time.Now(),
 	}
 
-	if orm != nil {
-		err = orm.Create(user)
+	if db != nil {
+		err = db.Create(user)
 		if err != nil {
 			fmt.Printf("创建用户失败: %v\n", err)
 		} else {
@@ -922,8 +152,8 @@ func main11() {
 	// 查询用户 (模拟)
 	fmt.Println("\n--- 查询用户演示 ---")
 	var users []UserWithAssociations
-	if orm != nil {
-		err = orm.Find(&users, "age > ?", 20)
+	if db != nil {
+		err = db.Find(&users, "age > ?", 20)
 		if err != nil {
 			fmt.Printf("查询用户失败: %v\n", err)
 		} else {
@@ -936,11 +166,11 @@ func main11() {
 
 	// 使用查询构建器
 	fmt.Println("\n--- 查询构建器演示 ---")
-	var qb *QueryBuilder
-	if orm != nil {
-		qb = orm.Query("users")
+	var qb *orm.QueryBuilder
+	if db != nil {
+		qb = db.Query("users")
 	} else {
-		qb = NewQueryBuilder("users")
+		qb = orm.NewQueryBuilder("users")
 	}
 
 	qb = qb.Select("id", "name", "email").
@@ -960,11 +190,11 @@ func main11() {
 
 	// 1. 使用With进行预加载
 	fmt.Println("\n--- With预加载演示 ---")
-	var qbWithPreload *QueryBuilder
-	if orm != nil {
-		qbWithPreload = orm.Query("users")
+	var qbWithPreload *orm.QueryBuilder
+	if db != nil {
+		qbWithPreload = db.Query("users")
 	} else {
-		qbWithPreload = NewQueryBuilder("users")
+		qbWithPreload = orm.NewQueryBuilder("users")
 	}
 
 	qbWithPreload = qbWithPreload.Select("*").
@@ -973,7 +203,7 @@ func main11() {
 		Limit(5)
 
 	fmt.Printf("预加载查询构建器: %+v\n", qbWithPreload)
-	fmt.Printf("预加载关联: %v\n", qbWithPreload.preloads)
+	fmt.Printf("预加载关联: %v\n", qbWithPreload.Preloads())
 
 	// 2. 模拟查询用户数据
 	var usersWithAssoc []UserWithAssociations
@@ -993,7 +223,7 @@ func main11() {
 
 	// 3. 使用LoadAssociations后加载关联数据
 	fmt.Println("\n--- 使用LoadAssociations加载关联数据 ---")
-	err = LoadAssociations(&usersWithAssoc, []string{"Profile", "Posts"})
+	err = orm.LoadAssociations(&usersWithAssoc, []string{"Profile", "Posts"})
 	if err != nil {
 		fmt.Printf("加载关联数据失败: %v\n", err)
 	} else {
@@ -1006,7 +236,7 @@ func main11() {
 	// HasOne关联示例
 	var profileUsers []UserWithAssociations
 	profileUsers = append(profileUsers, UserWithAssociations{ID: 1, Name: "用户1"})
-	err = LoadAssociations(&profileUsers, []string{"Profile"})
+	err = orm.LoadAssociations(&profileUsers, []string{"Profile"})
 	if err != nil {
 		fmt.Printf("HasOne关联加载失败: %v\n", err)
 	}
@@ -1014,15 +244,15 @@ func main11() {
 	// HasMany关联示例
 	var postUsers []UserWithAssociations
 	postUsers = append(postUsers, UserWithAssociations{ID: 1, Name: "用户1"})
-	err = LoadAssociations(&postUsers, []string{"Posts"})
+	err = orm.LoadAssociations(&postUsers, []string{"Posts"})
 	if err != nil {
 		fmt.Printf("HasMany关联加载失败: %v\n", err)
 	}
 
 	// 事务示例
 	fmt.Println("\n=== 事务示例 ===")
-	if orm != nil {
-		tx, err := orm.pool.Begin()
+	if db != nil {
+		tx, err := db.Pool().Begin()
 		if err != nil {
 			fmt.Printf("开始事务失败: %v\n", err)
 		} else {
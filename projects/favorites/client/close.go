@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+
+	"sdk/programs/favorite"
+	"sdk/txbuilder"
+)
+
+// runClose 关闭签名者的 favorites 账户（或某个 profile 账户），租金退回给用户
+func runClose(args []string) error {
+	fs := flag.NewFlagSet("close", flag.ExitOnError)
+	profile := fs.String("profile", "", "named profile to close; empty closes the default [\"favorites\", user] account")
+	endpoint := fs.String("rpc", defaultEndpoint, "RPC endpoint")
+	lookupTables := fs.String("lookup-table", "", "comma-separated address lookup tables (sends a v0 transaction)")
+	_ = fs.Parse(args)
+
+	signer, err := loadAccountFromFile(os.ExpandEnv("$HOME/.config/solana/id.json"))
+	if err != nil {
+		return fmt.Errorf("failed to load signer: %w", err)
+	}
+	pda, err := profilePDA(signer.PublicKey, *profile)
+	if err != nil {
+		return err
+	}
+
+	c := client.NewClient(*endpoint)
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// 不存在或已关闭的账户 owner 不是 favorite 程序
+	info, err := c.GetAccountInfo(ctx, pda.ToBase58())
+	if err != nil {
+		return fmt.Errorf("failed to get favorites account: %w", err)
+	}
+	if info.Owner != favorite.ProgramID {
+		return errors.New("favorites account does not exist or is already closed")
+	}
+
+	ix := favorite.NewCloseInstruction(
+		favorite.CloseAccounts{
+			User:      signer.PublicKey,
+			Favorites: pda,
+		},
+		favorite.CloseArgs{},
+	)
+	if *profile != "" {
+		ix = favorite.NewCloseProfileInstruction(
+			favorite.CloseProfileAccounts{
+				User:             signer.PublicKey,
+				ProfileFavorites: pda,
+			},
+			favorite.CloseProfileArgs{Profile: *profile},
+		)
+	}
+	tables, err := fetchLookupTables(ctx, c, *lookupTables)
+	if err != nil {
+		return fmt.Errorf("failed to load lookup tables: %w", err)
+	}
+	sig, err := txbuilder.New(c).FeePayer(signer).Add(ix).LookupTables(tables...).SendAndConfirm(ctx)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]any{
+		"signature": sig,
+		"user":      signer.PublicKey.ToBase58(),
+		"profile":   *profile,
+		"favorites": pda.ToBase58(),
+		"reclaimed": info.Lamports,
+	})
+}
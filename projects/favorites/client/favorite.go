@@ -2,9 +2,9 @@ package main
 
 import (
     "context"
-    "crypto/sha256"
-    "encoding/binary"
     "encoding/json"
+    "errors"
+    "flag"
     "fmt"
     "os"
     "strings"
@@ -13,15 +13,42 @@ import (
     "github.com/blocto/solana-go-sdk/client"
     "github.com/blocto/solana-go-sdk/common"
     "github.com/blocto/solana-go-sdk/types"
-)
 
-// 已部署 Anchor 程序的 Program ID（与 declare_id! 一致）
-const favoriteProgramID = "AdUTQjW9iWgWwjsr7n5RjVLjt1VGNtBSviJQtk18ESxQ"
+    "sdk/anchor"
+    "sdk/programs/favorite"
+    "sdk/txbuilder"
+)
 
 // 默认使用 Devnet，如需切换可改为本地或主网 RPC
 const defaultEndpoint = "https://api.devnet.solana.com"
 
 func main() {
+    // history 子命令：从事件日志重建某个 profile 的历史值
+    if len(os.Args) > 1 && os.Args[1] == "history" {
+        if err := runHistory(os.Args[2:]); err != nil {
+            fmt.Printf("history failed: %v\n", err)
+            os.Exit(1)
+        }
+        return
+    }
+    // close 子命令：关闭 favorites / profile 账户并取回租金
+    if len(os.Args) > 1 && os.Args[1] == "close" {
+        if err := runClose(os.Args[2:]); err != nil {
+            printTxError("close failed", err)
+            os.Exit(1)
+        }
+        return
+    }
+
+    fs := flag.NewFlagSet("favorite", flag.ExitOnError)
+    profile := fs.String("profile", "", "named profile; empty writes the default [\"favorites\", user] account")
+    number := fs.Uint64("number", 42, "favorite number")
+    color := fs.String("color", "blue", "favorite color (at most 10 characters)")
+    hobbies := fs.String("hobbies", "reading,coding", "comma-separated hobbies")
+    endpoint := fs.String("rpc", defaultEndpoint, "RPC endpoint")
+    lookupTables := fs.String("lookup-table", "", "comma-separated address lookup tables (sends a v0 transaction)")
+    _ = fs.Parse(os.Args[1:])
+
     // 加载签名者：~/.config/solana/id.json
     signer, err := loadAccountFromFile(os.ExpandEnv("$HOME/.config/solana/id.json"))
     if err != nil {
@@ -29,7 +56,7 @@ func main() {
         return
     }
 
-    c := client.NewClient(defaultEndpoint)
+    c := client.NewClient(*endpoint)
     ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
     defer cancel()
 
@@ -39,113 +66,127 @@ func main() {
         // 不中断主流程
     }
 
-    // 获取最新区块哈希
-    latest, err := c.GetLatestBlockhash(ctx)
+    ix, pda, err := favoritesInstruction(signer.PublicKey, *profile, *number, *color, splitList(*hobbies))
     if err != nil {
-        fmt.Printf("failed to get latest blockhash: %v\n", err)
+        fmt.Printf("failed to build instruction: %v\n", err)
         return
     }
-    recent := latest.Blockhash
 
-    // 派生 PDA：seeds = ["favorites", user]
-    programID := common.PublicKeyFromString(favoriteProgramID)
-    // 注意：blocto SDK 的 FindProgramAddress 接收 [][]byte 作为 seeds
-    // PublicKey.Bytes() 提供原始 32 字节公钥
-    favoritesPDA, _, err := common.FindProgramAddress(
-        [][]byte{[]byte("favorites"), signer.PublicKey.Bytes()},
-        programID,
-    )
+    // 给出查找表时编译为 v0 消息，账户按表内索引引用
+    tables, err := fetchLookupTables(ctx, c, *lookupTables)
     if err != nil {
-        fmt.Printf("failed to find PDA: %v\n", err)
+        fmt.Printf("failed to load lookup tables: %v\n", err)
         return
     }
 
-    // 构造 initialize 指令数据（Anchor: 8 字节 discriminator + Borsh 编码参数）
-    ixData := encodeInitialize(42, "blue", []string{"reading", "coding"})
-
-    // 构建指令账户列表：顺序需与 SetFavorite 定义一致
-    metas := []types.AccountMeta{
-        {PubKey: signer.PublicKey, IsSigner: true, IsWritable: true},
-        {PubKey: favoritesPDA, IsSigner: false, IsWritable: true},
-        // system_program
-        {PubKey: common.PublicKeyFromString("11111111111111111111111111111111"), IsSigner: false, IsWritable: false},
+    // 构建、签名、发送并等待确认；blockhash 由 txbuilder 获取
+    sig, err := txbuilder.New(c).FeePayer(signer).Add(ix).LookupTables(tables...).SendAndConfirm(ctx)
+    if err != nil {
+        // "custom program error: 0x..." 已解码为带名称与描述的程序错误
+        printTxError("failed to send tx", err)
+        return
     }
+    fmt.Println("favorites account:", pda.ToBase58())
+    fmt.Println("initialize tx signature:", sig)
+}
 
-    ix := types.Instruction{
-        ProgramID: programID,
-        Accounts:  metas,
-        Data:      ixData,
+// favoritesInstruction 构建写入指令：profile 为空时走默认账户的 initialize，
+// 否则走 set_profile；两者都会发出 FavoritesUpdated 事件
+func favoritesInstruction(user common.PublicKey, profile string, number uint64, color string, hobbies []string) (types.Instruction, common.PublicKey, error) {
+    if profile == "" {
+        // 派生 PDA：seeds = ["favorites", user]
+        pda, _, err := favorite.FindFavoritesAddress(user)
+        if err != nil {
+            return types.Instruction{}, common.PublicKey{}, fmt.Errorf("failed to find PDA: %w", err)
+        }
+        // initialize 指令：8 字节 discriminator + Borsh 编码参数，账户顺序与 SetFavorite 一致
+        return favorite.NewInitializeInstruction(
+            favorite.InitializeAccounts{
+                User:      user,
+                Favorites: pda,
+            },
+            favorite.InitializeArgs{
+                Number:  number,
+                Color:   color,
+                Hobbies: hobbies,
+            },
+        ), pda, nil
     }
 
-    // 构建并签名交易
-    msg := types.NewMessage(types.NewMessageParam{
-        FeePayer:        signer.PublicKey,
-        RecentBlockhash: recent,
-        Instructions:    []types.Instruction{ix},
-    })
-
-    tx, err := types.NewTransaction(types.NewTransactionParam{
-        Message: msg,
-        Signers: []types.Account{signer},
-    })
+    // 派生 PDA：seeds = ["favorites", user, profile]
+    pda, err := profilePDA(user, profile)
     if err != nil {
-        fmt.Printf("failed to build tx: %v\n", err)
-        return
+        return types.Instruction{}, common.PublicKey{}, err
     }
+    return favorite.NewSetProfileInstruction(
+        favorite.SetProfileAccounts{
+            User:             user,
+            ProfileFavorites: pda,
+        },
+        favorite.SetProfileArgs{
+            Profile: profile,
+            Number:  number,
+            Color:   color,
+            Hobbies: hobbies,
+        },
+    ), pda, nil
+}
 
-    sig, err := c.SendTransaction(ctx, tx)
+// profilePDA 派生 profile 账户地址；空 profile 对应默认账户。
+// profile 过长时返回校验错误，派生失败时返回 "failed to find PDA" 错误
+func profilePDA(user common.PublicKey, profile string) (common.PublicKey, error) {
+    if profile == "" {
+        pda, _, err := favorite.FindFavoritesAddress(user)
+        if err != nil {
+            return common.PublicKey{}, fmt.Errorf("failed to find PDA: %w", err)
+        }
+        return pda, nil
+    }
+    // 单个 seed 最长 32 字节，与链上 MAX_PROFILE_LEN 一致
+    if len(profile) > 32 {
+        return common.PublicKey{}, fmt.Errorf("profile %q is longer than 32 bytes", profile)
+    }
+    pda, _, err := favorite.FindProfileFavoritesAddress(user, profile)
     if err != nil {
-        fmt.Printf("failed to send tx: %v\n", err)
-        return
+        return common.PublicKey{}, fmt.Errorf("failed to find PDA: %w", err)
     }
-    fmt.Println("initialize tx signature:", sig)
-}
-
-// encodeInitialize 生成 Anchor 指令数据：
-// [8字节 discriminator("global:initialize")] [u64 number] [borsh string color] [borsh Vec<String> hobbies]
-func encodeInitialize(number uint64, color string, hobbies []string) []byte {
-    disc := anchorDiscriminator("initialize")
-    // u64（LE）
-    num := make([]byte, 8)
-    binary.LittleEndian.PutUint64(num, number)
-    // color（Borsh string）
-    colorB := borshEncodeString(color)
-    // hobbies（Borsh Vec<String>）
-    hobbiesB := borshEncodeStringVec(hobbies)
-    out := make([]byte, 0, len(disc)+len(num)+len(colorB)+len(hobbiesB))
-    out = append(out, disc...)
-    out = append(out, num...)
-    out = append(out, colorB...)
-    out = append(out, hobbiesB...)
-    return out
+    return pda, nil
 }
 
-// anchorDiscriminator 计算 8 字节 SIGHASH("global:<name>")
-func anchorDiscriminator(name string) []byte {
-    s := "global:" + strings.TrimSpace(name)
-    h := sha256.Sum256([]byte(s))
-    // 取前 8 字节
-    return h[:8]
+// fetchLookupTables 读取逗号分隔的查找表地址对应的链上账户
+func fetchLookupTables(ctx context.Context, c *client.Client, list string) ([]types.AddressLookupTableAccount, error) {
+    var keys []common.PublicKey
+    for _, s := range splitList(list) {
+        pk := common.PublicKeyFromString(s)
+        if pk.ToBase58() != s {
+            return nil, fmt.Errorf("invalid lookup table address %q", s)
+        }
+        keys = append(keys, pk)
+    }
+    return txbuilder.FetchLookupTableAccounts(ctx, c, keys...)
 }
 
-// Borsh: string = u32(len) + utf8 bytes
-func borshEncodeString(s string) []byte {
-    bs := []byte(s)
-    out := make([]byte, 4+len(bs))
-    binary.LittleEndian.PutUint32(out[:4], uint32(len(bs)))
-    copy(out[4:], bs)
-    return out
+// splitList 拆分逗号分隔的参数，去掉空白与空项；结果非 nil，空输入得到空切片
+func splitList(s string) []string {
+    items := []string{}
+    for _, item := range strings.Split(s, ",") {
+        if item = strings.TrimSpace(item); item != "" {
+            items = append(items, item)
+        }
+    }
+    return items
 }
 
-// Borsh: Vec<String> = u32(len) + each string
-func borshEncodeStringVec(v []string) []byte {
-    out := make([]byte, 4)
-    binary.LittleEndian.PutUint32(out[:4], uint32(len(v)))
-    for _, s := range v {
-        sb := borshEncodeString(s)
-        out = append(out, sb...)
+// printTxError 打印交易错误；可解码的程序错误以 JSON 输出到 stderr，便于脚本处理
+func printTxError(prefix string, err error) {
+    var pe *anchor.ProgramError
+    if errors.As(err, &pe) {
+        enc := json.NewEncoder(os.Stderr)
+        enc.SetIndent("", "  ")
+        _ = enc.Encode(map[string]any{"error": prefix, "programError": pe})
+        return
     }
-    return out
+    fmt.Printf("%s: %v\n", prefix, err)
 }
 
 // ensureAirdropIfLow: devnet 余额低于阈值时尝试空投
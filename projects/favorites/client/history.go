@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+
+	"sdk/anchor"
+	"sdk/programs/favorite"
+)
+
+// historyEntry 是一次写入后的值，来自 FavoritesUpdated 事件
+type historyEntry struct {
+	Signature string   `json:"signature"`
+	Slot      uint64   `json:"slot"`
+	UpdatedAt int64    `json:"updatedAt"`
+	Number    uint64   `json:"number"`
+	Color     string   `json:"color"`
+	Hobbies   []string `json:"hobbies"`
+}
+
+// runHistory 列出 profile 账户的全部交易签名，逐笔读取日志并解码
+// FavoritesUpdated 事件，按时间从旧到新输出历史值。账户被 update 覆盖后，
+// 旧值只保留在这些事件中。
+func runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	profile := fs.String("profile", "", "named profile; empty reads the default account")
+	userFlag := fs.String("user", "", "owner address (default: ~/.config/solana/id.json)")
+	limit := fs.Int("limit", 100, "maximum number of transactions to scan")
+	endpoint := fs.String("rpc", defaultEndpoint, "RPC endpoint")
+	_ = fs.Parse(args)
+
+	var user common.PublicKey
+	if *userFlag != "" {
+		user = common.PublicKeyFromString(*userFlag)
+	} else {
+		signer, err := loadAccountFromFile(os.ExpandEnv("$HOME/.config/solana/id.json"))
+		if err != nil {
+			return fmt.Errorf("failed to load signer: %w", err)
+		}
+		user = signer.PublicKey
+	}
+	pda, err := profilePDA(user, *profile)
+	if err != nil {
+		return err
+	}
+
+	c := client.NewClient(*endpoint)
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sigs, err := accountSignatures(ctx, c, pda, *limit)
+	if err != nil {
+		return err
+	}
+	entries := []historyEntry{}
+	// getSignaturesForAddress 从新到旧返回，倒序遍历得到时间顺序
+	for i := len(sigs) - 1; i >= 0; i-- {
+		s := sigs[i]
+		if s.Err != nil {
+			continue
+		}
+		tx, err := c.GetTransactionWithConfig(ctx, s.Signature, client.GetTransactionConfig{Commitment: rpc.CommitmentConfirmed})
+		if err != nil {
+			return fmt.Errorf("failed to get transaction %s: %w", s.Signature, err)
+		}
+		if tx == nil || tx.Meta == nil {
+			continue
+		}
+		for _, data := range anchor.ProgramEvents(tx.Meta.LogMessages, favorite.ProgramID) {
+			name, v, err := favorite.DecodeEvent(data)
+			if err != nil || name != "FavoritesUpdated" {
+				continue
+			}
+			ev := v.(*favorite.FavoritesUpdated)
+			// 同一交易可能写入多个 profile，只保留目标账户的事件
+			if ev.User != user || ev.Profile != *profile {
+				continue
+			}
+			entries = append(entries, historyEntry{
+				Signature: s.Signature,
+				Slot:      s.Slot,
+				UpdatedAt: ev.UpdatedAt,
+				Number:    ev.Number,
+				Color:     ev.Color,
+				Hobbies:   ev.Hobbies,
+			})
+		}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]any{
+		"user":      user.ToBase58(),
+		"profile":   *profile,
+		"favorites": pda.ToBase58(),
+		"history":   entries,
+	})
+}
+
+// accountSignatures 分页拉取涉及 addr 的最近 limit 笔交易签名（从新到旧）
+func accountSignatures(ctx context.Context, c *client.Client, addr common.PublicKey, limit int) (rpc.GetSignaturesForAddress, error) {
+	var out rpc.GetSignaturesForAddress
+	before := ""
+	for len(out) < limit {
+		page, err := c.GetSignaturesForAddressWithConfig(ctx, addr.ToBase58(), client.GetSignaturesForAddressConfig{
+			Limit:      min(limit-len(out), 1000),
+			Before:     before,
+			Commitment: rpc.CommitmentConfirmed,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get signatures: %w", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		out = append(out, page...)
+		before = page[len(page)-1].Signature
+	}
+	return out, nil
+}
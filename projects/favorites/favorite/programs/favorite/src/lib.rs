@@ -9,10 +9,17 @@ declare_id!("AdUTQjW9iWgWwjsr7n5RjVLjt1VGNtBSviJQtk18ESxQ");
 // The discriminator is a fixed prefix set by Anchor to identify the account type.
 pub const ANCHOR_DISCRIMINATOR_SIZE: usize = 8;
 
+// A single PDA seed is at most 32 bytes, which bounds the profile name.
+pub const MAX_PROFILE_LEN: usize = 32;
+
 /// Anchor program that stores a user's favorite number, color and hobbies
 /// in a PDA (Program Derived Address) account. The PDA is derived from a
 /// static seed ("favorites") and the user's public key. The `initialize`
 /// instruction creates the PDA if it doesn't exist, or updates it otherwise.
+///
+/// Users can also keep named profiles at `["favorites", user, profile]`.
+/// Every write emits a `FavoritesUpdated` event, so the transaction logs form
+/// an append-only history of past values.
 #[program]
 pub mod favorite {
     use super::*;
@@ -40,31 +47,118 @@ pub mod favorite {
             "User {public_id}'s favorite number is {number}, 
         favorite color is: {color}"
         );
-        ctx.accounts.favorites.set_inner(Favorite {
+        write_favorites(
+            &mut ctx.accounts.favorites,
+            ctx.accounts.user.key(),
+            String::new(),
             number,
             color,
             hobbies,
-        });
-        Ok(())
+        )
     }
 
+    /// Overwrites the user's default favorites; the previous values remain
+    /// in the `FavoritesUpdated` event of the earlier write.
     pub fn update(
         ctx: Context<SetFavorite>,
         number: u64,
         color: String,
         hobbies: Vec<String>,
     ) -> Result<()> {
+        write_favorites(
+            &mut ctx.accounts.favorites,
+            ctx.accounts.user.key(),
+            String::new(),
+            number,
+            color,
+            hobbies,
+        )
+    }
 
-        ctx.accounts.favorites.set_inner(Favorite {
+    /// Creates or updates one of the user's named profiles.
+    ///
+    /// - `profile`: Profile name, 1 to 32 bytes; part of the PDA seeds
+    ///   `[b"favorites", user.key().as_ref(), profile.as_bytes()]`.
+    /// - `number`, `color`, `hobbies`: Same as `initialize`.
+    pub fn set_profile(
+        ctx: Context<SetProfile>,
+        profile: String,
+        number: u64,
+        color: String,
+        hobbies: Vec<String>,
+    ) -> Result<()> {
+        check_profile(&profile)?;
+        write_favorites(
+            &mut ctx.accounts.profile_favorites,
+            ctx.accounts.user.key(),
+            profile,
             number,
             color,
             hobbies,
-        });
+        )
+    }
 
+    /// Closes the user's favorites account and returns its rent to the user.
+    ///
+    /// - `ctx`: Account context, containing the signer (`user`) and the PDA (`favorites`).
+    ///
+    /// Behavior:
+    /// - Only the user whose key seeds the PDA can close it.
+    /// - Anchor's `close = user` constraint zeroes the account data and moves all
+    ///   lamports to `user`; a later `initialize` recreates the account from scratch.
+    pub fn close(ctx: Context<CloseFavorite>) -> Result<()> {
+        msg!("Closing favorites of: {}", ctx.accounts.user.key());
+        Ok(())
+    }
+
+    /// Closes one of the user's named profiles and returns its rent to the user.
+    /// An empty name is rejected, so this never closes the default account.
+    pub fn close_profile(ctx: Context<CloseProfile>, profile: String) -> Result<()> {
+        check_profile(&profile)?;
+        msg!("Closing profile {} of: {}", profile, ctx.accounts.user.key());
         Ok(())
     }
 }
 
+/// Rejects profile names that are empty or longer than `MAX_PROFILE_LEN`.
+///
+/// An empty name derives the default `["favorites", user]` PDA, so it has to
+/// be refused here rather than by the seeds. A name over 32 bytes already
+/// fails PDA derivation before the handler runs; the length check keeps the
+/// rule explicit should the seeds ever change.
+fn check_profile(profile: &str) -> Result<()> {
+    require!(!profile.is_empty(), FavoriteError::EmptyProfile);
+    require!(profile.len() <= MAX_PROFILE_LEN, FavoriteError::ProfileTooLong);
+    Ok(())
+}
+
+/// Stores the new values and emits them as a `FavoritesUpdated` event.
+/// `profile` is empty for the default `["favorites", user]` account.
+fn write_favorites(
+    favorites: &mut Account<Favorite>,
+    user: Pubkey,
+    profile: String,
+    number: u64,
+    color: String,
+    hobbies: Vec<String>,
+) -> Result<()> {
+    favorites.set_inner(Favorite {
+        user,
+        number,
+        color: color.clone(),
+        hobbies: hobbies.clone(),
+    });
+    emit!(FavoritesUpdated {
+        user,
+        profile,
+        number,
+        color,
+        hobbies,
+        updated_at: Clock::get()?.unix_timestamp,
+    });
+    Ok(())
+}
+
 /// On-chain account that stores the user's preferences.
 ///
 /// The `InitSpace` derive macro calculates the static space required for this
@@ -74,6 +168,10 @@ pub mod favorite {
 #[account]
 #[derive(InitSpace)]
 pub struct Favorite {
+    /// Owner of the account. Kept first, at offset 8, so a memcmp filter
+    /// finds every default and named favorites account of a user.
+    pub user: Pubkey,
+
     /// Favorite number (64-bit unsigned integer).
     pub number: u64,
 
@@ -112,3 +210,89 @@ pub struct SetFavorite<'info> {
 
     pub system_program: Program<'info, System>,
 }
+
+/// Account context for the `set_profile` instruction.
+///
+/// - `user`: The transaction signer and payer.
+/// - `profile_favorites`: PDA of the named profile. Created if missing.
+/// - `system_program`: Required for account creation and rent payments.
+#[derive(Accounts)]
+#[instruction(profile: String)]
+pub struct SetProfile<'info> {
+    #[account(mut)]
+    pub user: Signer<'info>,
+
+    #[account(
+        init_if_needed,
+        payer = user,
+        space = ANCHOR_DISCRIMINATOR_SIZE + Favorite::INIT_SPACE,
+        // PDA seeds: static label + user's public key + profile name
+        seeds = [b"favorites", user.key().as_ref(), profile.as_bytes()],
+        bump
+    )]
+    pub profile_favorites: Account<'info, Favorite>,
+
+    pub system_program: Program<'info, System>,
+}
+
+/// Account context for the `close_profile` instruction.
+///
+/// - `user`: The transaction signer; receives the reclaimed rent.
+/// - `profile_favorites`: Profile PDA to close. Must be derived from `user`.
+#[derive(Accounts)]
+#[instruction(profile: String)]
+pub struct CloseProfile<'info> {
+    #[account(mut)]
+    pub user: Signer<'info>,
+
+    #[account(
+        mut,
+        close = user,
+        seeds = [b"favorites", user.key().as_ref(), profile.as_bytes()],
+        bump
+    )]
+    pub profile_favorites: Account<'info, Favorite>,
+}
+
+/// Account context for the `close` instruction.
+///
+/// - `user`: The transaction signer; receives the reclaimed rent.
+/// - `favorites`: PDA to close. Must be derived from `user`.
+#[derive(Accounts)]
+pub struct CloseFavorite<'info> {
+    #[account(mut)]
+    pub user: Signer<'info>,
+
+    #[account(
+        mut,
+        // Transfer all lamports to the user and mark the account as closed.
+        close = user,
+        seeds = [b"favorites", user.key().as_ref()],
+        bump
+    )]
+    pub favorites: Account<'info, Favorite>,
+}
+
+/// Emitted on every write to a favorites account. Replaying these events in
+/// order reconstructs the history of a user's default favorites or profile.
+#[event]
+pub struct FavoritesUpdated {
+    /// Owner of the favorites account.
+    pub user: Pubkey,
+    /// Profile name; empty for the default favorites account.
+    pub profile: String,
+    pub number: u64,
+    pub color: String,
+    pub hobbies: Vec<String>,
+    /// Cluster unix timestamp of the write.
+    pub updated_at: i64,
+}
+
+#[error_code]
+pub enum FavoriteError {
+    #[msg("Profile name must not be empty")]
+    EmptyProfile,
+
+    #[msg("Profile name is longer than 32 bytes")]
+    ProfileTooLong,
+}
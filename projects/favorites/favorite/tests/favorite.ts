@@ -63,4 +63,123 @@ describe("favorite", () => {
     // And check the hobbies too
     assert.deepEqual(dataFromPad.hobbies, hobbies);
   })
+
+  it("is closed!", async () => {
+    const userPubkey = provider.wallet.publicKey;
+    const [favoritesPda] = anchor.web3.PublicKey.findProgramAddressSync(
+      [Buffer.from('favorites'), userPubkey.toBuffer()],
+      program.programId);
+    const rent = await provider.connection.getBalance(favoritesPda);
+
+    const tx = await program.methods
+      .close()
+      .accounts({
+        user: userPubkey,
+      })
+      .rpc();
+    console.log("Your transaction signature", tx);
+
+    // The PDA is gone and its rent went back to the user
+    const info = await provider.connection.getAccountInfo(favoritesPda);
+    assert.isNull(info);
+    assert.isAbove(rent, 0);
+  })
+
+  it("keeps named profiles and records every write as an event", async () => {
+    const userPubkey = provider.wallet.publicKey;
+    const profile = "work";
+    const [profilePda] = anchor.web3.PublicKey.findProgramAddressSync(
+      [Buffer.from('favorites'), userPubkey.toBuffer(), Buffer.from(profile)],
+      program.programId);
+
+    const events = [];
+    const listener = program.addEventListener("favoritesUpdated", (event) => events.push(event));
+    for (const c of ["green", "black"]) {
+      await program.methods
+        .setProfile(profile, number, c, hobbies)
+        .accounts({
+          user: userPubkey,
+        })
+        .rpc({ commitment: "confirmed" });
+    }
+    await new Promise((r) => setTimeout(r, 1000));
+    await program.removeEventListener(listener);
+
+    // The account only keeps the latest value; the events keep both
+    const data = await program.account.favorite.fetch(profilePda);
+    assert.equal(data.color, "black");
+    assert.isTrue(data.user.equals(userPubkey));
+
+    // The stored user finds the profile without knowing its name
+    const owned = await program.account.favorite.all([
+      { memcmp: { offset: 8, bytes: userPubkey.toBase58() } },
+    ]);
+    assert.isTrue(owned.some((a) => a.publicKey.equals(profilePda)));
+    assert.deepEqual(events.map((e) => e.color), ["green", "black"]);
+    assert.equal(events[0].profile, profile);
+
+    await program.methods
+      .closeProfile(profile)
+      .accounts({
+        user: userPubkey,
+      })
+      .rpc();
+    assert.isNull(await provider.connection.getAccountInfo(profilePda));
+  })
+
+  it("rejects an empty profile name instead of using the default account", async () => {
+    const userPubkey = provider.wallet.publicKey;
+    const [favoritesPda] = anchor.web3.PublicKey.findProgramAddressSync(
+      [Buffer.from('favorites'), userPubkey.toBuffer()],
+      program.programId);
+    await program.methods
+      .update(number, color, hobbies)
+      .accounts({
+        user: userPubkey,
+      })
+      .rpc();
+
+    // An empty seed derives the default PDA, so both calls target it
+    for (const call of [
+      program.methods.setProfile("", number, "blue", hobbies),
+      program.methods.closeProfile(""),
+    ]) {
+      try {
+        await call.accounts({ user: userPubkey }).rpc();
+        assert.fail("an empty profile name was accepted");
+      } catch (err) {
+        assert.equal(err.error?.errorCode?.code, "EmptyProfile");
+      }
+    }
+    const data = await program.account.favorite.fetch(favoritesPda);
+    assert.equal(data.color, color);
+
+    await program.methods.close().accounts({ user: userPubkey }).rpc();
+  })
+
+  it("accepts a 32 byte profile name and rejects 33 bytes", async () => {
+    const userPubkey = provider.wallet.publicKey;
+    const longest = "p".repeat(32);
+    await program.methods
+      .setProfile(longest, number, color, hobbies)
+      .accounts({
+        user: userPubkey,
+      })
+      .rpc();
+    await program.methods.closeProfile(longest).accounts({ user: userPubkey }).rpc();
+
+    // A 33 byte seed cannot derive a PDA, so the call fails before it is sent
+    const tooLong = "p".repeat(33);
+    try {
+      await program.methods
+        .setProfile(tooLong, number, color, hobbies)
+        .accounts({
+          user: userPubkey,
+        })
+        .rpc();
+      assert.fail("a 33 byte profile name was accepted");
+    } catch (err) {
+      assert.match(String(err), /Max seed length exceeded/);
+    }
+  })
 });
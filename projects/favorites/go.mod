@@ -6,5 +6,10 @@ require github.com/blocto/solana-go-sdk v1.30.0
 
 require (
 	filippo.io/edwards25519 v1.0.0-rc.1 // indirect
+	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/mr-tron/base58 v1.2.0 // indirect
 )
+
+require sdk v0.0.0
+
+replace sdk => ../sdk
@@ -0,0 +1,20 @@
+module indexer
+
+go 1.23.4
+
+require (
+	github.com/blocto/solana-go-sdk v1.30.0
+	github.com/mattn/go-sqlite3 v1.14.22
+	orm_demo v0.0.0
+	sdk v0.0.0
+)
+
+require (
+	filippo.io/edwards25519 v1.0.0-rc.1 // indirect
+	github.com/gorilla/websocket v1.5.3 // indirect
+	github.com/mr-tron/base58 v1.2.0 // indirect
+)
+
+replace sdk => ../sdk
+
+replace orm_demo => ../../grammar/go
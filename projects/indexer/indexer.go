@@ -0,0 +1,414 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+
+	"orm_demo/orm"
+	"sdk/anchor"
+	"sdk/programs/favorite"
+	"sdk/programs/voting"
+	"sdk/ws"
+)
+
+// program describes one indexed Anchor program: its id and the account
+// discriminators fetched during a full sync (in order).
+type program struct {
+	name           string
+	id             common.PublicKey
+	discriminators []anchor.Discriminator
+}
+
+var programs = map[string]program{
+	"favorite": {"favorite", favorite.ProgramID, []anchor.Discriminator{favorite.FavoriteDiscriminator}},
+	// Polls first so candidates can be attributed to a recovered poll id.
+	"voting": {"voting", voting.ProgramID, []anchor.Discriminator{voting.PollDiscriminator, voting.CandidateAccountDiscriminator}},
+}
+
+type indexer struct {
+	db        *orm.ORM
+	rpc       *client.Client
+	programs  []program
+	maxPollID uint64
+
+	// pollIDs maps poll PDAs to ids for 0..maxPollID, built on first use.
+	pollIDs map[common.PublicKey]uint64
+	// polls are the poll ids seen so far, used to attribute candidates.
+	polls map[uint64]common.PublicKey
+}
+
+func newIndexer(db *orm.ORM, c *client.Client, progs []program, maxPollID uint64) *indexer {
+	return &indexer{
+		db:        db,
+		rpc:       c,
+		programs:  progs,
+		maxPollID: maxPollID,
+		polls:     map[uint64]common.PublicKey{},
+	}
+}
+
+func (ix *indexer) migrate() error {
+	for _, m := range []any{&PollRecord{}, &CandidateRecord{}, &FavoriteRecord{}} {
+		if err := ix.db.CreateTable(m); err != nil {
+			return fmt.Errorf("create table: %w", err)
+		}
+		if err := ix.addMissingColumns(m); err != nil {
+			return fmt.Errorf("migrate: %w", err)
+		}
+	}
+	return nil
+}
+
+// addMissingColumns adds columns introduced after a database was created
+// (e.g. the poll authority and mode); the next sync fills them in. A column
+// whose declared type changed (e.g. candidate votes, now text so u64 totals
+// fit) cannot be altered in SQLite, so the table is recreated empty instead.
+func (ix *indexer) addMissingColumns(model any) error {
+	info, err := orm.ParseModel(model)
+	if err != nil {
+		return err
+	}
+	db := ix.db.Pool().GetConnection()
+	rows, err := db.Query("select name, type from pragma_table_info(?)", info.TableName)
+	if err != nil {
+		return err
+	}
+	existing := map[string]string{}
+	for rows.Next() {
+		var name, typ string
+		if err := rows.Scan(&name, &typ); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[name] = typ
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, f := range info.Fields {
+		typ, ok := existing[f.DBName]
+		if ok && f.DBType != "" && !strings.EqualFold(typ, f.DBType) {
+			log.Printf("migrate: %s.%s changed from %s to %s; recreating %s", info.TableName, f.DBName, typ, f.DBType, info.TableName)
+			if _, err := db.Exec("drop table " + info.TableName); err != nil {
+				return err
+			}
+			return ix.db.CreateTable(model)
+		}
+	}
+	for _, f := range info.Fields {
+		if _, ok := existing[f.DBName]; ok {
+			continue
+		}
+		// Defaults keep rows written before the migration scannable until resynced.
+		col := f.DBName + " " + f.DBType
+		switch f.Type.Kind() {
+		case reflect.Bool:
+			col += " default 0"
+		case reflect.String:
+			col += " default ''"
+		}
+		if _, err := db.Exec(fmt.Sprintf("alter table %s add column %s", info.TableName, col)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncAll runs getProgramAccounts with a discriminator memcmp filter for every
+// account type and upserts the results. Rows of a program whose accounts are
+// missing from the snapshot were closed while the indexer was not watching and
+// are deleted. It returns the number of rows per table.
+func (ix *indexer) syncAll(ctx context.Context) (map[string]int, error) {
+	counts := map[string]int{}
+	for _, p := range ix.programs {
+		seen := map[string]bool{}
+		for _, d := range p.discriminators {
+			accounts, err := anchor.FetchProgramAccounts(ctx, ix.rpc, p.id, anchor.DiscriminatorFilter(d))
+			if err != nil {
+				return counts, fmt.Errorf("%s: %w", p.name, err)
+			}
+			for _, acc := range accounts {
+				// Undecodable accounts still exist; their old rows are kept.
+				seen[acc.Pubkey.ToBase58()] = true
+				table, err := ix.apply(p, acc.Pubkey, acc.Lamports, acc.Owner, acc.Data)
+				if err != nil {
+					log.Printf("skip %s account %s: %v", p.name, acc.Pubkey.ToBase58(), err)
+					continue
+				}
+				counts[table]++
+			}
+		}
+		if err := ix.prune(p, seen); err != nil {
+			return counts, fmt.Errorf("%s: %w", p.name, err)
+		}
+	}
+	return counts, nil
+}
+
+// prune deletes the rows of p whose address is not in seen.
+func (ix *indexer) prune(p program, seen map[string]bool) error {
+	addrs, err := ix.addresses(p)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if seen[addr] {
+			continue
+		}
+		if _, err := ix.remove(p, addr); err != nil {
+			return err
+		}
+		log.Printf("removed closed %s account %s", p.name, addr)
+	}
+	return nil
+}
+
+// addresses lists the addresses stored for p.
+func (ix *indexer) addresses(p program) ([]string, error) {
+	var out []string
+	switch p.name {
+	case "favorite":
+		var rows []FavoriteRecord
+		if err := ix.db.Find(&rows); err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			out = append(out, r.Address)
+		}
+	case "voting":
+		var polls []PollRecord
+		if err := ix.db.Find(&polls); err != nil {
+			return nil, err
+		}
+		for _, r := range polls {
+			out = append(out, r.Address)
+		}
+		var candidates []CandidateRecord
+		if err := ix.db.Find(&candidates); err != nil {
+			return nil, err
+		}
+		for _, r := range candidates {
+			out = append(out, r.Address)
+		}
+	default:
+		return nil, fmt.Errorf("unknown program %s", p.name)
+	}
+	return out, nil
+}
+
+// apply decodes one account and upserts it, or deletes it when the account was
+// closed (no data or no longer owned by the program). It returns the table touched.
+func (ix *indexer) apply(p program, pubkey common.PublicKey, lamports uint64, owner common.PublicKey, data []byte) (string, error) {
+	now := time.Now().UTC()
+	addr := pubkey.ToBase58()
+	if len(data) == 0 || owner != p.id {
+		return ix.remove(p, addr)
+	}
+	switch p.name {
+	case "favorite":
+		name, v, err := favorite.DecodeAccount(data)
+		if err != nil {
+			return "", err
+		}
+		if name != "Favorite" {
+			return "", fmt.Errorf("unexpected account type %s", name)
+		}
+		fav := v.(*favorite.Favorite)
+		hobbies, _ := json.Marshal(fav.Hobbies)
+		return "favorites", ix.db.Save(&FavoriteRecord{
+			Address:   addr,
+			User:      fav.User.ToBase58(),
+			Number:    strconv.FormatUint(fav.Number, 10),
+			Color:     fav.Color,
+			Hobbies:   string(hobbies),
+			Lamports:  int64(lamports),
+			UpdatedAt: now,
+		})
+	case "voting":
+		name, v, err := voting.DecodeAccount(data)
+		if err != nil {
+			return "", err
+		}
+		switch acc := v.(type) {
+		case *voting.Poll:
+			rec := &PollRecord{
+				Address:        addr,
+				Name:           acc.PollName,
+				Description:    acc.PollDesc,
+				VoteStart:      int64(acc.PollVoteStart),
+				VoteEnd:        int64(acc.PollVoteEnd),
+				CandidateCount: int64(acc.PollVoteIndex),
+				Authority:      acc.Authority.ToBase58(),
+				Finalized:      acc.Finalized,
+				Winner:         acc.Winner,
+				WinnerVotes:    strconv.FormatUint(acc.WinnerVotes, 10),
+				Mode:           acc.Mode.Kind.String(),
+				Lamports:       int64(lamports),
+				UpdatedAt:      now,
+			}
+			switch {
+			case acc.Mode.TokenWeighted != nil:
+				rec.Mint = acc.Mode.TokenWeighted.Mint.ToBase58()
+			case acc.Mode.MerkleAllowlist != nil:
+				rec.AllowlistRoot = hex.EncodeToString(acc.Mode.MerkleAllowlist.Root[:])
+			}
+			if id, ok := ix.pollID(pubkey); ok {
+				rec.PollID = someID(id)
+				ix.polls[id] = pubkey
+			}
+			return "polls", ix.db.Save(rec)
+		case *voting.CandidateAccount:
+			rec := &CandidateRecord{
+				Address:   addr,
+				Name:      acc.CandidateName,
+				Votes:     strconv.FormatUint(acc.CandidateVotes, 10),
+				Lamports:  int64(lamports),
+				UpdatedAt: now,
+			}
+			if id, poll, ok := ix.candidatePoll(pubkey, acc.CandidateName); ok {
+				rec.PollID = someID(id)
+				rec.PollAddress = poll.ToBase58()
+			}
+			return "candidates", ix.db.Save(rec)
+		default:
+			return "", fmt.Errorf("unexpected account type %s", name)
+		}
+	}
+	return "", fmt.Errorf("unknown program %s", p.name)
+}
+
+func (ix *indexer) remove(p program, addr string) (string, error) {
+	switch p.name {
+	case "favorite":
+		return "favorites", ix.db.Delete(&FavoriteRecord{Address: addr})
+	case "voting":
+		if err := ix.db.Delete(&PollRecord{Address: addr}); err != nil {
+			return "polls", err
+		}
+		return "candidates", ix.db.Delete(&CandidateRecord{Address: addr})
+	}
+	return "", fmt.Errorf("unknown program %s", p.name)
+}
+
+// pollID recovers a poll id by matching the address against ["poll", id_le]
+// PDAs for ids 0..maxPollID.
+func (ix *indexer) pollID(addr common.PublicKey) (uint64, bool) {
+	if ix.pollIDs == nil {
+		ix.pollIDs = make(map[common.PublicKey]uint64, ix.maxPollID+1)
+		for id := uint64(0); id <= ix.maxPollID; id++ {
+			pda, _, err := voting.FindPollAccountAddress(id)
+			if err == nil {
+				ix.pollIDs[pda] = id
+			}
+		}
+	}
+	id, ok := ix.pollIDs[addr]
+	return id, ok
+}
+
+// candidatePoll finds the known poll whose [id_le, name] PDA equals addr.
+func (ix *indexer) candidatePoll(addr common.PublicKey, name string) (uint64, common.PublicKey, bool) {
+	for id, poll := range ix.polls {
+		pda, _, err := voting.FindCandidateAccountAddress(id, name)
+		if err == nil && pda == addr {
+			return id, poll, true
+		}
+	}
+	return 0, common.PublicKey{}, false
+}
+
+type accountUpdate struct {
+	program program
+	value   ws.ProgramNotification
+}
+
+// watch keeps the store in sync: it subscribes to programSubscribe for every
+// program, runs a full sync, then applies account changes as they arrive.
+// Notifications received during the sync are buffered and applied afterwards,
+// so they always win over the snapshot. On disconnect it resyncs and resubscribes.
+func (ix *indexer) watch(ctx context.Context, wsEndpoint string, commitment rpc.Commitment, backoff time.Duration) error {
+	for {
+		err := ix.watchOnce(ctx, wsEndpoint, commitment)
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Printf("watch: %v; resyncing in %s", err, backoff)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func (ix *indexer) watchOnce(ctx context.Context, wsEndpoint string, commitment rpc.Commitment) error {
+	conn, err := ws.Dial(ctx, wsEndpoint)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	updates := make(chan accountUpdate, 1024)
+	for _, p := range ix.programs {
+		// No memcmp filter here: closed accounts have empty data and would not match.
+		sub, err := conn.ProgramSubscribe(ctx, p.id, commitment)
+		if err != nil {
+			return err
+		}
+		go func(p program, sub *ws.Subscription) {
+			for raw := range sub.C() {
+				var n ws.ProgramNotification
+				if err := json.Unmarshal(raw, &n); err != nil {
+					log.Printf("programNotification: %v", err)
+					continue
+				}
+				select {
+				case updates <- accountUpdate{p, n}:
+				case <-conn.Done():
+					return
+				}
+			}
+		}(p, sub)
+	}
+
+	counts, err := ix.syncAll(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("synced %v", counts)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-conn.Done():
+			return fmt.Errorf("websocket closed: %w", conn.Err())
+		case u := <-updates:
+			data, err := anchor.DecodeAccountData(u.value.Value.Account.Data)
+			if err != nil {
+				log.Printf("account %s: %v", u.value.Value.Pubkey, err)
+				continue
+			}
+			pubkey := common.PublicKeyFromString(u.value.Value.Pubkey)
+			owner := common.PublicKeyFromString(u.value.Value.Account.Owner)
+			table, err := ix.apply(u.program, pubkey, u.value.Value.Account.Lamports, owner, data)
+			if err != nil {
+				log.Printf("skip %s account %s: %v", u.program.name, pubkey.ToBase58(), err)
+				continue
+			}
+			log.Printf("slot %d: updated %s %s", u.value.Context.Slot, table, pubkey.ToBase58())
+		}
+	}
+}
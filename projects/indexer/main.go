@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/rpc"
+	_ "github.com/mattn/go-sqlite3"
+
+	"orm_demo/orm"
+	"sdk/ws"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "sync":
+		fs := flag.NewFlagSet("sync", flag.ExitOnError)
+		dbPath, progs, maxPollID, cluster, rpcURL := indexFlags(fs)
+		_ = fs.Parse(os.Args[2:])
+		ix, closeDB := openIndexer(*dbPath, *progs, *maxPollID, *cluster, *rpcURL)
+		defer closeDB()
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		counts, err := ix.syncAll(ctx)
+		if err != nil {
+			log.Fatalf("sync error: %v", err)
+		}
+		printJSON(map[string]any{"db": *dbPath, "synced": counts})
+	case "watch":
+		fs := flag.NewFlagSet("watch", flag.ExitOnError)
+		dbPath, progs, maxPollID, cluster, rpcURL := indexFlags(fs)
+		wsURL := fs.String("ws", "", "Custom websocket endpoint URL (default: derived from RPC URL)")
+		commitment := fs.String("commitment", "confirmed", "Commitment: confirmed|finalized")
+		backoff := fs.Duration("backoff", 5*time.Second, "Delay before resyncing after a disconnect")
+		_ = fs.Parse(os.Args[2:])
+		ix, closeDB := openIndexer(*dbPath, *progs, *maxPollID, *cluster, *rpcURL)
+		defer closeDB()
+		endpoint := strings.TrimSpace(*wsURL)
+		if endpoint == "" {
+			endpoint = ws.EndpointFor(resolveEndpoint(*cluster, *rpcURL))
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if err := ix.watch(ctx, endpoint, rpc.Commitment(*commitment), *backoff); err != nil {
+			log.Fatalf("watch error: %v", err)
+		}
+	case "list":
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(1)
+		}
+		fs := flag.NewFlagSet("list", flag.ExitOnError)
+		dbPath := fs.String("db", "indexer.db", "SQLite database file")
+		pollID := fs.Int64("poll-id", -1, "Only candidates of this poll (list candidates)")
+		asJSON := fs.Bool("json", false, "Print rows as JSON instead of a table")
+		_ = fs.Parse(os.Args[3:])
+		db, err := openDB(*dbPath)
+		if err != nil {
+			log.Fatalf("open db: %v", err)
+		}
+		defer db.Pool().Close()
+		if err := runList(db, os.Args[2], *pollID, *asJSON); err != nil {
+			log.Fatalf("list error: %v", err)
+		}
+	default:
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+func indexFlags(fs *flag.FlagSet) (dbPath, progs *string, maxPollID *uint64, cluster, rpcURL *string) {
+	dbPath = fs.String("db", "indexer.db", "SQLite database file")
+	progs = fs.String("programs", "favorite,voting", "Comma separated programs to index: favorite,voting")
+	maxPollID = fs.Uint64("max-poll-id", 1000, "Highest poll id tried when recovering poll ids from PDAs")
+	cluster = fs.String("cluster", "local", "Cluster: devnet|testnet|mainnet|local")
+	rpcURL = fs.String("rpc", "", "Custom RPC endpoint URL (override)")
+	return dbPath, progs, maxPollID, cluster, rpcURL
+}
+
+func openIndexer(dbPath, progs string, maxPollID uint64, cluster, rpcOverride string) (*indexer, func()) {
+	var selected []program
+	for _, name := range strings.Split(progs, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		p, ok := programs[name]
+		if !ok {
+			log.Fatalf("unknown program %q (known: favorite, voting)", name)
+		}
+		selected = append(selected, p)
+	}
+	db, err := openDB(dbPath)
+	if err != nil {
+		log.Fatalf("open db: %v", err)
+	}
+	ix := newIndexer(db, client.NewClient(resolveEndpoint(cluster, rpcOverride)), selected, maxPollID)
+	if err := ix.migrate(); err != nil {
+		log.Fatalf("migrate: %v", err)
+	}
+	return ix, func() { db.Pool().Close() }
+}
+
+func openDB(path string) (*orm.ORM, error) {
+	return orm.NewORM(orm.DBConfig{
+		Driver: "sqlite3",
+		DSN:    path,
+		// SQLite allows a single writer.
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+}
+
+func runList(db *orm.ORM, kind string, pollID int64, asJSON bool) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	switch kind {
+	case "polls":
+		var rows []PollRecord
+		if err := db.Find(&rows); err != nil {
+			return err
+		}
+		if asJSON {
+			return printJSON(rows)
+		}
+		fmt.Fprintln(w, "POLL_ID\tNAME\tSTART\tEND\tCANDIDATES\tMODE\tWINNER\tADDRESS")
+		for _, r := range rows {
+			winner := "-"
+			if r.Finalized {
+				winner = fmt.Sprintf("%s (%s)", r.Winner, r.WinnerVotes)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\t%s\t%s\n", r.PollID, r.Name, r.VoteStart, r.VoteEnd, r.CandidateCount, r.Mode, winner, r.Address)
+		}
+	case "candidates":
+		var rows []CandidateRecord
+		var err error
+		if pollID >= 0 {
+			err = db.Find(&rows, "poll_id = ?", pollID)
+		} else {
+			err = db.Find(&rows)
+		}
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			return printJSON(rows)
+		}
+		fmt.Fprintln(w, "POLL_ID\tCANDIDATE\tVOTES\tADDRESS")
+		for _, r := range rows {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.PollID, r.Name, r.Votes, r.Address)
+		}
+	case "favorites":
+		var rows []FavoriteRecord
+		if err := db.Find(&rows); err != nil {
+			return err
+		}
+		if asJSON {
+			return printJSON(rows)
+		}
+		fmt.Fprintln(w, "USER\tNUMBER\tCOLOR\tHOBBIES\tADDRESS")
+		for _, r := range rows {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.User, r.Number, r.Color, r.Hobbies, r.Address)
+		}
+	default:
+		return fmt.Errorf("unknown table %q (polls|candidates|favorites)", kind)
+	}
+	return w.Flush()
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func endpointFor(cluster string) string {
+	switch strings.ToLower(strings.TrimSpace(cluster)) {
+	case "mainnet", "mainnet-beta":
+		return rpc.MainnetRPCEndpoint
+	case "testnet":
+		return rpc.TestnetRPCEndpoint
+	case "devnet":
+		return rpc.DevnetRPCEndpoint
+	default:
+		return "http://127.0.0.1:8899"
+	}
+}
+
+func resolveEndpoint(cluster, rpcOverride string) string {
+	if strings.TrimSpace(rpcOverride) != "" {
+		return strings.TrimSpace(rpcOverride)
+	}
+	return endpointFor(cluster)
+}
+
+func printUsage() {
+	fmt.Println(`Usage:
+  Full sync (getProgramAccounts + discriminator filters) into SQLite:
+    go run . sync [--db indexer.db] [--programs favorite,voting] [--max-poll-id 1000] [--cluster local|devnet|testnet|mainnet] [--rpc <url>]
+
+  Sync, then keep the store updated from programSubscribe notifications:
+    go run . watch [--db indexer.db] [--programs ...] [--ws <url>] [--commitment confirmed] [--backoff 5s] [--cluster ...] [--rpc <url>]
+
+  List indexed rows:
+    go run . list polls|candidates|favorites [--db indexer.db] [--poll-id <id>] [--json]`)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Records are stored through the orm package (grammar/go/orm); column names and
+// types come from the struct tags. u64 values that may exceed int64 are kept as text.
+
+// PollRecord is a voting program Poll account.
+type PollRecord struct {
+	Address string `db:"address" primary_key:"true" type:"text" json:"address"`
+	// PollID is recovered by re-deriving ["poll", id_le] PDAs; NULL if not found
+	// within --max-poll-id since the account does not store its id.
+	PollID         OptionalID `db:"poll_id" type:"integer" json:"pollId"`
+	Name           string     `db:"name" json:"name"`
+	Description    string     `db:"description" json:"description"`
+	VoteStart      int64      `db:"vote_start" json:"voteStart"`
+	VoteEnd        int64      `db:"vote_end" json:"voteEnd"`
+	CandidateCount int64      `db:"candidate_count" json:"candidateCount"`
+	Authority      string     `db:"authority" json:"authority"`
+	Finalized      bool       `db:"finalized" json:"finalized"`
+	Winner         string     `db:"winner" json:"winner"`
+	WinnerVotes    string     `db:"winner_votes" json:"winnerVotes"`
+	// Mode is OneWalletOneVote, TokenWeighted or MerkleAllowlist; Mint and
+	// AllowlistRoot (hex) are set for the matching mode only.
+	Mode          string    `db:"mode" json:"mode"`
+	Mint          string    `db:"mint" json:"mint,omitempty"`
+	AllowlistRoot string    `db:"allowlist_root" json:"allowlistRoot,omitempty"`
+	Lamports      int64     `db:"lamports" json:"lamports"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+func (PollRecord) TableName() string { return "polls" }
+
+// CandidateRecord is a voting program CandidateAccount.
+type CandidateRecord struct {
+	Address string `db:"address" primary_key:"true" type:"text" json:"address"`
+	// PollID / PollAddress are set when the [id_le, name] PDA matches a known poll.
+	PollID      OptionalID `db:"poll_id" type:"integer" json:"pollId"`
+	PollAddress string     `db:"poll_address" json:"pollAddress"`
+	Name        string     `db:"name" json:"name"`
+	Votes       string     `db:"votes" type:"text" json:"votes"`
+	Lamports    int64      `db:"lamports" json:"lamports"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updatedAt"`
+}
+
+func (CandidateRecord) TableName() string { return "candidates" }
+
+// FavoriteRecord is a favorite program Favorite account, either the user's
+// default favorites or one of their named profiles.
+type FavoriteRecord struct {
+	Address   string    `db:"address" primary_key:"true" type:"text" json:"address"`
+	User      string    `db:"user" json:"user"`
+	Number    string    `db:"number" json:"number"`
+	Color     string    `db:"color" json:"color"`
+	Hobbies   string    `db:"hobbies" json:"hobbies"` // JSON array
+	Lamports  int64     `db:"lamports" json:"lamports"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+func (FavoriteRecord) TableName() string { return "favorites" }
+
+// OptionalID is a nullable id column that encodes as a number or null in JSON.
+type OptionalID struct {
+	sql.NullInt64
+}
+
+func someID(id uint64) OptionalID {
+	return OptionalID{sql.NullInt64{Int64: int64(id), Valid: true}}
+}
+
+func (o OptionalID) MarshalJSON() ([]byte, error) {
+	if !o.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Int64)
+}
+
+func (o OptionalID) String() string {
+	if !o.Valid {
+		return "-"
+	}
+	return strconv.FormatInt(o.Int64, 10)
+}
@@ -0,0 +1,77 @@
+package anchor
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/mr-tron/base58"
+)
+
+// ProgramAccount 是 getProgramAccounts 返回的一项
+type ProgramAccount struct {
+	Pubkey   common.PublicKey
+	Lamports uint64
+	Owner    common.PublicKey
+	Data     []byte
+}
+
+// DiscriminatorFilter 返回匹配账户 discriminator 的 memcmp 过滤器（offset 0）
+func DiscriminatorFilter(d Discriminator) rpc.GetProgramAccountsConfigFilter {
+	return MemcmpFilter(0, d[:])
+}
+
+// MemcmpFilter 构造 memcmp 过滤器，bytes 以 base58 编码传给 RPC
+func MemcmpFilter(offset uint64, b []byte) rpc.GetProgramAccountsConfigFilter {
+	return rpc.GetProgramAccountsConfigFilter{
+		MemCmp: &rpc.GetProgramAccountsConfigFilterMemCmp{
+			Offset: offset,
+			Bytes:  base58.Encode(b),
+		},
+	}
+}
+
+// FetchProgramAccounts 调用 getProgramAccounts，返回程序下所有满足 filters 的账户。
+// 通常配合 DiscriminatorFilter 只拉取某一种 Anchor 账户。
+func FetchProgramAccounts(ctx context.Context, c *client.Client, programID common.PublicKey, filters ...rpc.GetProgramAccountsConfigFilter) ([]ProgramAccount, error) {
+	res, err := c.RpcClient.GetProgramAccountsWithConfig(ctx, programID.ToBase58(), rpc.GetProgramAccountsConfig{
+		Encoding: rpc.AccountEncodingBase64,
+		Filters:  filters,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getProgramAccounts: %w", err)
+	}
+	if res.Error != nil {
+		return nil, fmt.Errorf("getProgramAccounts: %w", res.Error)
+	}
+	out := make([]ProgramAccount, 0, len(res.Result))
+	for _, item := range res.Result {
+		data, err := DecodeAccountData(item.Account.Data)
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", item.Pubkey, err)
+		}
+		out = append(out, ProgramAccount{
+			Pubkey:   common.PublicKeyFromString(item.Pubkey),
+			Lamports: item.Account.Lamports,
+			Owner:    common.PublicKeyFromString(item.Account.Owner),
+			Data:     data,
+		})
+	}
+	return out, nil
+}
+
+// DecodeAccountData 解析 RPC（含订阅通知）返回的 ["<base64>", "base64"] 账户数据
+func DecodeAccountData(v any) ([]byte, error) {
+	pair, ok := v.([]any)
+	if !ok || len(pair) != 2 {
+		return nil, fmt.Errorf("unexpected account data format %T", v)
+	}
+	if enc, _ := pair[1].(string); enc != string(rpc.AccountEncodingBase64) {
+		return nil, fmt.Errorf("unexpected account data encoding %v", pair[1])
+	}
+	s, _ := pair[0].(string)
+	return base64.StdEncoding.DecodeString(s)
+}
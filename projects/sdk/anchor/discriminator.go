@@ -0,0 +1,47 @@
+package anchor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// DiscriminatorSize 是 Anchor 指令 / 账户 / 事件前缀的长度
+const DiscriminatorSize = 8
+
+// Discriminator 是 sha256("<namespace>:<name>") 的前 8 字节
+type Discriminator [DiscriminatorSize]byte
+
+func sighash(namespace, name string) Discriminator {
+	h := sha256.Sum256([]byte(namespace + ":" + strings.TrimSpace(name)))
+	var d Discriminator
+	copy(d[:], h[:DiscriminatorSize])
+	return d
+}
+
+// InstructionDiscriminator 计算 SIGHASH("global:<name>")，name 为 snake_case 指令名
+func InstructionDiscriminator(name string) Discriminator {
+	return sighash("global", name)
+}
+
+// AccountDiscriminator 计算 SIGHASH("account:<Name>")，Name 为账户结构体名
+func AccountDiscriminator(name string) Discriminator {
+	return sighash("account", name)
+}
+
+// EventDiscriminator 计算 SIGHASH("event:<Name>")，Name 为事件结构体名
+func EventDiscriminator(name string) Discriminator {
+	return sighash("event", name)
+}
+
+// CheckDiscriminator 校验数据前缀，返回去掉前缀后的部分
+func CheckDiscriminator(data []byte, want Discriminator) ([]byte, error) {
+	if len(data) < DiscriminatorSize {
+		return nil, fmt.Errorf("data too short for discriminator: %d bytes", len(data))
+	}
+	if !bytes.Equal(data[:DiscriminatorSize], want[:]) {
+		return nil, fmt.Errorf("discriminator mismatch: got %x, want %x", data[:DiscriminatorSize], want[:])
+	}
+	return data[DiscriminatorSize:], nil
+}
@@ -0,0 +1,281 @@
+package anchor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// ProgramError 是解码后的指令错误，替代 RPC 返回的
+// "custom program error: 0x1770" 这类不透明信息
+type ProgramError struct {
+	// Program 是失败指令所属的程序（从日志或指令列表推断，可能为空）
+	Program string `json:"program,omitempty"`
+	// Instruction 是交易中失败指令的下标
+	Instruction int `json:"instruction"`
+	// Custom 为 true 时 Code 有效（InstructionError::Custom）
+	Custom bool   `json:"custom"`
+	Code   uint32 `json:"code,omitempty"`
+	// Name 为错误变体名，例如 VotingEnded、ConstraintSeeds、InvalidAccountData
+	Name    string `json:"name"`
+	Message string `json:"message,omitempty"`
+	// Account 是 Anchor 日志 "AnchorError caused by account: xxx" 中的账户名
+	Account string   `json:"account,omitempty"`
+	Logs    []string `json:"logs,omitempty"`
+
+	cause error
+}
+
+func (e *ProgramError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "instruction %d", e.Instruction)
+	if e.Program != "" {
+		fmt.Fprintf(&b, " (program %s)", e.Program)
+	}
+	b.WriteString(" failed: ")
+	if e.Name != "" {
+		b.WriteString(e.Name)
+	} else {
+		b.WriteString("unknown error")
+	}
+	if e.Custom {
+		fmt.Fprintf(&b, " (%d)", e.Code)
+	}
+	if e.Message != "" {
+		b.WriteString(": " + e.Message)
+	}
+	if e.Account != "" {
+		b.WriteString(" [account: " + e.Account + "]")
+	}
+	return b.String()
+}
+
+// Unwrap 返回原始 RPC 错误
+func (e *ProgramError) Unwrap() error {
+	return e.cause
+}
+
+// Is 支持 errors.Is(err, voting.ErrVotingEnded) 与 errors.Is(err, anchor.ErrConstraintSeeds)
+func (e *ProgramError) Is(target error) bool {
+	if !e.Custom {
+		return false
+	}
+	switch t := target.(type) {
+	case FrameworkError:
+		return uint32(t) == e.Code
+	case CodedError:
+		return t.Code() == e.Code && t.Program().ToBase58() == e.Program
+	}
+	return false
+}
+
+// CodedError 由 anchorgen 生成的 ErrorCode 类型实现
+type CodedError interface {
+	error
+	Program() common.PublicKey
+	Code() uint32
+}
+
+// ---------------------------------------------------------------------------
+// 错误表注册
+// ---------------------------------------------------------------------------
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]map[uint32]IDLError{}
+)
+
+// RegisterErrors 登记程序的自定义错误表；生成的客户端包在 init 中自动调用
+func RegisterErrors(programID common.PublicKey, errs []IDLError) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	table := registry[programID.ToBase58()]
+	if table == nil {
+		table = map[uint32]IDLError{}
+		registry[programID.ToBase58()] = table
+	}
+	for _, e := range errs {
+		table[e.Code] = e
+	}
+}
+
+// RegisterIDL 从 IDL 登记错误表，适用于没有生成客户端的程序
+func RegisterIDL(idl *IDL) {
+	RegisterErrors(common.PublicKeyFromString(idl.Address), idl.Errors)
+}
+
+// LookupError 按程序与错误码查找错误名与描述：
+// 先查程序登记的表，再查内置程序（System 等）与 Anchor 框架错误码
+func LookupError(program string, code uint32) (IDLError, bool) {
+	registryMu.RLock()
+	e, ok := registry[program][code]
+	registryMu.RUnlock()
+	if ok {
+		return e, true
+	}
+	if table, ok := builtinErrors[program]; ok {
+		e, ok := table[code]
+		return e, ok
+	}
+	if e, ok := frameworkErrors[FrameworkError(code)]; ok {
+		return IDLError{Code: code, Name: e.name, Msg: e.msg}, true
+	}
+	return IDLError{}, false
+}
+
+// ---------------------------------------------------------------------------
+// 解码
+// ---------------------------------------------------------------------------
+
+var (
+	programFailedRe = regexp.MustCompile(`^Program (\w+) failed: (.*)$`)
+	anchorAccountRe = regexp.MustCompile(`AnchorError caused by account: (\w+)\.`)
+	customHexRe     = regexp.MustCompile(`custom program error: 0x([0-9a-fA-F]+)`)
+)
+
+// DecodeError 尝试把 SendTransaction / SimulateTransaction 返回的错误
+// 解码为 *ProgramError；无法识别时原样返回 err。
+// ixs 为交易中的指令，用于在日志缺失时确定失败的程序。
+func DecodeError(err error, ixs ...types.Instruction) error {
+	if err == nil {
+		return nil
+	}
+	var pe *ProgramError
+	if errors.As(err, &pe) {
+		return err
+	}
+
+	var rpcErr *rpc.JsonRpcError
+	if errors.As(err, &rpcErr) {
+		if data, ok := rpcErr.Data.(map[string]any); ok {
+			logs := toStrings(data["logs"])
+			if decoded := DecodeTransactionError(data["err"], logs, ixs...); decoded != nil {
+				decoded.cause = err
+				return decoded
+			}
+		}
+	}
+
+	// 兜底：错误只剩字符串时，从中提取十六进制错误码
+	if m := customHexRe.FindStringSubmatch(err.Error()); m != nil {
+		code, perr := strconv.ParseUint(m[1], 16, 32)
+		if perr == nil {
+			pe := &ProgramError{Instruction: -1, Custom: true, Code: uint32(code), cause: err}
+			if len(ixs) == 1 {
+				pe.Instruction = 0
+				pe.Program = ixs[0].ProgramID.ToBase58()
+			}
+			pe.fill()
+			return pe
+		}
+	}
+	return err
+}
+
+// DecodeTransactionError 解码交易结果中的 err 字段（模拟结果、交易元数据或 RPC 错误数据），
+// 形如 {"InstructionError": [0, {"Custom": 6001}]}；不是指令错误时返回 nil
+func DecodeTransactionError(txErr any, logs []string, ixs ...types.Instruction) *ProgramError {
+	m, ok := txErr.(map[string]any)
+	if !ok {
+		return nil
+	}
+	pair, ok := m["InstructionError"].([]any)
+	if !ok || len(pair) != 2 {
+		return nil
+	}
+	idx, ok := toInt(pair[0])
+	if !ok {
+		return nil
+	}
+	pe := &ProgramError{Instruction: idx, Logs: logs}
+	switch v := pair[1].(type) {
+	case string:
+		pe.Name = v
+	case map[string]any:
+		if c, ok := v["Custom"]; ok {
+			code, ok := toInt(c)
+			if !ok {
+				return nil
+			}
+			pe.Custom = true
+			pe.Code = uint32(code)
+		} else {
+			// 例如 {"BorshIoError": "..."}
+			for k, detail := range v {
+				pe.Name = k
+				pe.Message = fmt.Sprint(detail)
+			}
+		}
+	default:
+		return nil
+	}
+
+	pe.Program = failedProgram(logs)
+	if pe.Program == "" && idx >= 0 && idx < len(ixs) {
+		pe.Program = ixs[idx].ProgramID.ToBase58()
+	}
+	for _, l := range logs {
+		if m := anchorAccountRe.FindStringSubmatch(l); m != nil {
+			pe.Account = m[1]
+		}
+	}
+	pe.fill()
+	return pe
+}
+
+// fill 根据程序与错误码补齐 Name / Message
+func (e *ProgramError) fill() {
+	if !e.Custom {
+		return
+	}
+	if info, ok := LookupError(e.Program, e.Code); ok {
+		e.Name = info.Name
+		e.Message = info.Msg
+	}
+}
+
+// failedProgram 返回日志中第一条 "Program <id> failed" 的程序；
+// 存在 CPI 时错误会逐层向外传播，第一条即最内层真正出错的程序
+func failedProgram(logs []string) string {
+	for _, l := range logs {
+		if m := programFailedRe.FindStringSubmatch(l); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	case int:
+		return n, true
+	}
+	return 0, false
+}
+
+func toStrings(v any) []string {
+	list, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,220 @@
+package anchor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// 测试用程序，登记两个自定义错误
+var (
+	testProgram = common.PublicKeyFromString("31Tq6cGFa1CU8JaU51snTvKaXaKqWP3M3dFBWNXeJqYj")
+	testOuter   = common.PublicKeyFromString("Fg6PaFpoGXkYsidMpWTK6W2BeZ7FEfcYkg476zPFsLnS")
+)
+
+func init() {
+	RegisterErrors(testProgram, []IDLError{
+		{Code: 6000, Name: "VotingNotStarted", Msg: "Voting has not started yet"},
+		{Code: 6001, Name: "VotingEnded", Msg: "Voting has ended"},
+	})
+}
+
+type testCodedError uint32
+
+func (e testCodedError) Error() string             { return fmt.Sprintf("error %d", uint32(e)) }
+func (e testCodedError) Program() common.PublicKey { return testProgram }
+func (e testCodedError) Code() uint32              { return uint32(e) }
+
+func TestLookupError(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+		code    uint32
+		want    string
+		ok      bool
+	}{
+		{"custom", testProgram.ToBase58(), 6001, "VotingEnded", true},
+		{"framework", testProgram.ToBase58(), 2006, "ConstraintSeeds", true},
+		{"framework for unregistered program", testOuter.ToBase58(), 3012, "AccountNotInitialized", true},
+		{"system", common.SystemProgramID.ToBase58(), 1, "ResultWithNegativeLamports", true},
+		// System 程序的错误码不会落到 Anchor 框架表
+		{"system unknown", common.SystemProgramID.ToBase58(), 2006, "", false},
+		{"unknown custom", testProgram.ToBase58(), 6999, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := LookupError(tt.program, tt.code)
+			if ok != tt.ok || got.Name != tt.want {
+				t.Fatalf("LookupError(%s, %d) = %q, %v; want %q, %v", tt.program, tt.code, got.Name, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestDecodeTransactionError(t *testing.T) {
+	custom := func(idx, code int) any {
+		return map[string]any{"InstructionError": []any{float64(idx), map[string]any{"Custom": float64(code)}}}
+	}
+	tests := []struct {
+		name    string
+		txErr   any
+		logs    []string
+		ixs     []types.Instruction
+		want    *ProgramError
+		wantNil bool
+	}{
+		{
+			name:  "custom code from logs",
+			txErr: custom(0, 6001),
+			logs: []string{
+				"Program " + testProgram.ToBase58() + " invoke [1]",
+				"Program log: AnchorError thrown in programs/voting/src/lib.rs:80. Error Code: VotingEnded. Error Number: 6001. Error Message: Voting has ended.",
+				"Program " + testProgram.ToBase58() + " failed: custom program error: 0x1771",
+			},
+			want: &ProgramError{Program: testProgram.ToBase58(), Instruction: 0, Custom: true, Code: 6001, Name: "VotingEnded", Message: "Voting has ended"},
+		},
+		{
+			name:  "framework code with account",
+			txErr: custom(1, 2006),
+			logs: []string{
+				"Program " + testProgram.ToBase58() + " invoke [1]",
+				"Program log: AnchorError caused by account: poll_account. Error Code: ConstraintSeeds. Error Number: 2006. Error Message: A seeds constraint was violated.",
+				"Program " + testProgram.ToBase58() + " failed: custom program error: 0x7d6",
+			},
+			want: &ProgramError{Program: testProgram.ToBase58(), Instruction: 1, Custom: true, Code: 2006, Name: "ConstraintSeeds", Message: "A seeds constraint was violated", Account: "poll_account"},
+		},
+		{
+			// 外层程序 CPI 调用 System 程序失败：第一条 failed 日志是最内层的 System 程序
+			name:  "nested CPI failure",
+			txErr: custom(0, 1),
+			logs: []string{
+				"Program " + testOuter.ToBase58() + " invoke [1]",
+				"Program 11111111111111111111111111111111 invoke [2]",
+				"Transfer: insufficient lamports 10, need 100",
+				"Program 11111111111111111111111111111111 failed: custom program error: 0x1",
+				"Program " + testOuter.ToBase58() + " consumed 4000 of 200000 compute units",
+				"Program " + testOuter.ToBase58() + " failed: custom program error: 0x1",
+			},
+			want: &ProgramError{Program: common.SystemProgramID.ToBase58(), Instruction: 0, Custom: true, Code: 1, Name: "ResultWithNegativeLamports", Message: "account does not have enough SOL to perform the operation"},
+		},
+		{
+			name:  "program from instruction list without logs",
+			txErr: custom(1, 0),
+			ixs:   []types.Instruction{{ProgramID: testOuter}, {ProgramID: common.SystemProgramID}},
+			want:  &ProgramError{Program: common.SystemProgramID.ToBase58(), Instruction: 1, Custom: true, Code: 0, Name: "AccountAlreadyInUse", Message: "an account with the same address already exists"},
+		},
+		{
+			name:  "builtin instruction error",
+			txErr: map[string]any{"InstructionError": []any{float64(0), "InvalidAccountData"}},
+			want:  &ProgramError{Instruction: 0, Name: "InvalidAccountData"},
+		},
+		{
+			name:  "error with detail",
+			txErr: map[string]any{"InstructionError": []any{float64(0), map[string]any{"BorshIoError": "Unknown"}}},
+			want:  &ProgramError{Instruction: 0, Name: "BorshIoError", Message: "Unknown"},
+		},
+		{name: "transaction level error", txErr: "BlockhashNotFound", wantNil: true},
+		{name: "malformed pair", txErr: map[string]any{"InstructionError": []any{float64(0)}}, wantNil: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecodeTransactionError(tt.txErr, tt.logs, tt.ixs...)
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("got %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("got nil")
+			}
+			got.Logs = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	rpcErr := &rpc.JsonRpcError{
+		Code:    -32002,
+		Message: "Transaction simulation failed: Error processing Instruction 0: custom program error: 0x1771",
+		Data: map[string]any{
+			"err": map[string]any{"InstructionError": []any{float64(0), map[string]any{"Custom": float64(6001)}}},
+			"logs": []any{
+				"Program " + testProgram.ToBase58() + " invoke [1]",
+				"Program " + testProgram.ToBase58() + " failed: custom program error: 0x1771",
+			},
+		},
+	}
+	wrapped := fmt.Errorf("failed to send transaction: %w", rpcErr)
+
+	err := DecodeError(wrapped)
+	var pe *ProgramError
+	if !errors.As(err, &pe) {
+		t.Fatalf("DecodeError returned %T: %v", err, err)
+	}
+	if pe.Name != "VotingEnded" || len(pe.Logs) != 2 {
+		t.Fatalf("unexpected error %+v", pe)
+	}
+	if !errors.Is(err, testCodedError(6001)) || errors.Is(err, testCodedError(6000)) {
+		t.Fatal("errors.Is does not match the program error code")
+	}
+	if !errors.As(err, &rpcErr) {
+		t.Fatal("the RPC error is not reachable through Unwrap")
+	}
+	if DecodeError(err) != err {
+		t.Fatal("decoding an already decoded error changed it")
+	}
+	if DecodeError(nil) != nil {
+		t.Fatal("DecodeError(nil) != nil")
+	}
+}
+
+func TestDecodeErrorFromString(t *testing.T) {
+	ix := types.Instruction{ProgramID: testProgram}
+	tests := []struct {
+		name    string
+		err     error
+		ixs     []types.Instruction
+		want    string
+		wantIdx int
+	}{
+		{"single instruction", errors.New("Error processing Instruction 0: custom program error: 0x1770"), []types.Instruction{ix}, "VotingNotStarted", 0},
+		// 多条指令时无法确定失败的程序，只能回退到框架错误表
+		{"several instructions", errors.New("custom program error: 0x7d1"), []types.Instruction{ix, ix}, "ConstraintHasOne", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pe *ProgramError
+			if !errors.As(DecodeError(tt.err, tt.ixs...), &pe) {
+				t.Fatal("not decoded")
+			}
+			if pe.Name != tt.want || pe.Instruction != tt.wantIdx {
+				t.Fatalf("got %s at %d, want %s at %d", pe.Name, pe.Instruction, tt.want, tt.wantIdx)
+			}
+		})
+	}
+
+	plain := errors.New("connection refused")
+	if DecodeError(plain) != plain {
+		t.Fatal("an unrelated error was rewritten")
+	}
+}
+
+func TestFrameworkErrorIs(t *testing.T) {
+	pe := &ProgramError{Program: testProgram.ToBase58(), Custom: true, Code: 2006}
+	if !errors.Is(pe, ErrConstraintSeeds) || errors.Is(pe, ErrConstraintHasOne) {
+		t.Fatal("errors.Is does not match framework codes")
+	}
+	// 非 Custom 错误不与任何错误码匹配
+	if errors.Is(&ProgramError{Name: "InvalidAccountData"}, FrameworkError(0)) {
+		t.Fatal("a builtin error matched a framework code")
+	}
+}
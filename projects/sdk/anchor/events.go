@@ -0,0 +1,63 @@
+package anchor
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/blocto/solana-go-sdk/common"
+)
+
+const programDataPrefix = "Program data: "
+
+// EventData 是从交易日志中提取的一条 emit! 事件（已 base64 解码：8 字节 discriminator + Borsh）
+type EventData struct {
+	// Program 是发出该事件的程序
+	Program common.PublicKey
+	Data    []byte
+}
+
+// ParseEventLogs 从交易日志中提取所有 "Program data:" 事件，并根据
+// "Program <id> invoke [n]" / "Program <id> success|failed" 维护调用栈，
+// 以确定每条事件由哪个程序发出（CPI 场景下同一交易可能包含多个程序的事件）。
+func ParseEventLogs(logs []string) []EventData {
+	var stack []common.PublicKey
+	var out []EventData
+	for _, l := range logs {
+		if strings.HasPrefix(l, programDataPrefix) {
+			if len(stack) == 0 {
+				continue
+			}
+			data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(strings.TrimPrefix(l, programDataPrefix)))
+			if err != nil {
+				continue
+			}
+			out = append(out, EventData{Program: stack[len(stack)-1], Data: data})
+			continue
+		}
+		// "Program log: ..." / "Program return: ..." 等行的第二个字段带冒号，程序 ID 不会
+		f := strings.Fields(l)
+		if len(f) < 3 || f[0] != "Program" || strings.HasSuffix(f[1], ":") {
+			continue
+		}
+		switch f[2] {
+		case "invoke":
+			stack = append(stack, common.PublicKeyFromString(f[1]))
+		case "success", "failed:":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return out
+}
+
+// ProgramEvents 只返回指定程序发出的事件数据
+func ProgramEvents(logs []string, programID common.PublicKey) [][]byte {
+	var out [][]byte
+	for _, ev := range ParseEventLogs(logs) {
+		if ev.Program == programID {
+			out = append(out, ev.Data)
+		}
+	}
+	return out
+}
@@ -0,0 +1,174 @@
+package anchor
+
+import (
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+)
+
+// FrameworkError 是 Anchor 框架内置错误码（< 6000），与具体程序无关
+type FrameworkError uint32
+
+// Anchor 框架错误码，对应 anchor_lang::error::ErrorCode
+const (
+	ErrInstructionMissing                    FrameworkError = 100
+	ErrInstructionFallbackNotFound           FrameworkError = 101
+	ErrInstructionDidNotDeserialize          FrameworkError = 102
+	ErrInstructionDidNotSerialize            FrameworkError = 103
+	ErrIdlInstructionStub                    FrameworkError = 1000
+	ErrIdlInstructionInvalidProgram          FrameworkError = 1001
+	ErrIdlAccountNotEmpty                    FrameworkError = 1002
+	ErrEventInstructionStub                  FrameworkError = 1500
+	ErrConstraintMut                         FrameworkError = 2000
+	ErrConstraintHasOne                      FrameworkError = 2001
+	ErrConstraintSigner                      FrameworkError = 2002
+	ErrConstraintRaw                         FrameworkError = 2003
+	ErrConstraintOwner                       FrameworkError = 2004
+	ErrConstraintRentExempt                  FrameworkError = 2005
+	ErrConstraintSeeds                       FrameworkError = 2006
+	ErrConstraintExecutable                  FrameworkError = 2007
+	ErrConstraintState                       FrameworkError = 2008
+	ErrConstraintAssociated                  FrameworkError = 2009
+	ErrConstraintAssociatedInit              FrameworkError = 2010
+	ErrConstraintClose                       FrameworkError = 2011
+	ErrConstraintAddress                     FrameworkError = 2012
+	ErrConstraintZero                        FrameworkError = 2013
+	ErrConstraintTokenMint                   FrameworkError = 2014
+	ErrConstraintTokenOwner                  FrameworkError = 2015
+	ErrConstraintMintMintAuthority           FrameworkError = 2016
+	ErrConstraintMintFreezeAuthority         FrameworkError = 2017
+	ErrConstraintMintDecimals                FrameworkError = 2018
+	ErrConstraintSpace                       FrameworkError = 2019
+	ErrConstraintAccountIsNone               FrameworkError = 2020
+	ErrConstraintTokenTokenProgram           FrameworkError = 2021
+	ErrConstraintMintTokenProgram            FrameworkError = 2022
+	ErrConstraintAssociatedTokenTokenProgram FrameworkError = 2023
+	ErrRequireViolated                       FrameworkError = 2500
+	ErrRequireEqViolated                     FrameworkError = 2501
+	ErrRequireKeysEqViolated                 FrameworkError = 2502
+	ErrRequireNeqViolated                    FrameworkError = 2503
+	ErrRequireKeysNeqViolated                FrameworkError = 2504
+	ErrRequireGtViolated                     FrameworkError = 2505
+	ErrRequireGteViolated                    FrameworkError = 2506
+	ErrAccountDiscriminatorAlreadySet        FrameworkError = 3000
+	ErrAccountDiscriminatorNotFound          FrameworkError = 3001
+	ErrAccountDiscriminatorMismatch          FrameworkError = 3002
+	ErrAccountDidNotDeserialize              FrameworkError = 3003
+	ErrAccountDidNotSerialize                FrameworkError = 3004
+	ErrAccountNotEnoughKeys                  FrameworkError = 3005
+	ErrAccountNotMutable                     FrameworkError = 3006
+	ErrAccountOwnedByWrongProgram            FrameworkError = 3007
+	ErrInvalidProgramId                      FrameworkError = 3008
+	ErrInvalidProgramExecutable              FrameworkError = 3009
+	ErrAccountNotSigner                      FrameworkError = 3010
+	ErrAccountNotSystemOwned                 FrameworkError = 3011
+	ErrAccountNotInitialized                 FrameworkError = 3012
+	ErrAccountNotProgramData                 FrameworkError = 3013
+	ErrAccountNotAssociatedTokenAccount      FrameworkError = 3014
+	ErrAccountSysvarMismatch                 FrameworkError = 3015
+	ErrAccountReallocExceedsLimit            FrameworkError = 3016
+	ErrAccountDuplicateReallocs              FrameworkError = 3017
+	ErrDeclaredProgramIdMismatch             FrameworkError = 4100
+	ErrTryingToInitPayerAsProgramAccount     FrameworkError = 4101
+	ErrInvalidNumericConversion              FrameworkError = 4102
+	ErrDeprecated                            FrameworkError = 5000
+)
+
+type errorInfo struct {
+	name string
+	msg  string
+}
+
+var frameworkErrors = map[FrameworkError]errorInfo{
+	ErrInstructionMissing:                    {"InstructionMissing", "8 byte instruction identifier not provided"},
+	ErrInstructionFallbackNotFound:           {"InstructionFallbackNotFound", "Fallback functions are not supported"},
+	ErrInstructionDidNotDeserialize:          {"InstructionDidNotDeserialize", "The program could not deserialize the given instruction"},
+	ErrInstructionDidNotSerialize:            {"InstructionDidNotSerialize", "The program could not serialize the given instruction"},
+	ErrIdlInstructionStub:                    {"IdlInstructionStub", "The program was compiled without idl instructions"},
+	ErrIdlInstructionInvalidProgram:          {"IdlInstructionInvalidProgram", "Invalid program given to the IDL instruction"},
+	ErrIdlAccountNotEmpty:                    {"IdlAccountNotEmpty", "IDL account must be empty in order to resize, try closing first"},
+	ErrEventInstructionStub:                  {"EventInstructionStub", "The program was compiled without `event-cpi` feature"},
+	ErrConstraintMut:                         {"ConstraintMut", "A mut constraint was violated"},
+	ErrConstraintHasOne:                      {"ConstraintHasOne", "A has one constraint was violated"},
+	ErrConstraintSigner:                      {"ConstraintSigner", "A signer constraint was violated"},
+	ErrConstraintRaw:                         {"ConstraintRaw", "A raw constraint was violated"},
+	ErrConstraintOwner:                       {"ConstraintOwner", "An owner constraint was violated"},
+	ErrConstraintRentExempt:                  {"ConstraintRentExempt", "A rent exemption constraint was violated"},
+	ErrConstraintSeeds:                       {"ConstraintSeeds", "A seeds constraint was violated"},
+	ErrConstraintExecutable:                  {"ConstraintExecutable", "An executable constraint was violated"},
+	ErrConstraintState:                       {"ConstraintState", "Deprecated Error, feel free to replace with something else"},
+	ErrConstraintAssociated:                  {"ConstraintAssociated", "An associated constraint was violated"},
+	ErrConstraintAssociatedInit:              {"ConstraintAssociatedInit", "An associated init constraint was violated"},
+	ErrConstraintClose:                       {"ConstraintClose", "A close constraint was violated"},
+	ErrConstraintAddress:                     {"ConstraintAddress", "An address constraint was violated"},
+	ErrConstraintZero:                        {"ConstraintZero", "Expected zero account discriminant"},
+	ErrConstraintTokenMint:                   {"ConstraintTokenMint", "A token mint constraint was violated"},
+	ErrConstraintTokenOwner:                  {"ConstraintTokenOwner", "A token owner constraint was violated"},
+	ErrConstraintMintMintAuthority:           {"ConstraintMintMintAuthority", "A mint mint authority constraint was violated"},
+	ErrConstraintMintFreezeAuthority:         {"ConstraintMintFreezeAuthority", "A mint freeze authority constraint was violated"},
+	ErrConstraintMintDecimals:                {"ConstraintMintDecimals", "A mint decimals constraint was violated"},
+	ErrConstraintSpace:                       {"ConstraintSpace", "A space constraint was violated"},
+	ErrConstraintAccountIsNone:               {"ConstraintAccountIsNone", "A required account for the constraint is None"},
+	ErrConstraintTokenTokenProgram:           {"ConstraintTokenTokenProgram", "A token account token program constraint was violated"},
+	ErrConstraintMintTokenProgram:            {"ConstraintMintTokenProgram", "A mint token program constraint was violated"},
+	ErrConstraintAssociatedTokenTokenProgram: {"ConstraintAssociatedTokenTokenProgram", "An associated token account token program constraint was violated"},
+	ErrRequireViolated:                       {"RequireViolated", "A require expression was violated"},
+	ErrRequireEqViolated:                     {"RequireEqViolated", "A require_eq expression was violated"},
+	ErrRequireKeysEqViolated:                 {"RequireKeysEqViolated", "A require_keys_eq expression was violated"},
+	ErrRequireNeqViolated:                    {"RequireNeqViolated", "A require_neq expression was violated"},
+	ErrRequireKeysNeqViolated:                {"RequireKeysNeqViolated", "A require_keys_neq expression was violated"},
+	ErrRequireGtViolated:                     {"RequireGtViolated", "A require_gt expression was violated"},
+	ErrRequireGteViolated:                    {"RequireGteViolated", "A require_gte expression was violated"},
+	ErrAccountDiscriminatorAlreadySet:        {"AccountDiscriminatorAlreadySet", "The account discriminator was already set on this account"},
+	ErrAccountDiscriminatorNotFound:          {"AccountDiscriminatorNotFound", "No discriminator was found on the account"},
+	ErrAccountDiscriminatorMismatch:          {"AccountDiscriminatorMismatch", "Account discriminator did not match what was expected"},
+	ErrAccountDidNotDeserialize:              {"AccountDidNotDeserialize", "Failed to deserialize the account"},
+	ErrAccountDidNotSerialize:                {"AccountDidNotSerialize", "Failed to serialize the account"},
+	ErrAccountNotEnoughKeys:                  {"AccountNotEnoughKeys", "Not enough account keys given to the instruction"},
+	ErrAccountNotMutable:                     {"AccountNotMutable", "The given account is not mutable"},
+	ErrAccountOwnedByWrongProgram:            {"AccountOwnedByWrongProgram", "The given account is owned by a different program than expected"},
+	ErrInvalidProgramId:                      {"InvalidProgramId", "Program ID was not as expected"},
+	ErrInvalidProgramExecutable:              {"InvalidProgramExecutable", "Program account is not executable"},
+	ErrAccountNotSigner:                      {"AccountNotSigner", "The given account did not sign"},
+	ErrAccountNotSystemOwned:                 {"AccountNotSystemOwned", "The given account is not owned by the system program"},
+	ErrAccountNotInitialized:                 {"AccountNotInitialized", "The program expected this account to be already initialized"},
+	ErrAccountNotProgramData:                 {"AccountNotProgramData", "The given account is not a program data account"},
+	ErrAccountNotAssociatedTokenAccount:      {"AccountNotAssociatedTokenAccount", "The given account is not the associated token account"},
+	ErrAccountSysvarMismatch:                 {"AccountSysvarMismatch", "The given public key does not match the required sysvar"},
+	ErrAccountReallocExceedsLimit:            {"AccountReallocExceedsLimit", "The account reallocation exceeds the MAX_PERMITTED_DATA_INCREASE limit"},
+	ErrAccountDuplicateReallocs:              {"AccountDuplicateReallocs", "The account was duplicated for more than one reallocation"},
+	ErrDeclaredProgramIdMismatch:             {"DeclaredProgramIdMismatch", "The declared program id does not match the actual program id"},
+	ErrTryingToInitPayerAsProgramAccount:     {"TryingToInitPayerAsProgramAccount", "You cannot/should not initialize the payer account as a program account"},
+	ErrInvalidNumericConversion:              {"InvalidNumericConversion", "Error during numeric conversion"},
+	ErrDeprecated:                            {"Deprecated", "The API being used is deprecated and should no longer be used"},
+}
+
+func (e FrameworkError) Name() string {
+	return frameworkErrors[e].name
+}
+
+func (e FrameworkError) Message() string {
+	return frameworkErrors[e].msg
+}
+
+func (e FrameworkError) Error() string {
+	if info, ok := frameworkErrors[e]; ok {
+		return fmt.Sprintf("%s (%d): %s", info.name, uint32(e), info.msg)
+	}
+	return fmt.Sprintf("unknown anchor error %d", uint32(e))
+}
+
+// builtinErrors 是非 Anchor 原生程序的自定义错误表
+var builtinErrors = map[string]map[uint32]IDLError{
+	common.SystemProgramID.ToBase58(): {
+		0: {Code: 0, Name: "AccountAlreadyInUse", Msg: "an account with the same address already exists"},
+		1: {Code: 1, Name: "ResultWithNegativeLamports", Msg: "account does not have enough SOL to perform the operation"},
+		2: {Code: 2, Name: "InvalidProgramId", Msg: "cannot assign account to this program id"},
+		3: {Code: 3, Name: "InvalidAccountDataLength", Msg: "cannot allocate account data of this length"},
+		4: {Code: 4, Name: "MaxSeedLengthExceeded", Msg: "length of requested seed is too long"},
+		5: {Code: 5, Name: "AddressWithSeedMismatch", Msg: "provided address does not match addressed derived from seed"},
+		6: {Code: 6, Name: "NonceNoRecentBlockhashes", Msg: "advancing stored nonce requires a populated RecentBlockhashes sysvar"},
+		7: {Code: 7, Name: "NonceBlockhashNotExpired", Msg: "stored nonce is still in recent_blockhashes"},
+		8: {Code: 8, Name: "NonceUnexpectedBlockhashValue", Msg: "specified nonce does not match stored nonce"},
+	},
+}
@@ -0,0 +1,223 @@
+// Package anchor 提供与 Anchor 程序交互所需的通用能力：
+// IDL 解析、discriminator 计算等。
+package anchor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// IDL 对应 `anchor build` 生成的 target/idl/<program>.json（Anchor 0.30+ 格式）
+type IDL struct {
+	Address      string           `json:"address"`
+	Metadata     IDLMetadata      `json:"metadata"`
+	Docs         []string         `json:"docs,omitempty"`
+	Instructions []IDLInstruction `json:"instructions"`
+	Accounts     []IDLAccountDef  `json:"accounts,omitempty"`
+	Events       []IDLEventDef    `json:"events,omitempty"`
+	Errors       []IDLError       `json:"errors,omitempty"`
+	Types        []IDLTypeDef     `json:"types,omitempty"`
+}
+
+type IDLMetadata struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Spec        string `json:"spec"`
+	Description string `json:"description,omitempty"`
+}
+
+type IDLInstruction struct {
+	Name          string          `json:"name"`
+	Docs          []string        `json:"docs,omitempty"`
+	Discriminator []byte          `json:"discriminator"`
+	Accounts      []IDLAccountArg `json:"accounts"`
+	Args          []IDLField      `json:"args"`
+}
+
+// IDLAccountArg 是指令账户列表中的一项
+type IDLAccountArg struct {
+	Name     string   `json:"name"`
+	Docs     []string `json:"docs,omitempty"`
+	Writable bool     `json:"writable,omitempty"`
+	Signer   bool     `json:"signer,omitempty"`
+	Optional bool     `json:"optional,omitempty"`
+	Address  string   `json:"address,omitempty"`
+	PDA      *IDLPDA  `json:"pda,omitempty"`
+}
+
+type IDLPDA struct {
+	Seeds []IDLSeed `json:"seeds"`
+}
+
+// IDLSeed 的 Kind 取值：const（Value 为字节）、arg（Path 为参数名）、account（Path 为账户名）
+type IDLSeed struct {
+	Kind  string `json:"kind"`
+	Value []byte `json:"value,omitempty"`
+	Path  string `json:"path,omitempty"`
+}
+
+type IDLAccountDef struct {
+	Name          string `json:"name"`
+	Discriminator []byte `json:"discriminator"`
+}
+
+type IDLEventDef struct {
+	Name          string `json:"name"`
+	Discriminator []byte `json:"discriminator"`
+}
+
+type IDLError struct {
+	Code uint32 `json:"code"`
+	Name string `json:"name"`
+	Msg  string `json:"msg,omitempty"`
+}
+
+type IDLTypeDef struct {
+	Name string      `json:"name"`
+	Docs []string    `json:"docs,omitempty"`
+	Type IDLTypeBody `json:"type"`
+}
+
+// IDLTypeBody 描述自定义类型：Kind 为 struct 或 enum
+type IDLTypeBody struct {
+	Kind     string           `json:"kind"`
+	Fields   []IDLField       `json:"fields,omitempty"`
+	Variants []IDLEnumVariant `json:"variants,omitempty"`
+}
+
+type IDLEnumVariant struct {
+	Name   string     `json:"name"`
+	Fields []IDLField `json:"fields,omitempty"`
+}
+
+type IDLField struct {
+	Name string   `json:"name"`
+	Docs []string `json:"docs,omitempty"`
+	Type IDLType  `json:"type"`
+}
+
+// IDLType 是 IDL 中的类型表达式。
+// 基础类型只设置 Primitive（如 "u64"、"string"、"pubkey"）；
+// 复合类型分别设置 Vec / Option / Array（元素类型 + ArrayLen）/ Defined。
+type IDLType struct {
+	Primitive string
+	Vec       *IDLType
+	Option    *IDLType
+	Array     *IDLType
+	ArrayLen  int
+	Defined   string
+}
+
+func (t *IDLType) UnmarshalJSON(data []byte) error {
+	var prim string
+	if err := json.Unmarshal(data, &prim); err == nil {
+		// 旧版 IDL 使用 "publicKey"
+		if prim == "publicKey" {
+			prim = "pubkey"
+		}
+		t.Primitive = prim
+		return nil
+	}
+	var obj struct {
+		Vec     *IDLType          `json:"vec"`
+		Option  *IDLType          `json:"option"`
+		Array   []json.RawMessage `json:"array"`
+		Defined json.RawMessage   `json:"defined"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("invalid idl type %s: %w", string(data), err)
+	}
+	switch {
+	case obj.Vec != nil:
+		t.Vec = obj.Vec
+	case obj.Option != nil:
+		t.Option = obj.Option
+	case len(obj.Array) == 2:
+		var elem IDLType
+		if err := json.Unmarshal(obj.Array[0], &elem); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(obj.Array[1], &t.ArrayLen); err != nil {
+			return fmt.Errorf("invalid array length in %s: %w", string(data), err)
+		}
+		t.Array = &elem
+	case len(obj.Defined) > 0:
+		// 0.30+: {"defined": {"name": "X"}}；旧版：{"defined": "X"}
+		var name string
+		if err := json.Unmarshal(obj.Defined, &name); err != nil {
+			var def struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(obj.Defined, &def); err != nil {
+				return fmt.Errorf("invalid defined type %s: %w", string(data), err)
+			}
+			name = def.Name
+		}
+		t.Defined = name
+	default:
+		return fmt.Errorf("unsupported idl type %s", string(data))
+	}
+	return nil
+}
+
+func (t IDLType) MarshalJSON() ([]byte, error) {
+	switch {
+	case t.Vec != nil:
+		return json.Marshal(map[string]any{"vec": t.Vec})
+	case t.Option != nil:
+		return json.Marshal(map[string]any{"option": t.Option})
+	case t.Array != nil:
+		return json.Marshal(map[string]any{"array": []any{t.Array, t.ArrayLen}})
+	case t.Defined != "":
+		return json.Marshal(map[string]any{"defined": map[string]string{"name": t.Defined}})
+	default:
+		return json.Marshal(t.Primitive)
+	}
+}
+
+// LoadIDL 从文件读取 IDL，并为缺失的 discriminator 补齐计算值（兼容旧版 IDL）
+func LoadIDL(path string) (*IDL, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseIDL(data)
+}
+
+// ParseIDL 解析 IDL JSON
+func ParseIDL(data []byte) (*IDL, error) {
+	var idl IDL
+	if err := json.Unmarshal(data, &idl); err != nil {
+		return nil, fmt.Errorf("failed to parse idl: %w", err)
+	}
+	for i := range idl.Instructions {
+		if len(idl.Instructions[i].Discriminator) == 0 {
+			d := InstructionDiscriminator(idl.Instructions[i].Name)
+			idl.Instructions[i].Discriminator = d[:]
+		}
+	}
+	for i := range idl.Accounts {
+		if len(idl.Accounts[i].Discriminator) == 0 {
+			d := AccountDiscriminator(idl.Accounts[i].Name)
+			idl.Accounts[i].Discriminator = d[:]
+		}
+	}
+	for i := range idl.Events {
+		if len(idl.Events[i].Discriminator) == 0 {
+			d := EventDiscriminator(idl.Events[i].Name)
+			idl.Events[i].Discriminator = d[:]
+		}
+	}
+	return &idl, nil
+}
+
+// FindType 按名称查找自定义类型
+func (idl *IDL) FindType(name string) (IDLTypeDef, bool) {
+	for _, t := range idl.Types {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return IDLTypeDef{}, false
+}
@@ -0,0 +1,242 @@
+package anchor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+
+	"sdk/ws"
+)
+
+// EventDecoder 把一条事件数据（discriminator + Borsh）解码为事件名和类型化的值，
+// 生成代码中的 DecodeEvent 即满足该签名。
+type EventDecoder func(data []byte) (string, any, error)
+
+// Event 是监听到的一条已解码事件
+type Event struct {
+	Signature string `json:"signature"`
+	Slot      uint64 `json:"slot"`
+	Program   string `json:"program"`
+	Name      string `json:"name"`
+	Data      any    `json:"data"`
+}
+
+// Listener 监听某个程序发出的 Anchor 事件。
+// 优先使用 logsSubscribe；websocket 不可用或断开时退回 getSignaturesForAddress 轮询，
+// 每次（重新）连接前都会从最后处理的签名开始补齐，因此不会漏掉或重复事件。
+type Listener struct {
+	RPC       *client.Client
+	ProgramID common.PublicKey
+	Decode    EventDecoder
+
+	// WSEndpoint 为空时只轮询
+	WSEndpoint string
+	// Commitment 默认 confirmed
+	Commitment rpc.Commitment
+	// PollInterval 是轮询间隔，也是 websocket 重连的退避时间，默认 5s
+	PollInterval time.Duration
+
+	// Since 是上次处理到的签名；为空时从当前最新交易之后开始
+	Since string
+	// Checkpoint 在每笔交易处理完后调用，可用于持久化游标
+	Checkpoint func(signature string) error
+	// OnError 接收可恢复的错误（断线、解码失败等），默认忽略
+	OnError func(error)
+
+	last string
+	seen map[string]struct{}
+}
+
+// Run 持续监听直到 ctx 取消或 handle 返回错误
+func (l *Listener) Run(ctx context.Context, handle func(Event) error) error {
+	if l.Decode == nil {
+		return errors.New("anchor: listener has no event decoder")
+	}
+	if l.Commitment == "" {
+		l.Commitment = rpc.CommitmentConfirmed
+	}
+	if l.PollInterval <= 0 {
+		l.PollInterval = 5 * time.Second
+	}
+	l.last = l.Since
+	if l.last == "" {
+		sigs, err := l.RPC.GetSignaturesForAddressWithConfig(ctx, l.ProgramID.ToBase58(), client.GetSignaturesForAddressConfig{
+			Limit:      1,
+			Commitment: l.Commitment,
+		})
+		if err != nil {
+			return fmt.Errorf("getSignaturesForAddress: %w", err)
+		}
+		if len(sigs) > 0 {
+			l.last = sigs[0].Signature
+		}
+	}
+
+	for {
+		var err error
+		if l.WSEndpoint != "" {
+			err = l.stream(ctx, handle)
+		} else {
+			err = l.catchUp(ctx, handle)
+		}
+		var he handlerError
+		if errors.As(err, &he) {
+			return he.err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			l.report(err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(l.PollInterval):
+		}
+	}
+}
+
+// handlerError 区分 handle 返回的错误（终止监听）与网络错误（重试）
+type handlerError struct{ err error }
+
+func (e handlerError) Error() string { return e.err.Error() }
+
+func (l *Listener) report(err error) {
+	if l.OnError != nil {
+		l.OnError(err)
+	}
+}
+
+// stream 先订阅再补齐，随后处理推送；补齐期间收到的推送按签名去重
+func (l *Listener) stream(ctx context.Context, handle func(Event) error) error {
+	conn, err := ws.Dial(ctx, l.WSEndpoint)
+	if err != nil {
+		// 连接失败时本轮退回轮询
+		l.report(err)
+		return l.catchUp(ctx, handle)
+	}
+	defer conn.Close()
+	sub, err := conn.LogsSubscribe(ctx, l.ProgramID, l.Commitment)
+	if err != nil {
+		l.report(err)
+		return l.catchUp(ctx, handle)
+	}
+
+	l.seen = map[string]struct{}{}
+	defer func() { l.seen = nil }()
+	if err := l.catchUp(ctx, handle); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case raw, ok := <-sub.C():
+			if !ok {
+				return fmt.Errorf("logsSubscribe: %w", conn.Err())
+			}
+			var n ws.LogsNotification
+			if err := json.Unmarshal(raw, &n); err != nil {
+				l.report(fmt.Errorf("logsNotification: %w", err))
+				continue
+			}
+			if _, dup := l.seen[n.Value.Signature]; dup {
+				continue
+			}
+			if err := l.process(n.Value.Signature, n.Context.Slot, n.Value.Err, n.Value.Logs, handle); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// catchUp 拉取 last 之后的所有签名（按时间正序）并逐笔处理
+func (l *Listener) catchUp(ctx context.Context, handle func(Event) error) error {
+	var pending []rpc.SignatureWithStatus
+	before := ""
+	for {
+		page, err := l.RPC.GetSignaturesForAddressWithConfig(ctx, l.ProgramID.ToBase58(), client.GetSignaturesForAddressConfig{
+			Before:     before,
+			Until:      l.last,
+			Commitment: l.Commitment,
+		})
+		if err != nil {
+			return fmt.Errorf("getSignaturesForAddress: %w", err)
+		}
+		pending = append(pending, page...)
+		// 没有游标时只取最新一页，避免回放整个历史
+		if len(page) < 1000 || l.last == "" {
+			break
+		}
+		before = page[len(page)-1].Signature
+	}
+	for i := len(pending) - 1; i >= 0; i-- {
+		s := pending[i]
+		var logs []string
+		if s.Err == nil {
+			var err error
+			if logs, err = l.transactionLogs(ctx, s.Signature); err != nil {
+				return err
+			}
+		}
+		if err := l.process(s.Signature, s.Slot, s.Err, logs, handle); err != nil {
+			return err
+		}
+		if l.seen != nil {
+			l.seen[s.Signature] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// process 解码一笔交易中本程序的事件；失败交易的事件已随状态回滚，跳过
+func (l *Listener) process(sig string, slot uint64, txErr any, logs []string, handle func(Event) error) error {
+	if txErr == nil {
+		for _, data := range ProgramEvents(logs, l.ProgramID) {
+			name, v, err := l.Decode(data)
+			if err != nil {
+				l.report(fmt.Errorf("tx %s: %w", sig, err))
+				continue
+			}
+			ev := Event{Signature: sig, Slot: slot, Program: l.ProgramID.ToBase58(), Name: name, Data: v}
+			if err := handle(ev); err != nil {
+				return handlerError{err}
+			}
+		}
+	}
+	l.last = sig
+	if l.Checkpoint != nil {
+		if err := l.Checkpoint(sig); err != nil {
+			return handlerError{err}
+		}
+	}
+	return nil
+}
+
+// transactionLogs 只取交易日志；直接用 rpc 层避免解析整笔交易（也兼容 v0 交易）
+func (l *Listener) transactionLogs(ctx context.Context, sig string) ([]string, error) {
+	version := uint8(0)
+	res, err := l.RPC.RpcClient.GetTransactionWithConfig(ctx, sig, rpc.GetTransactionConfig{
+		Encoding:                       rpc.TransactionEncodingBase64,
+		Commitment:                     l.Commitment,
+		MaxSupportedTransactionVersion: &version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getTransaction %s: %w", sig, err)
+	}
+	if res.Error != nil {
+		return nil, fmt.Errorf("getTransaction %s: %w", sig, res.Error)
+	}
+	if res.Result == nil || res.Result.Meta == nil {
+		// 节点尚未能返回该交易，下轮再试
+		return nil, fmt.Errorf("transaction %s not available yet", sig)
+	}
+	return res.Result.Meta.LogMessages, nil
+}
@@ -0,0 +1,214 @@
+package anchor
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/mr-tron/base58"
+)
+
+// SeedKind 是 PDA seed 的编码方式，同时用作 "kind:value" 形式 seed 参数的前缀
+type SeedKind string
+
+const (
+	SeedConst  SeedKind = "const" // 模板中的固定字节
+	SeedString SeedKind = "str"
+	SeedPubkey SeedKind = "pubkey"
+	SeedU8     SeedKind = "u8"
+	SeedU16    SeedKind = "u16le"
+	SeedU32    SeedKind = "u32le"
+	SeedU64    SeedKind = "u64le"
+	SeedI64    SeedKind = "i64le"
+	SeedHex    SeedKind = "hex"
+)
+
+// EncodeSeed 按 kind 编码 seed 文本值，与 Rust 端 as_bytes() / to_le_bytes() / key().as_ref() 一致
+func EncodeSeed(kind SeedKind, value string) ([]byte, error) {
+	switch kind {
+	case SeedString, SeedConst:
+		return []byte(value), nil
+	case SeedPubkey:
+		b, err := base58.Decode(value)
+		if err != nil || len(b) != 32 {
+			return nil, fmt.Errorf("invalid pubkey seed %q", value)
+		}
+		return b, nil
+	case SeedHex:
+		b, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex seed %q: %w", value, err)
+		}
+		return b, nil
+	case SeedU8, SeedU16, SeedU32, SeedU64:
+		bits := map[SeedKind]int{SeedU8: 8, SeedU16: 16, SeedU32: 32, SeedU64: 64}[kind]
+		v, err := strconv.ParseUint(value, 10, bits)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s seed %q: %w", kind, value, err)
+		}
+		return binary.LittleEndian.AppendUint64(nil, v)[:bits/8], nil
+	case SeedI64:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s seed %q: %w", kind, value, err)
+		}
+		return I64Seed(v), nil
+	}
+	return nil, fmt.Errorf("unknown seed kind %q", kind)
+}
+
+// ParseSeed 解析 "kind:value" 形式的 seed，例如 str:favorites、pubkey:<base58>、u64le:7
+func ParseSeed(spec string) ([]byte, error) {
+	kind, value, ok := strings.Cut(spec, ":")
+	if !ok {
+		return nil, fmt.Errorf("seed %q must be kind:value (str, pubkey, u8, u16le, u32le, u64le, i64le, hex)", spec)
+	}
+	b, err := EncodeSeed(SeedKind(kind), value)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > 32 {
+		return nil, fmt.Errorf("seed %q is %d bytes, the limit is 32", spec, len(b))
+	}
+	return b, nil
+}
+
+// SeedSpec 是模板中的一个 seed：SeedConst 取 Value，其余按 Name 对应的变量编码
+type SeedSpec struct {
+	Kind  SeedKind
+	Name  string
+	Value []byte
+}
+
+// PDATemplate 描述程序中一个 PDA 账户的 seeds，由 anchorgen 从 IDL 生成
+type PDATemplate struct {
+	Program   string
+	Account   string
+	ProgramID common.PublicKey
+	Seeds     []SeedSpec
+}
+
+// String 以 ["poll", poll_id:u64le] 的形式展示模板
+func (t PDATemplate) String() string {
+	parts := make([]string, len(t.Seeds))
+	for i, s := range t.Seeds {
+		if s.Kind == SeedConst {
+			parts[i] = strconv.Quote(string(s.Value))
+		} else {
+			parts[i] = s.Name + ":" + string(s.Kind)
+		}
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// PDACandidates 是 FindPDA 反查时尝试的变量取值
+type PDACandidates struct {
+	Pubkeys []common.PublicKey
+	Strings []string
+	// MaxInt 整数类 seed 尝试 0..MaxInt
+	MaxInt uint64
+}
+
+// PDAMatch 是一次反查命中：模板、各变量 seed 的 "kind:value" 以及 bump
+type PDAMatch struct {
+	Template PDATemplate
+	Seeds    []string
+	Bump     uint8
+}
+
+// FindPDA 在模板的变量取值组合中搜索派生出 address 的组合。
+// 组合数为各变量候选数之积，hex 类 seed 无法枚举，对应模板会被跳过。
+func FindPDA(address common.PublicKey, templates []PDATemplate, cand PDACandidates) []PDAMatch {
+	var matches []PDAMatch
+	for _, t := range templates {
+		options := make([][]string, len(t.Seeds))
+		ok := true
+		for i, s := range t.Seeds {
+			options[i] = seedOptions(s, cand)
+			if len(options[i]) == 0 {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		idx := make([]int, len(options))
+		for {
+			seeds := make([][]byte, len(t.Seeds))
+			specs := make([]string, len(t.Seeds))
+			valid := true
+			for i, s := range t.Seeds {
+				b, err := EncodeSeed(s.Kind, options[i][idx[i]])
+				if err != nil || len(b) > 32 {
+					valid = false
+					break
+				}
+				seeds[i] = b
+				specs[i] = seedSpec(s.Kind, b, options[i][idx[i]])
+			}
+			if valid {
+				if got, bump, err := common.FindProgramAddress(seeds, t.ProgramID); err == nil && got == address {
+					matches = append(matches, PDAMatch{Template: t, Seeds: specs, Bump: bump})
+				}
+			}
+			// 进位到下一个组合
+			i := len(idx) - 1
+			for ; i >= 0; i-- {
+				if idx[i]++; idx[i] < len(options[i]) {
+					break
+				}
+				idx[i] = 0
+			}
+			if i < 0 {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+func seedOptions(s SeedSpec, cand PDACandidates) []string {
+	switch s.Kind {
+	case SeedConst:
+		return []string{string(s.Value)}
+	case SeedString:
+		return cand.Strings
+	case SeedPubkey:
+		out := make([]string, len(cand.Pubkeys))
+		for i, pk := range cand.Pubkeys {
+			out[i] = pk.ToBase58()
+		}
+		return out
+	case SeedU8, SeedU16, SeedU32, SeedU64, SeedI64:
+		max := cand.MaxInt
+		if s.Kind == SeedU8 && max > 0xff {
+			max = 0xff
+		}
+		if s.Kind == SeedU16 && max > 0xffff {
+			max = 0xffff
+		}
+		out := make([]string, 0, max+1)
+		for v := uint64(0); v <= max; v++ {
+			out = append(out, strconv.FormatUint(v, 10))
+		}
+		return out
+	}
+	return nil
+}
+
+// seedSpec 把命中的 seed 还原为可传给 ParseSeed 的 "kind:value"
+func seedSpec(kind SeedKind, b []byte, value string) string {
+	if kind != SeedConst {
+		return string(kind) + ":" + value
+	}
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return "hex:" + hex.EncodeToString(b)
+		}
+	}
+	return "str:" + value
+}
@@ -0,0 +1,21 @@
+package anchor
+
+import "encoding/binary"
+
+// 与 Rust 端 `x.to_le_bytes().as_ref()` 对应的 PDA seed 编码
+
+func U16Seed(v uint16) []byte {
+	return binary.LittleEndian.AppendUint16(nil, v)
+}
+
+func U32Seed(v uint32) []byte {
+	return binary.LittleEndian.AppendUint32(nil, v)
+}
+
+func U64Seed(v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(nil, v)
+}
+
+func I64Seed(v int64) []byte {
+	return U64Seed(uint64(v))
+}
@@ -0,0 +1,138 @@
+package anchor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/client"
+)
+
+// LamportsPerSignature 是每个签名的基础交易费
+const LamportsPerSignature = 5000
+
+// MaxLens 对应 Rust 端的 #[max_len(...)] 注解（IDL 中不包含）。
+// 键为 "类型名.字段名"，值按嵌套顺序给出长度：#[max_len(10, 50)] Vec<String> 为 {10, 50}。
+type MaxLens map[string][]int
+
+// AccountSpace 计算账户的最大序列化大小（含 8 字节 discriminator），
+// 与 `8 + T::INIT_SPACE` 一致
+func (idl *IDL) AccountSpace(account string, lens MaxLens) (int, error) {
+	n, err := idl.definedSpace(account, lens)
+	if err != nil {
+		return 0, err
+	}
+	return DiscriminatorSize + n, nil
+}
+
+// definedSpace 按 InitSpace 规则计算自定义类型的最大大小：结构体为字段之和，枚举为 1 + 最大变体
+func (idl *IDL) definedSpace(name string, lens MaxLens) (int, error) {
+	var def *IDLTypeDef
+	for i := range idl.Types {
+		if idl.Types[i].Name == name {
+			def = &idl.Types[i]
+		}
+	}
+	if def == nil {
+		return 0, fmt.Errorf("type %s not found in idl", name)
+	}
+	fieldsSpace := func(fields []IDLField) (int, error) {
+		total := 0
+		for _, f := range fields {
+			n, err := idl.typeSpace(f.Type, lens[name+"."+f.Name], name+"."+f.Name, lens)
+			if err != nil {
+				return 0, err
+			}
+			total += n
+		}
+		return total, nil
+	}
+	switch def.Type.Kind {
+	case "struct":
+		return fieldsSpace(def.Type.Fields)
+	case "enum":
+		largest := 0
+		for _, v := range def.Type.Variants {
+			n, err := fieldsSpace(v.Fields)
+			if err != nil {
+				return 0, err
+			}
+			largest = max(largest, n)
+		}
+		return 1 + largest, nil
+	}
+	return 0, fmt.Errorf("type %s: unsupported kind %q", name, def.Type.Kind)
+}
+
+// typeSpace 计算单个类型的最大大小；maxLen 是该字段剩余的 max_len 参数
+func (idl *IDL) typeSpace(t IDLType, maxLen []int, path string, lens MaxLens) (int, error) {
+	switch {
+	case t.Vec != nil:
+		if len(maxLen) == 0 {
+			return 0, fmt.Errorf("%s: vec needs a #[max_len] annotation", path)
+		}
+		elem, err := idl.typeSpace(*t.Vec, maxLen[1:], path, lens)
+		if err != nil {
+			return 0, err
+		}
+		return 4 + maxLen[0]*elem, nil
+	case t.Option != nil:
+		n, err := idl.typeSpace(*t.Option, maxLen, path, lens)
+		return 1 + n, err
+	case t.Array != nil:
+		n, err := idl.typeSpace(*t.Array, maxLen, path, lens)
+		return t.ArrayLen * n, err
+	case t.Defined != "":
+		return idl.definedSpace(t.Defined, lens)
+	}
+	switch t.Primitive {
+	case "bool", "u8", "i8":
+		return 1, nil
+	case "u16", "i16":
+		return 2, nil
+	case "u32", "i32", "f32":
+		return 4, nil
+	case "u64", "i64", "f64":
+		return 8, nil
+	case "u128", "i128":
+		return 16, nil
+	case "pubkey":
+		return 32, nil
+	case "string", "bytes":
+		if len(maxLen) == 0 {
+			return 0, fmt.Errorf("%s: %s needs a #[max_len] annotation", path, t.Primitive)
+		}
+		return 4 + maxLen[0], nil
+	}
+	return 0, fmt.Errorf("%s: unsupported type %q", path, t.Primitive)
+}
+
+// AccountCost 是创建账户所需的 lamports
+type AccountCost struct {
+	Account string `json:"account,omitempty"`
+	Space   int    `json:"space"`
+	// Rent 是免租所需的最低余额，由账户创建时从 payer 转入
+	Rent uint64 `json:"rentExemptLamports"`
+	// Fee 是单签名交易的基础手续费
+	Fee uint64 `json:"fee"`
+	// Required 是调用 initialize 前 payer 至少需要持有的 lamports
+	Required uint64 `json:"required"`
+}
+
+// AccountRent 计算账户大小并通过 getMinimumBalanceForRentExemption 查询免租余额
+func (idl *IDL) AccountRent(ctx context.Context, c *client.Client, account string, lens MaxLens) (AccountCost, error) {
+	space, err := idl.AccountSpace(account, lens)
+	if err != nil {
+		return AccountCost{}, err
+	}
+	rent, err := c.GetMinimumBalanceForRentExemption(ctx, uint64(space))
+	if err != nil {
+		return AccountCost{}, fmt.Errorf("failed to get rent-exempt minimum: %w", err)
+	}
+	return AccountCost{
+		Account:  account,
+		Space:    space,
+		Rent:     rent,
+		Fee:      LamportsPerSignature,
+		Required: rent + LamportsPerSignature,
+	}, nil
+}
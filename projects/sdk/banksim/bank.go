@@ -0,0 +1,362 @@
+// Package banksim 是进程内的 Solana bank 模拟器，用于在没有 validator 的情况下测试客户端逻辑。
+// 账户保存在内存中；交易需通过 ed25519 签名与 blockhash 校验，按真实规则收取手续费
+// （每签名费用 + ComputeBudget 优先费）并检查租金状态转换，再执行 System 程序的
+// CreateAccount / Assign / Transfer / Allocate 指令（ComputeBudget 与 Memo 视为空操作）。
+//
+// Handler 提供 JSON-RPC 接口，client.NewClient 可直接指向它：
+//
+//	bank := banksim.New(banksim.Config{})
+//	bank.Fund(payer.PublicKey, 10_000_000_000)
+//	srv := httptest.NewServer(bank.Handler())
+//	c := client.NewClient(srv.URL)
+//
+// 每笔成功处理的交易或空投都会推进一个 slot 并产生新的 blockhash，结果完全确定。
+package banksim
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+)
+
+const (
+	// DefaultLamportsPerSignature 是主网当前的每签名手续费
+	DefaultLamportsPerSignature = 5000
+	// DefaultMaxBlockhashAge 是 blockhash 的有效 slot 数
+	DefaultMaxBlockhashAge = 150
+
+	// 租金参数与主网一致：每字节每年 3480 lamports，豁免阈值 2 年，账户元数据按 128 字节计
+	lamportsPerByteYear     = 3480
+	exemptionThresholdYears = 2
+	accountStorageOverhead  = 128
+
+	slotDuration = 400 * time.Millisecond
+)
+
+// Account 是一个链上账户
+type Account struct {
+	Lamports   uint64
+	Owner      common.PublicKey
+	Data       []byte
+	Executable bool
+}
+
+func (a Account) clone() Account {
+	a.Data = append([]byte(nil), a.Data...)
+	return a
+}
+
+// Config 为模拟器参数；零值字段使用默认值
+type Config struct {
+	LamportsPerSignature uint64
+	MaxBlockhashAge      uint64
+	GenesisTime          time.Time // slot 0 的区块时间，默认 Unix 纪元
+}
+
+// TransactionError 是交易被拒绝或执行失败的原因，Err 与 RPC 返回的 err 字段格式一致，
+// 例如 "BlockhashNotFound" 或 {"InstructionError":[0,{"Custom":1}]}
+type TransactionError struct {
+	Err  any
+	Logs []string
+}
+
+func (e *TransactionError) Error() string {
+	b, _ := json.Marshal(e.Err)
+	return "banksim: transaction failed: " + string(b)
+}
+
+// ErrSignatureFailure 表示签名数量不符或 ed25519 校验失败
+var ErrSignatureFailure = errors.New("banksim: signature verification failed")
+
+type signatureStatus struct {
+	Slot uint64
+	Err  any
+}
+
+type blockhash struct {
+	Hash string
+	Slot uint64
+}
+
+// Bank 是模拟器状态，可被多个 goroutine 并发使用
+type Bank struct {
+	mu       sync.Mutex
+	cfg      Config
+	accounts map[common.PublicKey]Account
+	slot     uint64
+	hashes   []blockhash // 最近的 blockhash，旧的在前
+	statuses map[string]signatureStatus
+}
+
+// New 创建只有 slot 0 的空 bank
+func New(cfg Config) *Bank {
+	if cfg.LamportsPerSignature == 0 {
+		cfg.LamportsPerSignature = DefaultLamportsPerSignature
+	}
+	if cfg.MaxBlockhashAge == 0 {
+		cfg.MaxBlockhashAge = DefaultMaxBlockhashAge
+	}
+	if cfg.GenesisTime.IsZero() {
+		cfg.GenesisTime = time.Unix(0, 0)
+	}
+	b := &Bank{
+		cfg:      cfg,
+		accounts: map[common.PublicKey]Account{},
+		statuses: map[string]signatureStatus{},
+	}
+	b.hashes = []blockhash{{Hash: hashForSlot(0), Slot: 0}}
+	return b
+}
+
+// RentExemptMinimum 返回 dataLen 字节账户免租所需的最低余额
+func RentExemptMinimum(dataLen uint64) uint64 {
+	return (accountStorageOverhead + dataLen) * lamportsPerByteYear * exemptionThresholdYears
+}
+
+// SetAccount 直接写入账户，Lamports 为 0 时删除
+func (b *Bank) SetAccount(pubkey common.PublicKey, acc Account) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.store(pubkey, acc.clone())
+}
+
+// GetAccount 返回账户副本；账户不存在时 ok 为 false
+func (b *Bank) GetAccount(pubkey common.PublicKey) (acc Account, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	acc, ok = b.accounts[pubkey]
+	return acc.clone(), ok
+}
+
+// Balance 返回账户余额，不存在的账户为 0
+func (b *Bank) Balance(pubkey common.PublicKey) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.accounts[pubkey].Lamports
+}
+
+// Fund 给 System 账户增加余额（账户不存在时创建），不产生交易
+func (b *Bank) Fund(pubkey common.PublicKey, lamports uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	acc, ok := b.accounts[pubkey]
+	if !ok {
+		acc.Owner = common.SystemProgramID
+	}
+	acc.Lamports += lamports
+	b.store(pubkey, acc)
+}
+
+// Airdrop 与 requestAirdrop 相同：增加余额、记录一个确定的签名并推进一个 slot
+func (b *Bank) Airdrop(pubkey common.PublicKey, lamports uint64) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	acc, ok := b.accounts[pubkey]
+	if !ok {
+		acc.Owner = common.SystemProgramID
+	}
+	acc.Lamports += lamports
+	b.store(pubkey, acc)
+
+	var seed [48]byte
+	copy(seed[:32], pubkey.Bytes())
+	binary.LittleEndian.PutUint64(seed[32:], lamports)
+	binary.LittleEndian.PutUint64(seed[40:], b.slot)
+	sum := sha512.Sum512(append([]byte("banksim airdrop"), seed[:]...))
+	sig := base58.Encode(sum[:])
+	b.statuses[sig] = signatureStatus{Slot: b.slot}
+	b.advance()
+	return sig
+}
+
+// Slot 返回当前 slot（也用作区块高度）
+func (b *Bank) Slot() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.slot
+}
+
+// BlockTime 返回 slot 对应的区块时间
+func (b *Bank) BlockTime(slot uint64) time.Time {
+	return b.cfg.GenesisTime.Add(time.Duration(slot) * slotDuration)
+}
+
+// LatestBlockhash 返回最新 blockhash 及其最后有效的区块高度
+func (b *Bank) LatestBlockhash() (hash string, lastValidBlockHeight uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	latest := b.hashes[len(b.hashes)-1]
+	return latest.Hash, latest.Slot + b.cfg.MaxBlockhashAge
+}
+
+// IsBlockhashValid 判断 blockhash 是否仍在有效窗口内
+func (b *Bank) IsBlockhashValid(hash string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.blockhashValid(hash)
+}
+
+// Advance 空推进 n 个 slot，可用于让 blockhash 过期
+func (b *Bank) Advance(n uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := uint64(0); i < n; i++ {
+		b.advance()
+	}
+}
+
+// SignatureStatus 返回签名所在 slot 与执行错误；未知签名时 ok 为 false
+func (b *Bank) SignatureStatus(sig string) (slot uint64, txErr any, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.statuses[sig]
+	return s.Slot, s.Err, ok
+}
+
+// FeeForMessage 计算消息的手续费；blockhash 已失效时 ok 为 false
+func (b *Bank) FeeForMessage(msg types.Message) (fee uint64, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.blockhashValid(msg.RecentBlockHash) {
+		return 0, false
+	}
+	return b.fee(msg), true
+}
+
+// ProcessTransaction 校验并执行一笔序列化的交易，返回第一个签名。
+// 预检（skipPreflight 为 false）时执行失败的交易不落账并返回 *TransactionError；
+// 跳过预检时失败交易仍收取手续费并记录错误状态，与 validator 行为一致。
+func (b *Bank) ProcessTransaction(raw []byte, skipPreflight bool) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tx, msgBytes, err := decodeTransaction(raw)
+	if err != nil {
+		return "", err
+	}
+	sig := base58.Encode(tx.Signatures[0])
+	if err := verifySignatures(tx, msgBytes); err != nil {
+		return sig, err
+	}
+	if _, seen := b.statuses[sig]; seen {
+		return sig, &TransactionError{Err: "AlreadyProcessed"}
+	}
+	if !b.blockhashValid(tx.Message.RecentBlockHash) {
+		return sig, &TransactionError{Err: "BlockhashNotFound"}
+	}
+	res, err := b.execute(tx.Message)
+	if err != nil {
+		return sig, err
+	}
+	if res.err != nil && !skipPreflight {
+		return sig, &TransactionError{Err: res.err, Logs: res.logs}
+	}
+	res.commit(b)
+	b.statuses[sig] = signatureStatus{Slot: b.slot, Err: res.err}
+	b.advance()
+	return sig, nil
+}
+
+// SimulateTransaction 执行交易但不落账，返回执行错误（成功时为 nil）与日志。
+// sigVerify 为 false 时跳过签名校验；replaceBlockhash 为 true 时不检查 blockhash。
+func (b *Bank) SimulateTransaction(raw []byte, sigVerify, replaceBlockhash bool) (txErr any, logs []string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tx, msgBytes, err := decodeTransaction(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sigVerify {
+		if err := verifySignatures(tx, msgBytes); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !replaceBlockhash && !b.blockhashValid(tx.Message.RecentBlockHash) {
+		return "BlockhashNotFound", nil, nil
+	}
+	res, err := b.execute(tx.Message)
+	if err != nil {
+		var te *TransactionError
+		if errors.As(err, &te) {
+			return te.Err, te.Logs, nil
+		}
+		return nil, nil, err
+	}
+	return res.err, res.logs, nil
+}
+
+// store 写入账户；余额为 0 的账户视为已关闭
+func (b *Bank) store(pubkey common.PublicKey, acc Account) {
+	if acc.Lamports == 0 {
+		delete(b.accounts, pubkey)
+		return
+	}
+	b.accounts[pubkey] = acc
+}
+
+func (b *Bank) advance() {
+	b.slot++
+	b.hashes = append(b.hashes, blockhash{Hash: hashForSlot(b.slot), Slot: b.slot})
+	if uint64(len(b.hashes)) > b.cfg.MaxBlockhashAge+1 {
+		b.hashes = b.hashes[1:]
+	}
+}
+
+func (b *Bank) blockhashValid(hash string) bool {
+	for _, h := range b.hashes {
+		if h.Hash == hash {
+			return b.slot-h.Slot <= b.cfg.MaxBlockhashAge
+		}
+	}
+	return false
+}
+
+// hashForSlot 由 slot 确定地派生 blockhash
+func hashForSlot(slot uint64) string {
+	sum := sha256.Sum256([]byte("banksim:" + strconv.FormatUint(slot, 10)))
+	return base58.Encode(sum[:])
+}
+
+// decodeTransaction 反序列化交易并返回签名所覆盖的消息字节
+func decodeTransaction(raw []byte) (types.Transaction, []byte, error) {
+	tx, err := types.TransactionDeserialize(raw)
+	if err != nil {
+		return types.Transaction{}, nil, fmt.Errorf("banksim: invalid transaction: %w", err)
+	}
+	if len(tx.Message.AddressLookupTables) > 0 {
+		return types.Transaction{}, nil, errors.New("banksim: address lookup tables are not supported")
+	}
+	// 与 validator 的 sanitize 规则一致：至少一个签名者，且付费账户（第一个签名者）可写
+	h := tx.Message.Header
+	if len(tx.Signatures) == 0 || h.NumRequireSignatures == 0 || h.NumReadonlySignedAccounts >= h.NumRequireSignatures {
+		return types.Transaction{}, nil, errors.New("banksim: invalid transaction: the fee payer must be a writable signer")
+	}
+	// 签名数量是 compact-u16，不超过 127 时占 1 字节
+	prefix := 1 + 64*len(tx.Signatures)
+	if len(tx.Signatures) > 127 {
+		prefix++
+	}
+	return tx, raw[prefix:], nil
+}
+
+func verifySignatures(tx types.Transaction, msg []byte) error {
+	if len(tx.Signatures) != int(tx.Message.Header.NumRequireSignatures) || len(tx.Message.Accounts) < len(tx.Signatures) {
+		return ErrSignatureFailure
+	}
+	for i, sig := range tx.Signatures {
+		if !ed25519.Verify(tx.Message.Accounts[i].Bytes(), msg, sig) {
+			return fmt.Errorf("%w: signature %d (%s)", ErrSignatureFailure, i, tx.Message.Accounts[i].ToBase58())
+		}
+	}
+	return nil
+}
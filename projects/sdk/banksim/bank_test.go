@@ -0,0 +1,340 @@
+package banksim
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/cmptbdgprog"
+	"github.com/blocto/solana-go-sdk/program/sysprog"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// message 以 bank 当前的 blockhash 构建消息
+func message(b *Bank, payer common.PublicKey, ixs ...types.Instruction) types.Message {
+	hash, _ := b.LatestBlockhash()
+	return types.NewMessage(types.NewMessageParam{FeePayer: payer, RecentBlockhash: hash, Instructions: ixs})
+}
+
+// sign 对消息签名并序列化
+func sign(t *testing.T, msg types.Message, signers ...types.Account) []byte {
+	t.Helper()
+	tx, err := types.NewTransaction(types.NewTransactionParam{Message: msg, Signers: signers})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func transfer(from, to common.PublicKey, lamports uint64) types.Instruction {
+	return sysprog.Transfer(sysprog.TransferParam{From: from, To: to, Amount: lamports})
+}
+
+func memo() types.Instruction {
+	return types.Instruction{ProgramID: common.MemoProgramID, Data: []byte("banksim")}
+}
+
+// process 处理交易并只返回错误
+func process(b *Bank, raw []byte, skipPreflight bool) error {
+	_, err := b.ProcessTransaction(raw, skipPreflight)
+	return err
+}
+
+// txError 断言 err 是 *TransactionError 并返回其 Err
+func txError(t *testing.T, err error) any {
+	t.Helper()
+	var te *TransactionError
+	if !errors.As(err, &te) {
+		t.Fatalf("got %v, want a *TransactionError", err)
+	}
+	return te.Err
+}
+
+func rentError(index int) any {
+	return map[string]any{"InsufficientFundsForRent": map[string]any{"account_index": index}}
+}
+
+func TestSignatureVerification(t *testing.T) {
+	b := New(Config{})
+	payer, other, to := types.NewAccount(), types.NewAccount(), types.NewAccount()
+	b.Fund(payer.PublicKey, 1_000_000_000)
+	msg := message(b, payer.PublicKey, transfer(payer.PublicKey, to.PublicKey, 1_000_000))
+
+	forged := sign(t, msg, types.Account{PublicKey: payer.PublicKey, PrivateKey: other.PrivateKey})
+	if _, err := b.ProcessTransaction(forged, false); !errors.Is(err, ErrSignatureFailure) {
+		t.Fatalf("forged signature: %v", err)
+	}
+	if _, err := b.ProcessTransaction(forged, true); !errors.Is(err, ErrSignatureFailure) {
+		t.Fatalf("forged signature with skipPreflight: %v", err)
+	}
+	if _, _, err := b.SimulateTransaction(forged, true, false); !errors.Is(err, ErrSignatureFailure) {
+		t.Fatalf("simulate with sigVerify: %v", err)
+	}
+	// 关闭 sigVerify 的模拟与 RPC 一致，不校验签名
+	if txErr, _, err := b.SimulateTransaction(forged, false, false); err != nil || txErr != nil {
+		t.Fatalf("simulate without sigVerify: %v, %v", txErr, err)
+	}
+
+	// 消息要求两个签名，只带一个：反序列化时即被拒绝
+	full := sign(t, message(b, payer.PublicKey, transfer(other.PublicKey, to.PublicKey, 1)), payer, other)
+	raw := append([]byte{1}, full[1:65]...)
+	raw = append(raw, full[129:]...)
+	if _, err := b.ProcessTransaction(raw, false); err == nil {
+		t.Fatalf("missing signature: %v", err)
+	}
+
+	if got := b.Balance(payer.PublicKey); got != 1_000_000_000 {
+		t.Fatalf("rejected transactions charged %d lamports", 1_000_000_000-got)
+	}
+}
+
+func TestReadonlyFeePayer(t *testing.T) {
+	b := New(Config{})
+	payer, to := types.NewAccount(), types.NewAccount()
+	b.Fund(payer.PublicKey, 1_000_000_000)
+	msg := message(b, payer.PublicKey, memo())
+	msg.Header.NumReadonlySignedAccounts = 1
+	raw := sign(t, msg, payer)
+
+	_, err := b.ProcessTransaction(raw, true)
+	if err == nil || !strings.Contains(err.Error(), "writable signer") {
+		t.Fatalf("read-only fee payer: %v", err)
+	}
+	if _, _, err := b.SimulateTransaction(raw, false, true); err == nil {
+		t.Fatal("simulated a transaction with a read-only fee payer")
+	}
+	if got := b.Balance(payer.PublicKey); got != 1_000_000_000 {
+		t.Fatalf("read-only fee payer was charged %d lamports", 1_000_000_000-got)
+	}
+
+	// 同一消息付费账户可写时正常处理
+	ok := sign(t, message(b, payer.PublicKey, transfer(payer.PublicKey, to.PublicKey, 1_000_000)), payer)
+	if _, err := b.ProcessTransaction(ok, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBlockhashWindow(t *testing.T) {
+	b := New(Config{})
+	payer := types.NewAccount()
+	b.Fund(payer.PublicKey, 1_000_000_000)
+
+	hash, lastValid := b.LatestBlockhash()
+	if lastValid != DefaultMaxBlockhashAge {
+		t.Fatalf("last valid block height %d, want %d", lastValid, DefaultMaxBlockhashAge)
+	}
+	msg := message(b, payer.PublicKey, memo())
+
+	// 窗口最后一个 slot 仍然有效
+	b.Advance(DefaultMaxBlockhashAge)
+	if !b.IsBlockhashValid(hash) {
+		t.Fatal("blockhash expired inside its window")
+	}
+	if _, ok := b.FeeForMessage(msg); !ok {
+		t.Fatal("FeeForMessage rejected a valid blockhash")
+	}
+
+	b.Advance(1)
+	if b.IsBlockhashValid(hash) {
+		t.Fatal("blockhash is valid past its window")
+	}
+	if _, ok := b.FeeForMessage(msg); ok {
+		t.Fatal("FeeForMessage accepted an expired blockhash")
+	}
+	raw := sign(t, msg, payer)
+	if got := txError(t, process(b, raw, true)); got != "BlockhashNotFound" {
+		t.Fatalf("expired blockhash: %v", got)
+	}
+	if txErr, _, err := b.SimulateTransaction(raw, true, false); err != nil || txErr != "BlockhashNotFound" {
+		t.Fatalf("simulate with an expired blockhash: %v, %v", txErr, err)
+	}
+	// replaceRecentBlockhash 跳过检查
+	if txErr, _, err := b.SimulateTransaction(raw, true, true); err != nil || txErr != nil {
+		t.Fatalf("simulate with replaceRecentBlockhash: %v, %v", txErr, err)
+	}
+	if b.IsBlockhashValid("not-a-blockhash") {
+		t.Fatal("an unknown blockhash is valid")
+	}
+
+	// 同一笔交易只处理一次
+	fresh := sign(t, message(b, payer.PublicKey, memo()), payer)
+	sig, err := b.ProcessTransaction(fresh, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ProcessTransaction(fresh, false); txError(t, err) != "AlreadyProcessed" {
+		t.Fatalf("replayed transaction: %v", err)
+	}
+	if slot, txErr, ok := b.SignatureStatus(sig); !ok || txErr != nil || slot != b.Slot()-1 {
+		t.Fatalf("status slot %d err %v ok %v", slot, txErr, ok)
+	}
+}
+
+func TestFees(t *testing.T) {
+	payer, other, to := types.NewAccount(), types.NewAccount(), types.NewAccount()
+	price := func(micro uint64) types.Instruction {
+		return cmptbdgprog.SetComputeUnitPrice(cmptbdgprog.SetComputeUnitPriceParam{MicroLamports: micro})
+	}
+	limit := func(units uint32) types.Instruction {
+		return cmptbdgprog.SetComputeUnitLimit(cmptbdgprog.SetComputeUnitLimitParam{Units: units})
+	}
+	send := transfer(payer.PublicKey, to.PublicKey, 1_000_000)
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		ixs     []types.Instruction
+		signers []types.Account
+		want    uint64
+	}{
+		{"one signature", Config{}, []types.Instruction{send}, []types.Account{payer}, 5000},
+		{"two signatures", Config{}, []types.Instruction{send, transfer(other.PublicKey, to.PublicKey, 1_000_000)}, []types.Account{payer, other}, 10_000},
+		{"configured signature fee", Config{LamportsPerSignature: 10}, []types.Instruction{send}, []types.Account{payer}, 10},
+		// 200_000 单元 × 1000 micro-lamports = 200 lamports
+		{"priority fee", Config{}, []types.Instruction{limit(200_000), price(1000), send}, []types.Account{payer}, 5200},
+		// 未设上限时按每条非 ComputeBudget 指令 200_000 单元计
+		{"default unit limit", Config{}, []types.Instruction{price(1000), send, memo()}, []types.Account{payer}, 5400},
+		// 不足 1 lamport 的优先费向上取整
+		{"priority fee rounds up", Config{}, []types.Instruction{limit(1), price(3), send}, []types.Account{payer}, 5001},
+		{"unit limit capped", Config{}, []types.Instruction{limit(2_000_000), price(1_000_000), send}, []types.Account{payer}, 5000 + 1_400_000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.cfg)
+			b.Fund(payer.PublicKey, 1_000_000_000)
+			b.Fund(other.PublicKey, 1_000_000_000)
+			msg := message(b, payer.PublicKey, tt.ixs...)
+			if fee, ok := b.FeeForMessage(msg); !ok || fee != tt.want {
+				t.Fatalf("FeeForMessage = %d, %v; want %d", fee, ok, tt.want)
+			}
+			if _, err := b.ProcessTransaction(sign(t, msg, tt.signers...), false); err != nil {
+				t.Fatal(err)
+			}
+			// 只有付费账户支付手续费
+			if got, want := b.Balance(payer.PublicKey), 1_000_000_000-1_000_000-tt.want; got != want {
+				t.Fatalf("payer has %d lamports, want %d", got, want)
+			}
+			if got, want := b.Balance(other.PublicKey), uint64(1_000_000_000); len(tt.signers) > 1 && got != want-1_000_000 {
+				t.Fatalf("second signer has %d lamports, want %d", got, want-1_000_000)
+			}
+		})
+	}
+}
+
+func TestFeePayerLoadErrors(t *testing.T) {
+	b := New(Config{})
+	payer := types.NewAccount()
+	program := types.NewAccount().PublicKey
+
+	if err := process(b, sign(t, message(b, payer.PublicKey, memo()), payer), true); txError(t, err) != "AccountNotFound" {
+		t.Fatalf("unfunded payer: %v", err)
+	}
+	b.Fund(payer.PublicKey, DefaultLamportsPerSignature-1)
+	if err := process(b, sign(t, message(b, payer.PublicKey, memo()), payer), true); txError(t, err) != "InsufficientFundsForFee" {
+		t.Fatalf("payer short of the fee: %v", err)
+	}
+	// 加载阶段的失败即使跳过预检也不收费
+	if got := b.Balance(payer.PublicKey); got != DefaultLamportsPerSignature-1 {
+		t.Fatalf("payer was charged: %d lamports left", got)
+	}
+
+	owned := types.NewAccount()
+	b.SetAccount(owned.PublicKey, Account{Lamports: 1_000_000_000, Owner: program})
+	if err := process(b, sign(t, message(b, owned.PublicKey, memo()), owned), true); txError(t, err) != "InvalidAccountForFee" {
+		t.Fatalf("program-owned payer: %v", err)
+	}
+}
+
+func TestRentExemption(t *testing.T) {
+	rent := RentExemptMinimum(0)
+	fee := uint64(DefaultLamportsPerSignature)
+
+	t.Run("new account below the minimum", func(t *testing.T) {
+		b := New(Config{})
+		payer, to := types.NewAccount(), types.NewAccount()
+		b.Fund(payer.PublicKey, 1_000_000_000)
+		raw := sign(t, message(b, payer.PublicKey, transfer(payer.PublicKey, to.PublicKey, rent-1)), payer)
+
+		// 账户顺序：付费账户、收款方、System 程序
+		if _, err := b.ProcessTransaction(raw, false); !reflect.DeepEqual(txError(t, err), rentError(1)) {
+			t.Fatalf("preflight: %v", err)
+		}
+		if got := b.Balance(payer.PublicKey); got != 1_000_000_000 {
+			t.Fatalf("failed preflight charged %d lamports", 1_000_000_000-got)
+		}
+
+		// 跳过预检时失败交易落账：只扣手续费并记录错误
+		sig, err := b.ProcessTransaction(raw, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, txErr, ok := b.SignatureStatus(sig); !ok || !reflect.DeepEqual(txErr, rentError(1)) {
+			t.Fatalf("status err %v ok %v", txErr, ok)
+		}
+		if got := b.Balance(payer.PublicKey); got != 1_000_000_000-fee {
+			t.Fatalf("payer has %d lamports, want %d", got, 1_000_000_000-fee)
+		}
+		if got := b.Balance(to.PublicKey); got != 0 {
+			t.Fatalf("recipient received %d lamports", got)
+		}
+	})
+
+	t.Run("sender left below the minimum", func(t *testing.T) {
+		b := New(Config{})
+		payer, to := types.NewAccount(), types.NewAccount()
+		b.Fund(payer.PublicKey, 1_000_000_000)
+		amount := 1_000_000_000 - fee - rent + 1
+		raw := sign(t, message(b, payer.PublicKey, transfer(payer.PublicKey, to.PublicKey, amount)), payer)
+		if _, err := b.ProcessTransaction(raw, false); !reflect.DeepEqual(txError(t, err), rentError(0)) {
+			t.Fatalf("got %v", err)
+		}
+
+		// 恰好留下免租最低额，或转空账户，都是允许的
+		for _, keep := range []uint64{rent, 0} {
+			b := New(Config{})
+			b.Fund(payer.PublicKey, 1_000_000_000)
+			raw := sign(t, message(b, payer.PublicKey, transfer(payer.PublicKey, to.PublicKey, 1_000_000_000-fee-keep)), payer)
+			if _, err := b.ProcessTransaction(raw, false); err != nil {
+				t.Fatalf("keeping %d lamports: %v", keep, err)
+			}
+			if _, ok := b.GetAccount(payer.PublicKey); ok != (keep > 0) {
+				t.Fatalf("keeping %d lamports: account exists %v", keep, ok)
+			}
+		}
+	})
+
+	t.Run("rent-paying account", func(t *testing.T) {
+		b := New(Config{})
+		payer, poor := types.NewAccount(), types.NewAccount()
+		b.Fund(payer.PublicKey, 1_000_000_000)
+		b.Fund(poor.PublicKey, rent/2)
+
+		// 已欠租的账户可以支付手续费，但不能收款后仍欠租
+		if _, err := b.ProcessTransaction(sign(t, message(b, poor.PublicKey, memo()), poor), false); err != nil {
+			t.Fatalf("rent-paying fee payer: %v", err)
+		}
+		raw := sign(t, message(b, payer.PublicKey, transfer(payer.PublicKey, poor.PublicKey, 1)), payer)
+		if _, err := b.ProcessTransaction(raw, false); !reflect.DeepEqual(txError(t, err), rentError(1)) {
+			t.Fatalf("topping up a rent-paying account: %v", err)
+		}
+	})
+
+	t.Run("fee alone leaves the payer below the minimum", func(t *testing.T) {
+		b := New(Config{})
+		payer := types.NewAccount()
+		b.Fund(payer.PublicKey, rent)
+		raw := sign(t, message(b, payer.PublicKey, memo()), payer)
+		if _, err := b.ProcessTransaction(raw, true); !reflect.DeepEqual(txError(t, err), rentError(0)) {
+			t.Fatalf("got %v", err)
+		}
+		if got := b.Balance(payer.PublicKey); got != rent {
+			t.Fatalf("payer was charged: %d lamports left", got)
+		}
+	})
+}
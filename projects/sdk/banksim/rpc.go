@@ -0,0 +1,337 @@
+package banksim
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+)
+
+// JSON-RPC 错误码，与 validator 一致
+const (
+	codeInvalidRequest   = -32600
+	codeMethodNotFound   = -32601
+	codeInvalidParams    = -32602
+	codeParseError       = -32700
+	codeSimulationFailed = -32002
+	codeSignatureFailure = -32003
+)
+
+type rpcRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// Handler 返回 JSON-RPC 2.0 处理器，支持 client.Client 常用的方法：
+// getHealth getVersion getSlot getBlockHeight getLatestBlockhash isBlockhashValid
+// getBalance getAccountInfo getMultipleAccounts getMinimumBalanceForRentExemption
+// getFeeForMessage requestAirdrop sendTransaction simulateTransaction getSignatureStatuses。
+// 也接受批量请求（JSON 数组）。
+func (b *Bank) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "use POST", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		var batch []rpcRequest
+		if err := json.Unmarshal(body, &batch); err == nil {
+			out := make([]rpcResponse, len(batch))
+			for i, req := range batch {
+				out[i] = b.serve(req)
+			}
+			_ = json.NewEncoder(w).Encode(out)
+			return
+		}
+		var req rpcRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			_ = json.NewEncoder(w).Encode(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"),
+				Error: &rpcError{Code: codeParseError, Message: "Parse error"}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(b.serve(req))
+	})
+}
+
+func (b *Bank) serve(req rpcRequest) rpcResponse {
+	resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	if len(resp.ID) == 0 {
+		resp.ID = json.RawMessage("null")
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &rpcError{Code: codeInvalidRequest, Message: "Invalid request"}
+		return resp
+	}
+	result, err := b.call(req.Method, req.Params)
+	if err != nil {
+		var re *rpcError
+		if !errors.As(err, &re) {
+			re = &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		resp.Error = re
+		return resp
+	}
+	resp.Result = result
+	return resp
+}
+
+// withContext 包装成 {"context":{"slot":n},"value":v}
+func (b *Bank) withContext(v any) any {
+	return map[string]any{"context": map[string]any{"slot": b.Slot()}, "value": v}
+}
+
+func (b *Bank) call(method string, params []json.RawMessage) (any, error) {
+	switch method {
+	case "getHealth":
+		return "ok", nil
+	case "getVersion":
+		return map[string]any{"solana-core": "banksim", "feature-set": 0}, nil
+	case "getSlot", "getBlockHeight":
+		return b.Slot(), nil
+	case "getLatestBlockhash":
+		hash, last := b.LatestBlockhash()
+		return b.withContext(map[string]any{"blockhash": hash, "lastValidBlockHeight": last}), nil
+	case "isBlockhashValid":
+		var hash string
+		if err := param(params, 0, &hash); err != nil {
+			return nil, err
+		}
+		return b.withContext(b.IsBlockhashValid(hash)), nil
+	case "getBalance":
+		key, err := pubkeyParam(params, 0)
+		if err != nil {
+			return nil, err
+		}
+		return b.withContext(b.Balance(key)), nil
+	case "getAccountInfo":
+		key, err := pubkeyParam(params, 0)
+		if err != nil {
+			return nil, err
+		}
+		var cfg struct{ Encoding string }
+		_ = param(params, 1, &cfg)
+		return b.withContext(b.accountJSON(key, cfg.Encoding)), nil
+	case "getMultipleAccounts":
+		var keys []string
+		if err := param(params, 0, &keys); err != nil {
+			return nil, err
+		}
+		var cfg struct{ Encoding string }
+		_ = param(params, 1, &cfg)
+		out := make([]any, len(keys))
+		for i, k := range keys {
+			key, err := parsePubkey(k)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = b.accountJSON(key, cfg.Encoding)
+		}
+		return b.withContext(out), nil
+	case "getMinimumBalanceForRentExemption":
+		var n uint64
+		if err := param(params, 0, &n); err != nil {
+			return nil, err
+		}
+		return RentExemptMinimum(n), nil
+	case "getFeeForMessage":
+		var encoded string
+		if err := param(params, 0, &encoded); err != nil {
+			return nil, err
+		}
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 message: %w", err)
+		}
+		msg, err := types.MessageDeserialize(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message: %w", err)
+		}
+		fee, ok := b.FeeForMessage(msg)
+		if !ok {
+			return b.withContext(nil), nil
+		}
+		return b.withContext(fee), nil
+	case "requestAirdrop":
+		key, err := pubkeyParam(params, 0)
+		if err != nil {
+			return nil, err
+		}
+		var lamports uint64
+		if err := param(params, 1, &lamports); err != nil {
+			return nil, err
+		}
+		return b.Airdrop(key, lamports), nil
+	case "sendTransaction":
+		var cfg struct{ SkipPreflight bool }
+		_ = param(params, 1, &cfg)
+		raw, err := txParam(params)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := b.ProcessTransaction(raw, cfg.SkipPreflight)
+		var te *TransactionError
+		switch {
+		case errors.Is(err, ErrSignatureFailure):
+			return nil, &rpcError{Code: codeSignatureFailure, Message: "Transaction signature verification failure"}
+		case errors.As(err, &te):
+			return nil, &rpcError{
+				Code:    codeSimulationFailed,
+				Message: "Transaction simulation failed: " + describe(te.Err),
+				Data:    map[string]any{"err": te.Err, "logs": te.Logs, "accounts": nil, "unitsConsumed": 0},
+			}
+		case err != nil:
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return sig, nil
+	case "simulateTransaction":
+		var cfg struct {
+			SigVerify              bool
+			ReplaceRecentBlockhash bool
+		}
+		_ = param(params, 1, &cfg)
+		raw, err := txParam(params)
+		if err != nil {
+			return nil, err
+		}
+		txErr, logs, err := b.SimulateTransaction(raw, cfg.SigVerify, cfg.ReplaceRecentBlockhash)
+		if errors.Is(err, ErrSignatureFailure) {
+			return nil, &rpcError{Code: codeSignatureFailure, Message: "Transaction signature verification failure"}
+		}
+		if err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		if logs == nil {
+			logs = []string{}
+		}
+		return b.withContext(map[string]any{
+			"err": txErr, "logs": logs, "accounts": nil, "unitsConsumed": 0, "returnData": nil,
+		}), nil
+	case "getSignatureStatuses":
+		var sigs []string
+		if err := param(params, 0, &sigs); err != nil {
+			return nil, err
+		}
+		out := make([]any, len(sigs))
+		for i, sig := range sigs {
+			slot, txErr, ok := b.SignatureStatus(sig)
+			if !ok {
+				continue
+			}
+			// 模拟器没有分叉，交易落账即为 finalized
+			out[i] = map[string]any{
+				"slot":               slot,
+				"confirmations":      nil,
+				"err":                txErr,
+				"confirmationStatus": "finalized",
+			}
+		}
+		return b.withContext(out), nil
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "Method not found: " + method}
+}
+
+// accountJSON 按 RPC 格式返回账户，不存在时为 nil；默认与 validator 一样使用 base58 编码
+func (b *Bank) accountJSON(key common.PublicKey, encoding string) any {
+	acc, ok := b.GetAccount(key)
+	if !ok {
+		return nil
+	}
+	var data []any
+	if encoding == "base58" || encoding == "" {
+		data = []any{base58.Encode(acc.Data), "base58"}
+	} else {
+		data = []any{base64.StdEncoding.EncodeToString(acc.Data), "base64"}
+	}
+	return map[string]any{
+		"lamports":   acc.Lamports,
+		"owner":      acc.Owner.ToBase58(),
+		"data":       data,
+		"executable": acc.Executable,
+		"rentEpoch":  uint64(math.MaxUint64),
+		"space":      len(acc.Data),
+	}
+}
+
+func param(params []json.RawMessage, i int, v any) error {
+	if i >= len(params) {
+		return fmt.Errorf("missing parameter %d", i)
+	}
+	if err := json.Unmarshal(params[i], v); err != nil {
+		return fmt.Errorf("invalid parameter %d: %w", i, err)
+	}
+	return nil
+}
+
+func pubkeyParam(params []json.RawMessage, i int) (common.PublicKey, error) {
+	var s string
+	if err := param(params, i, &s); err != nil {
+		return common.PublicKey{}, err
+	}
+	return parsePubkey(s)
+}
+
+func parsePubkey(s string) (common.PublicKey, error) {
+	b, err := base58.Decode(s)
+	if err != nil || len(b) != 32 {
+		return common.PublicKey{}, fmt.Errorf("invalid pubkey %q", s)
+	}
+	return common.PublicKeyFromBytes(b), nil
+}
+
+// txParam 解码第一个参数中的交易，编码取自配置参数的 encoding，缺省为 base58
+func txParam(params []json.RawMessage) ([]byte, error) {
+	var encoded string
+	if err := param(params, 0, &encoded); err != nil {
+		return nil, err
+	}
+	var cfg struct{ Encoding string }
+	_ = param(params, 1, &cfg)
+	if cfg.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(encoded)
+	}
+	return base58.Decode(encoded)
+}
+
+// describe 给出交易错误的可读描述，用于 RPC 错误信息
+func describe(err any) string {
+	switch v := err.(type) {
+	case string:
+		return v
+	case map[string]any:
+		if ie, ok := v["InstructionError"].([]any); ok && len(ie) == 2 {
+			return fmt.Sprintf("Error processing Instruction %v: %s", ie[0], instructionErrorString(ie[1]))
+		}
+	}
+	b, _ := json.Marshal(err)
+	return string(b)
+}
@@ -0,0 +1,277 @@
+package banksim
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+const (
+	// 未设置 SetComputeUnitLimit 时每条非 ComputeBudget 指令的默认计算单元，以及交易上限
+	defaultInstructionUnits = 200_000
+	maxTransactionUnits     = 1_400_000
+	maxAccountDataLen       = 10 * 1024 * 1024
+
+	// System 程序指令编号（u32 LE）
+	systemCreateAccount = 0
+	systemAssign        = 1
+	systemTransfer      = 2
+	systemAllocate      = 8
+
+	// System 程序自定义错误码
+	errAccountAlreadyInUse        = 0
+	errResultWithNegativeLamports = 1
+	errInvalidAccountDataLength   = 3
+)
+
+// fee 返回签名费与优先费之和；优先费 = ceil(单价(micro-lamports) × 计算单元上限 / 1e6)
+func (b *Bank) fee(msg types.Message) uint64 {
+	var price, limit, defaultLimit uint64
+	limitSet := false
+	for _, ix := range msg.Instructions {
+		if ix.ProgramIDIndex >= len(msg.Accounts) || msg.Accounts[ix.ProgramIDIndex] != common.ComputeBudgetProgramID {
+			defaultLimit += defaultInstructionUnits
+			continue
+		}
+		switch {
+		case len(ix.Data) == 5 && ix.Data[0] == 2: // SetComputeUnitLimit
+			limit, limitSet = uint64(binary.LittleEndian.Uint32(ix.Data[1:])), true
+		case len(ix.Data) == 9 && ix.Data[0] == 3: // SetComputeUnitPrice
+			price = binary.LittleEndian.Uint64(ix.Data[1:])
+		}
+	}
+	if !limitSet {
+		limit = defaultLimit
+	}
+	limit = min(limit, maxTransactionUnits)
+	return uint64(msg.Header.NumRequireSignatures)*b.cfg.LamportsPerSignature + (price*limit+999_999)/1_000_000
+}
+
+// execution 是一次执行的结果：成功时写回所有可写账户，失败时只扣手续费
+type execution struct {
+	keys     []common.PublicKey
+	writable []bool
+	post     []Account
+	payer    Account // 仅扣除手续费后的付费账户
+	err      any
+	logs     []string
+}
+
+func (e *execution) commit(b *Bank) {
+	if e.err != nil {
+		b.store(e.keys[0], e.payer)
+		return
+	}
+	for i, key := range e.keys {
+		if e.writable[i] {
+			b.store(key, e.post[i])
+		}
+	}
+}
+
+// execute 检查付费账户与程序后依次执行指令。
+// 加载阶段的失败（付费账户不存在、余额不足以支付手续费等）以 *TransactionError 返回，不收取手续费。
+func (b *Bank) execute(msg types.Message) (*execution, error) {
+	n := len(msg.Accounts)
+	e := &execution{keys: msg.Accounts, writable: make([]bool, n), post: make([]Account, n)}
+	pre := make([]Account, n)
+	for i, key := range msg.Accounts {
+		pre[i] = b.accounts[key].clone()
+		e.post[i] = pre[i].clone()
+		e.writable[i] = isWritable(msg.Header, n, i)
+	}
+	for _, ix := range msg.Instructions {
+		if ix.ProgramIDIndex >= n {
+			return nil, &TransactionError{Err: "ProgramAccountNotFound"}
+		}
+		switch msg.Accounts[ix.ProgramIDIndex] {
+		case common.SystemProgramID, common.ComputeBudgetProgramID, common.MemoProgramID:
+		default:
+			return nil, &TransactionError{Err: "ProgramAccountNotFound"}
+		}
+	}
+
+	fee := b.fee(msg)
+	payer := &e.post[0]
+	switch {
+	case payer.Lamports == 0:
+		return nil, &TransactionError{Err: "AccountNotFound"}
+	case payer.Owner != common.SystemProgramID:
+		return nil, &TransactionError{Err: "InvalidAccountForFee"}
+	case payer.Lamports < fee:
+		return nil, &TransactionError{Err: "InsufficientFundsForFee"}
+	}
+	payer.Lamports -= fee
+	if !rentTransitionAllowed(pre[0], *payer) {
+		return nil, &TransactionError{Err: map[string]any{"InsufficientFundsForRent": map[string]any{"account_index": 0}}}
+	}
+	e.payer = payer.clone()
+
+	for i, ix := range msg.Instructions {
+		program := msg.Accounts[ix.ProgramIDIndex]
+		e.logs = append(e.logs, fmt.Sprintf("Program %s invoke [1]", program.ToBase58()))
+		var ixErr any
+		if program == common.SystemProgramID {
+			ixErr = e.system(msg.Header, ix)
+		}
+		if ixErr != nil {
+			e.logs = append(e.logs, fmt.Sprintf("Program %s failed: %s", program.ToBase58(), instructionErrorString(ixErr)))
+			e.err = map[string]any{"InstructionError": []any{i, ixErr}}
+			return e, nil
+		}
+		e.logs = append(e.logs, fmt.Sprintf("Program %s success", program.ToBase58()))
+	}
+	for i := range e.post {
+		if e.writable[i] && !rentTransitionAllowed(pre[i], e.post[i]) {
+			e.err = map[string]any{"InsufficientFundsForRent": map[string]any{"account_index": i}}
+			return e, nil
+		}
+	}
+	return e, nil
+}
+
+// system 执行一条 System 程序指令，返回 RPC 格式的指令错误或 nil
+func (e *execution) system(h types.MessageHeader, ix types.CompiledInstruction) any {
+	if len(ix.Data) < 4 {
+		return "InvalidInstructionData"
+	}
+	// account 取指令的第 k 个账户，并检查签名与可写要求
+	account := func(k int, signer bool) (*Account, any) {
+		if k >= len(ix.Accounts) {
+			return nil, "NotEnoughAccountKeys"
+		}
+		idx := ix.Accounts[k]
+		if idx >= len(e.post) {
+			return nil, "NotEnoughAccountKeys"
+		}
+		if signer && idx >= int(h.NumRequireSignatures) {
+			return nil, "MissingRequiredSignature"
+		}
+		if !e.writable[idx] {
+			return nil, "ReadonlyLamportChange"
+		}
+		return &e.post[idx], nil
+	}
+	// debit 从 System 账户扣款；带数据或不属于 System 的账户不能作为付款方
+	debit := func(from *Account, lamports uint64) any {
+		if from.Owner != common.SystemProgramID && from.Lamports > 0 {
+			return "ExternalAccountLamportSpend"
+		}
+		if len(from.Data) > 0 {
+			return "InvalidArgument"
+		}
+		if from.Lamports < lamports {
+			return map[string]any{"Custom": errResultWithNegativeLamports}
+		}
+		from.Lamports -= lamports
+		return nil
+	}
+	data := ix.Data[4:]
+
+	switch binary.LittleEndian.Uint32(ix.Data) {
+	case systemCreateAccount:
+		if len(data) != 48 {
+			return "InvalidInstructionData"
+		}
+		lamports := binary.LittleEndian.Uint64(data)
+		space := binary.LittleEndian.Uint64(data[8:])
+		owner := common.PublicKeyFromBytes(data[16:48])
+		from, errv := account(0, true)
+		if errv != nil {
+			return errv
+		}
+		to, errv := account(1, true)
+		if errv != nil {
+			return errv
+		}
+		if to.Lamports > 0 || len(to.Data) > 0 {
+			return map[string]any{"Custom": errAccountAlreadyInUse}
+		}
+		if space > maxAccountDataLen {
+			return map[string]any{"Custom": errInvalidAccountDataLength}
+		}
+		if errv := debit(from, lamports); errv != nil {
+			return errv
+		}
+		*to = Account{Lamports: lamports, Owner: owner, Data: make([]byte, space)}
+	case systemAssign:
+		if len(data) != 32 {
+			return "InvalidInstructionData"
+		}
+		acc, errv := account(0, true)
+		if errv != nil {
+			return errv
+		}
+		if acc.Owner != common.SystemProgramID && acc.Lamports > 0 {
+			return "ModifiedProgramId"
+		}
+		acc.Owner = common.PublicKeyFromBytes(data)
+	case systemTransfer:
+		if len(data) != 8 {
+			return "InvalidInstructionData"
+		}
+		lamports := binary.LittleEndian.Uint64(data)
+		from, errv := account(0, true)
+		if errv != nil {
+			return errv
+		}
+		to, errv := account(1, false)
+		if errv != nil {
+			return errv
+		}
+		if errv := debit(from, lamports); errv != nil {
+			return errv
+		}
+		// 不存在的账户是零值，Owner 即 System 程序（全零公钥）
+		to.Lamports += lamports
+	case systemAllocate:
+		if len(data) != 8 {
+			return "InvalidInstructionData"
+		}
+		space := binary.LittleEndian.Uint64(data)
+		acc, errv := account(0, true)
+		if errv != nil {
+			return errv
+		}
+		if len(acc.Data) > 0 || (acc.Owner != common.SystemProgramID && acc.Lamports > 0) {
+			return map[string]any{"Custom": errAccountAlreadyInUse}
+		}
+		if space > maxAccountDataLen {
+			return map[string]any{"Custom": errInvalidAccountDataLength}
+		}
+		acc.Data = make([]byte, space)
+	default:
+		return "InvalidInstructionData"
+	}
+	return nil
+}
+
+// instructionErrorString 按 validator 日志的写法描述指令错误
+func instructionErrorString(err any) string {
+	if m, ok := err.(map[string]any); ok {
+		if code, ok := m["Custom"].(int); ok {
+			return fmt.Sprintf("custom program error: 0x%x", code)
+		}
+	}
+	return fmt.Sprint(err)
+}
+
+// isWritable 按消息头判断第 i 个账户是否可写
+func isWritable(h types.MessageHeader, n, i int) bool {
+	if i < int(h.NumRequireSignatures) {
+		return i < int(h.NumRequireSignatures-h.NumReadonlySignedAccounts)
+	}
+	return i < n-int(h.NumReadonlyUnsignedAccounts)
+}
+
+// rentTransitionAllowed 实现租金状态转换规则：交易后账户必须为空或免租；
+// 原本就欠租的账户只允许在数据长度不变且余额不增加时保持欠租。
+func rentTransitionAllowed(pre, post Account) bool {
+	if post.Lamports == 0 || post.Lamports >= RentExemptMinimum(uint64(len(post.Data))) {
+		return true
+	}
+	preRentPaying := pre.Lamports > 0 && pre.Lamports < RentExemptMinimum(uint64(len(pre.Data)))
+	return preRentPaying && len(pre.Data) == len(post.Data) && post.Lamports <= pre.Lamports
+}
@@ -0,0 +1,276 @@
+// Package borsh 实现 Anchor 程序使用的 Borsh 编解码子集：
+// 定长整数（小端）、bool、string、Vec<T>、Option<T>、定长数组与 Pubkey。
+package borsh
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/blocto/solana-go-sdk/common"
+)
+
+// ErrUnexpectedEOF 表示数据在解码完成前耗尽
+var ErrUnexpectedEOF = errors.New("borsh: unexpected end of data")
+
+// Encoder 以追加方式写出 Borsh 字节
+type Encoder struct {
+	buf []byte
+}
+
+// NewEncoder 创建一个空的编码器
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// Bytes 返回已编码的字节
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// WriteRaw 原样写入字节（用于 discriminator 与定长 [u8; N]）
+func (e *Encoder) WriteRaw(b []byte) {
+	e.buf = append(e.buf, b...)
+}
+
+func (e *Encoder) WriteBool(v bool) {
+	if v {
+		e.buf = append(e.buf, 1)
+		return
+	}
+	e.buf = append(e.buf, 0)
+}
+
+func (e *Encoder) WriteU8(v uint8) {
+	e.buf = append(e.buf, v)
+}
+
+func (e *Encoder) WriteU16(v uint16) {
+	e.buf = binary.LittleEndian.AppendUint16(e.buf, v)
+}
+
+func (e *Encoder) WriteU32(v uint32) {
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, v)
+}
+
+func (e *Encoder) WriteU64(v uint64) {
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, v)
+}
+
+func (e *Encoder) WriteI8(v int8) {
+	e.WriteU8(uint8(v))
+}
+
+func (e *Encoder) WriteI16(v int16) {
+	e.WriteU16(uint16(v))
+}
+
+func (e *Encoder) WriteI32(v int32) {
+	e.WriteU32(uint32(v))
+}
+
+func (e *Encoder) WriteI64(v int64) {
+	e.WriteU64(uint64(v))
+}
+
+func (e *Encoder) WriteF32(v float32) {
+	e.WriteU32(math.Float32bits(v))
+}
+
+func (e *Encoder) WriteF64(v float64) {
+	e.WriteU64(math.Float64bits(v))
+}
+
+// WriteLen 写出 Vec/String 的 u32 长度前缀
+func (e *Encoder) WriteLen(n int) {
+	e.WriteU32(uint32(n))
+}
+
+// WriteString: string = u32(len) + utf8 bytes
+func (e *Encoder) WriteString(s string) {
+	e.WriteLen(len(s))
+	e.buf = append(e.buf, s...)
+}
+
+// WriteBytes: Vec<u8> = u32(len) + bytes
+func (e *Encoder) WriteBytes(b []byte) {
+	e.WriteLen(len(b))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *Encoder) WritePubkey(pk common.PublicKey) {
+	e.buf = append(e.buf, pk.Bytes()...)
+}
+
+// WriteOption 写出 Option 的 tag（0=None，1=Some）；Some 时调用方随后写入值
+func (e *Encoder) WriteOption(some bool) {
+	e.WriteBool(some)
+}
+
+// Decoder 顺序读取 Borsh 字节。
+// 首个错误会被记录，之后的读取全部返回零值，调用方在末尾检查 Err 即可。
+type Decoder struct {
+	data []byte
+	off  int
+	err  error
+}
+
+// NewDecoder 基于给定字节创建解码器
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{data: data}
+}
+
+// Err 返回解码过程中遇到的第一个错误
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Remaining 返回尚未读取的字节数
+func (d *Decoder) Remaining() int {
+	return len(d.data) - d.off
+}
+
+// SetErr 记录外部校验失败（例如未知的枚举值），只保留第一个错误
+func (d *Decoder) SetErr(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *Decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.Remaining() < n {
+		d.err = ErrUnexpectedEOF
+		return nil
+	}
+	b := d.data[d.off : d.off+n]
+	d.off += n
+	return b
+}
+
+// ReadRaw 读取 n 个原始字节（拷贝）
+func (d *Decoder) ReadRaw(n int) []byte {
+	b := d.next(n)
+	if b == nil {
+		return nil
+	}
+	out := make([]byte, n)
+	copy(out, b)
+	return out
+}
+
+func (d *Decoder) ReadBool() bool {
+	b := d.next(1)
+	if b == nil {
+		return false
+	}
+	switch b[0] {
+	case 0:
+		return false
+	case 1:
+		return true
+	default:
+		d.err = fmt.Errorf("borsh: invalid bool value %d", b[0])
+		return false
+	}
+}
+
+func (d *Decoder) ReadU8() uint8 {
+	b := d.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (d *Decoder) ReadU16() uint16 {
+	b := d.next(2)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(b)
+}
+
+func (d *Decoder) ReadU32() uint32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (d *Decoder) ReadU64() uint64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+func (d *Decoder) ReadI8() int8 {
+	return int8(d.ReadU8())
+}
+
+func (d *Decoder) ReadI16() int16 {
+	return int16(d.ReadU16())
+}
+
+func (d *Decoder) ReadI32() int32 {
+	return int32(d.ReadU32())
+}
+
+func (d *Decoder) ReadI64() int64 {
+	return int64(d.ReadU64())
+}
+
+func (d *Decoder) ReadF32() float32 {
+	return math.Float32frombits(d.ReadU32())
+}
+
+func (d *Decoder) ReadF64() float64 {
+	return math.Float64frombits(d.ReadU64())
+}
+
+// ReadLen 读取 u32 长度前缀；每个元素至少占 minElem 字节，
+// 用于在分配内存前拒绝明显越界的长度
+func (d *Decoder) ReadLen(minElem int) int {
+	n := int(d.ReadU32())
+	if d.err != nil {
+		return 0
+	}
+	if minElem > 0 && n > d.Remaining()/minElem {
+		d.err = ErrUnexpectedEOF
+		return 0
+	}
+	return n
+}
+
+func (d *Decoder) ReadString() string {
+	n := d.ReadLen(1)
+	b := d.next(n)
+	if b == nil {
+		return ""
+	}
+	return string(b)
+}
+
+func (d *Decoder) ReadBytes() []byte {
+	n := d.ReadLen(1)
+	return d.ReadRaw(n)
+}
+
+func (d *Decoder) ReadPubkey() common.PublicKey {
+	b := d.next(common.PublicKeyLength)
+	if b == nil {
+		return common.PublicKey{}
+	}
+	return common.PublicKeyFromBytes(b)
+}
+
+// ReadOption 读取 Option 的 tag，返回是否为 Some
+func (d *Decoder) ReadOption() bool {
+	return d.ReadBool()
+}
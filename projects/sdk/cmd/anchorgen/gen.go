@@ -0,0 +1,982 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sdk/anchor"
+)
+
+type generator struct {
+	idl     *anchor.IDL
+	body    bytes.Buffer
+	imports map[string]bool
+}
+
+func generate(idl *anchor.IDL, pkg, source string) ([]byte, error) {
+	g := &generator{idl: idl, imports: map[string]bool{}}
+	steps := []func() error{
+		g.genProgramID,
+		g.genTypes,
+		g.genAccounts,
+		g.genEvents,
+		g.genInstructions,
+		g.genPDAs,
+		g.genErrors,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by anchorgen from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&out, "// Package %s 是 Anchor 程序 %s 的类型化客户端。\n", pkg, idl.Metadata.Name)
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	// 按 标准库 / 第三方 / 本仓库 分组输出 import
+	var groups [3][]string
+	for p := range g.imports {
+		switch first := strings.Split(p, "/")[0]; {
+		case first == "sdk":
+			groups[2] = append(groups[2], p)
+		case strings.Contains(first, "."):
+			groups[1] = append(groups[1], p)
+		default:
+			groups[0] = append(groups[0], p)
+		}
+	}
+	out.WriteString("import (\n")
+	sep := false
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		if sep {
+			out.WriteString("\n")
+		}
+		sort.Strings(group)
+		for _, p := range group {
+			fmt.Fprintf(&out, "\t%q\n", p)
+		}
+		sep = true
+	}
+	out.WriteString(")\n\n")
+	out.Write(g.body.Bytes())
+	return out.Bytes(), nil
+}
+
+func (g *generator) use(path string) {
+	g.imports[path] = true
+}
+
+func (g *generator) p(format string, args ...any) {
+	fmt.Fprintf(&g.body, format, args...)
+	g.body.WriteByte('\n')
+}
+
+func (g *generator) docs(lines []string) {
+	for _, l := range lines {
+		g.p("// %s", strings.TrimSpace(l))
+	}
+}
+
+func (g *generator) genProgramID() error {
+	if g.idl.Address == "" {
+		return fmt.Errorf("idl has no program address")
+	}
+	g.use("github.com/blocto/solana-go-sdk/common")
+	g.p("// ProgramID 是程序 declare_id! 声明的地址")
+	g.p("var ProgramID = common.PublicKeyFromString(%q)", g.idl.Address)
+	g.p("")
+	return nil
+}
+
+func discriminatorLiteral(d []byte) string {
+	parts := make([]string, len(d))
+	for i, b := range d {
+		parts[i] = strconv.Itoa(int(b))
+	}
+	return "anchor.Discriminator{" + strings.Join(parts, ", ") + "}"
+}
+
+// ---------------------------------------------------------------------------
+// 类型映射与 Borsh 编解码
+// ---------------------------------------------------------------------------
+
+var primitives = map[string]struct {
+	goType string
+	method string
+	size   int
+}{
+	"bool":   {"bool", "Bool", 1},
+	"u8":     {"uint8", "U8", 1},
+	"i8":     {"int8", "I8", 1},
+	"u16":    {"uint16", "U16", 2},
+	"i16":    {"int16", "I16", 2},
+	"u32":    {"uint32", "U32", 4},
+	"i32":    {"int32", "I32", 4},
+	"u64":    {"uint64", "U64", 8},
+	"i64":    {"int64", "I64", 8},
+	"f32":    {"float32", "F32", 4},
+	"f64":    {"float64", "F64", 8},
+	"string": {"string", "String", 4},
+	"bytes":  {"[]byte", "Bytes", 4},
+	"pubkey": {"common.PublicKey", "Pubkey", 32},
+}
+
+func (g *generator) goType(t anchor.IDLType) (string, error) {
+	switch {
+	case t.Vec != nil:
+		elem, err := g.goType(*t.Vec)
+		return "[]" + elem, err
+	case t.Option != nil:
+		elem, err := g.goType(*t.Option)
+		return "*" + elem, err
+	case t.Array != nil:
+		elem, err := g.goType(*t.Array)
+		return fmt.Sprintf("[%d]%s", t.ArrayLen, elem), err
+	case t.Defined != "":
+		if _, ok := g.idl.FindType(t.Defined); !ok {
+			return "", fmt.Errorf("undefined type %q", t.Defined)
+		}
+		return t.Defined, nil
+	}
+	prim, ok := primitives[t.Primitive]
+	if !ok {
+		return "", fmt.Errorf("unsupported idl type %q", t.Primitive)
+	}
+	if t.Primitive == "pubkey" {
+		g.use("github.com/blocto/solana-go-sdk/common")
+	}
+	return prim.goType, nil
+}
+
+// minSize 返回类型编码后的最小字节数，用于解码 Vec 长度时做越界检查
+func (g *generator) minSize(t anchor.IDLType) int {
+	switch {
+	case t.Vec != nil:
+		return 4
+	case t.Option != nil:
+		return 1
+	case t.Array != nil:
+		return t.ArrayLen * g.minSize(*t.Array)
+	case t.Defined != "":
+		def, _ := g.idl.FindType(t.Defined)
+		if def.Type.Kind == "enum" {
+			return 1
+		}
+		n := 0
+		for _, f := range def.Type.Fields {
+			n += g.minSize(f.Type)
+		}
+		return n
+	}
+	return primitives[t.Primitive].size
+}
+
+// encode 生成把 expr 写入编码器 e 的语句
+func (g *generator) encode(expr string, t anchor.IDLType, depth int) error {
+	switch {
+	case t.Vec != nil:
+		v := fmt.Sprintf("v%d", depth)
+		g.p("e.WriteLen(len(%s))", expr)
+		g.p("for _, %s := range %s {", v, expr)
+		if err := g.encode(v, *t.Vec, depth+1); err != nil {
+			return err
+		}
+		g.p("}")
+	case t.Option != nil:
+		g.p("e.WriteOption(%s != nil)", expr)
+		g.p("if %s != nil {", expr)
+		if err := g.encode("(*"+expr+")", *t.Option, depth+1); err != nil {
+			return err
+		}
+		g.p("}")
+	case t.Array != nil:
+		if t.Array.Primitive == "u8" {
+			g.p("e.WriteRaw(%s[:])", expr)
+			return nil
+		}
+		v := fmt.Sprintf("v%d", depth)
+		g.p("for _, %s := range %s {", v, expr)
+		if err := g.encode(v, *t.Array, depth+1); err != nil {
+			return err
+		}
+		g.p("}")
+	case t.Defined != "":
+		g.p("%s.MarshalBorsh(e)", expr)
+	default:
+		prim, ok := primitives[t.Primitive]
+		if !ok {
+			return fmt.Errorf("unsupported idl type %q", t.Primitive)
+		}
+		g.p("e.Write%s(%s)", prim.method, expr)
+	}
+	return nil
+}
+
+// decode 生成从解码器 d 读出值并赋给可寻址表达式 target 的语句
+func (g *generator) decode(target string, t anchor.IDLType, depth int) error {
+	switch {
+	case t.Vec != nil:
+		elem, err := g.goType(*t.Vec)
+		if err != nil {
+			return err
+		}
+		i := fmt.Sprintf("i%d", depth)
+		g.p("%s = make([]%s, d.ReadLen(%d))", target, elem, g.minSize(*t.Vec))
+		g.p("for %s := range %s {", i, target)
+		if err := g.decode(fmt.Sprintf("%s[%s]", target, i), *t.Vec, depth+1); err != nil {
+			return err
+		}
+		g.p("}")
+	case t.Option != nil:
+		elem, err := g.goType(*t.Option)
+		if err != nil {
+			return err
+		}
+		v := fmt.Sprintf("v%d", depth)
+		g.p("if d.ReadOption() {")
+		g.p("var %s %s", v, elem)
+		if err := g.decode(v, *t.Option, depth+1); err != nil {
+			return err
+		}
+		g.p("%s = &%s", target, v)
+		g.p("}")
+	case t.Array != nil:
+		if t.Array.Primitive == "u8" {
+			g.p("copy(%s[:], d.ReadRaw(%d))", target, t.ArrayLen)
+			return nil
+		}
+		i := fmt.Sprintf("i%d", depth)
+		g.p("for %s := range %s {", i, target)
+		if err := g.decode(fmt.Sprintf("%s[%s]", target, i), *t.Array, depth+1); err != nil {
+			return err
+		}
+		g.p("}")
+	case t.Defined != "":
+		g.p("%s.UnmarshalBorsh(d)", target)
+	default:
+		prim, ok := primitives[t.Primitive]
+		if !ok {
+			return fmt.Errorf("unsupported idl type %q", t.Primitive)
+		}
+		g.p("%s = d.Read%s()", target, prim.method)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// 自定义类型（账户、事件与参数中引用的结构体 / 枚举）
+// ---------------------------------------------------------------------------
+
+func (g *generator) genTypes() error {
+	for _, def := range g.idl.Types {
+		var err error
+		switch def.Type.Kind {
+		case "struct":
+			err = g.genStruct(def)
+		case "enum":
+			err = g.genEnum(def)
+		default:
+			err = fmt.Errorf("type %s: unsupported kind %q", def.Name, def.Type.Kind)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) genStruct(def anchor.IDLTypeDef) error {
+	g.use("sdk/borsh")
+	g.p("// %s 对应程序中的同名结构体", def.Name)
+	if len(def.Docs) > 0 {
+		g.p("//")
+		g.docs(def.Docs)
+	}
+	g.p("type %s struct {", def.Name)
+	for _, f := range def.Type.Fields {
+		typ, err := g.goType(f.Type)
+		if err != nil {
+			return fmt.Errorf("type %s field %s: %w", def.Name, f.Name, err)
+		}
+		g.docs(f.Docs)
+		g.p("%s %s `json:%q`", exportedName(f.Name), typ, localName(f.Name))
+	}
+	g.p("}")
+	g.p("")
+
+	g.p("// MarshalBorsh 按字段顺序写出 Borsh 编码")
+	g.p("func (v *%s) MarshalBorsh(e *borsh.Encoder) {", def.Name)
+	for _, f := range def.Type.Fields {
+		if err := g.encode("v."+exportedName(f.Name), f.Type, 0); err != nil {
+			return err
+		}
+	}
+	g.p("}")
+	g.p("")
+
+	g.p("// UnmarshalBorsh 按字段顺序读取 Borsh 编码，错误记录在 d.Err() 中")
+	g.p("func (v *%s) UnmarshalBorsh(d *borsh.Decoder) {", def.Name)
+	for _, f := range def.Type.Fields {
+		if err := g.decode("v."+exportedName(f.Name), f.Type, 0); err != nil {
+			return err
+		}
+	}
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+func (g *generator) genEnum(def anchor.IDLTypeDef) error {
+	for _, v := range def.Type.Variants {
+		if len(v.Fields) > 0 {
+			return g.genDataEnum(def)
+		}
+	}
+	g.use("fmt")
+	g.use("sdk/borsh")
+	g.p("// %s 对应程序中的同名枚举（Borsh 编码为 u8 变体序号）", def.Name)
+	if len(def.Docs) > 0 {
+		g.p("//")
+		g.docs(def.Docs)
+	}
+	g.p("type %s uint8", def.Name)
+	g.p("")
+	g.p("const (")
+	for i, v := range def.Type.Variants {
+		if i == 0 {
+			g.p("%s%s %s = iota", def.Name, exportedName(v.Name), def.Name)
+			continue
+		}
+		g.p("%s%s", def.Name, exportedName(v.Name))
+	}
+	g.p(")")
+	g.p("")
+	g.p("func (v %s) String() string {", def.Name)
+	g.p("switch v {")
+	for _, v := range def.Type.Variants {
+		g.p("case %s%s:", def.Name, exportedName(v.Name))
+		g.p("return %q", v.Name)
+	}
+	g.p("}")
+	g.p("return fmt.Sprintf(\"%s(%%d)\", uint8(v))", def.Name)
+	g.p("}")
+	g.p("")
+	g.p("func (v *%s) MarshalBorsh(e *borsh.Encoder) {", def.Name)
+	g.p("e.WriteU8(uint8(*v))")
+	g.p("}")
+	g.p("")
+	g.p("func (v *%s) UnmarshalBorsh(d *borsh.Decoder) {", def.Name)
+	g.p("*v = %s(d.ReadU8())", def.Name)
+	g.p("if *v >= %d {", len(def.Type.Variants))
+	g.p("d.SetErr(fmt.Errorf(\"invalid %s variant %%d\", uint8(*v)))", def.Name)
+	g.p("}")
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+// genDataEnum 生成带字段变体的枚举：Kind 为变体序号，各变体字段放在同名指针字段中，
+// 仅 Kind 对应的那个有效（为 nil 时按零值编码）。
+//
+//	type VotingMode struct {
+//		Kind          VotingModeKind
+//		TokenWeighted *VotingModeTokenWeighted
+//	}
+func (g *generator) genDataEnum(def anchor.IDLTypeDef) error {
+	g.use("fmt")
+	g.use("sdk/borsh")
+	kind := def.Name + "Kind"
+	g.p("// %s 是 %s 的变体序号", kind, def.Name)
+	g.p("type %s uint8", kind)
+	g.p("")
+	g.p("const (")
+	for i, v := range def.Type.Variants {
+		if i == 0 {
+			g.p("%s%s %s = iota", kind, exportedName(v.Name), kind)
+			continue
+		}
+		g.p("%s%s", kind, exportedName(v.Name))
+	}
+	g.p(")")
+	g.p("")
+	g.p("func (k %s) String() string {", kind)
+	g.p("switch k {")
+	for _, v := range def.Type.Variants {
+		g.p("case %s%s:", kind, exportedName(v.Name))
+		g.p("return %q", v.Name)
+	}
+	g.p("}")
+	g.p("return fmt.Sprintf(\"%s(%%d)\", uint8(k))", kind)
+	g.p("}")
+	g.p("")
+
+	for _, v := range def.Type.Variants {
+		if len(v.Fields) == 0 {
+			continue
+		}
+		if err := g.genStruct(anchor.IDLTypeDef{
+			Name: def.Name + exportedName(v.Name),
+			Docs: []string{fmt.Sprintf("%s::%s 变体的字段", def.Name, v.Name)},
+			Type: anchor.IDLTypeBody{Kind: "struct", Fields: v.Fields},
+		}); err != nil {
+			return err
+		}
+	}
+
+	g.p("// %s 对应程序中的同名枚举（Borsh 编码为 u8 变体序号 + 变体字段）", def.Name)
+	if len(def.Docs) > 0 {
+		g.p("//")
+		g.docs(def.Docs)
+	}
+	g.p("type %s struct {", def.Name)
+	g.p("Kind %s `json:\"kind\"`", kind)
+	for _, v := range def.Type.Variants {
+		if len(v.Fields) == 0 {
+			continue
+		}
+		name := exportedName(v.Name)
+		g.p("%s *%s%s `json:%q`", name, def.Name, name, localName(v.Name)+",omitempty")
+	}
+	g.p("}")
+	g.p("")
+
+	g.p("func (v *%s) MarshalBorsh(e *borsh.Encoder) {", def.Name)
+	g.p("e.WriteU8(uint8(v.Kind))")
+	g.p("switch v.Kind {")
+	for _, v := range def.Type.Variants {
+		if len(v.Fields) == 0 {
+			continue
+		}
+		name := exportedName(v.Name)
+		g.p("case %s%s:", kind, name)
+		g.p("f := v.%s", name)
+		g.p("if f == nil {")
+		g.p("f = &%s%s{}", def.Name, name)
+		g.p("}")
+		g.p("f.MarshalBorsh(e)")
+	}
+	g.p("}")
+	g.p("}")
+	g.p("")
+
+	g.p("func (v *%s) UnmarshalBorsh(d *borsh.Decoder) {", def.Name)
+	g.p("*v = %s{Kind: %s(d.ReadU8())}", def.Name, kind)
+	g.p("switch v.Kind {")
+	for _, v := range def.Type.Variants {
+		name := exportedName(v.Name)
+		g.p("case %s%s:", kind, name)
+		if len(v.Fields) > 0 {
+			g.p("v.%s = new(%s%s)", name, def.Name, name)
+			g.p("v.%s.UnmarshalBorsh(d)", name)
+		}
+	}
+	g.p("default:")
+	g.p("d.SetErr(fmt.Errorf(\"invalid %s variant %%d\", uint8(v.Kind)))", def.Name)
+	g.p("}")
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// 账户与事件
+// ---------------------------------------------------------------------------
+
+func (g *generator) genAccounts() error {
+	if len(g.idl.Accounts) == 0 {
+		return nil
+	}
+	g.use("sdk/anchor")
+	g.use("sdk/borsh")
+	g.use("fmt")
+	g.p("// 账户 discriminator：sha256(\"account:<Name>\")[:8]")
+	g.p("var (")
+	for _, acc := range g.idl.Accounts {
+		g.p("%sDiscriminator = %s", acc.Name, discriminatorLiteral(acc.Discriminator))
+	}
+	g.p(")")
+	g.p("")
+	for _, acc := range g.idl.Accounts {
+		if _, ok := g.idl.FindType(acc.Name); !ok {
+			return fmt.Errorf("account %s has no type definition", acc.Name)
+		}
+		g.p("// Decode%s 校验 discriminator 并解码 %s 账户数据", acc.Name, acc.Name)
+		g.p("func Decode%s(data []byte) (*%s, error) {", acc.Name, acc.Name)
+		g.p("body, err := anchor.CheckDiscriminator(data, %sDiscriminator)", acc.Name)
+		g.p("if err != nil {")
+		g.p("return nil, fmt.Errorf(\"decode %s: %%w\", err)", acc.Name)
+		g.p("}")
+		g.p("var v %s", acc.Name)
+		g.p("d := borsh.NewDecoder(body)")
+		g.p("v.UnmarshalBorsh(d)")
+		g.p("if err := d.Err(); err != nil {")
+		g.p("return nil, fmt.Errorf(\"decode %s: %%w\", err)", acc.Name)
+		g.p("}")
+		g.p("return &v, nil")
+		g.p("}")
+		g.p("")
+	}
+
+	g.p("// DecodeAccount 根据 discriminator 识别并解码本程序的任意账户，返回账户类型名")
+	g.p("func DecodeAccount(data []byte) (string, any, error) {")
+	g.p("if len(data) < anchor.DiscriminatorSize {")
+	g.p("return \"\", nil, fmt.Errorf(\"account data too short: %%d bytes\", len(data))")
+	g.p("}")
+	g.p("switch anchor.Discriminator(data[:anchor.DiscriminatorSize]) {")
+	for _, acc := range g.idl.Accounts {
+		g.p("case %sDiscriminator:", acc.Name)
+		g.p("v, err := Decode%s(data)", acc.Name)
+		g.p("return %q, v, err", acc.Name)
+	}
+	g.p("}")
+	g.p("return \"\", nil, fmt.Errorf(\"unknown account discriminator %%x\", data[:anchor.DiscriminatorSize])")
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+func (g *generator) genEvents() error {
+	if len(g.idl.Events) == 0 {
+		return nil
+	}
+	g.use("sdk/anchor")
+	g.use("sdk/borsh")
+	g.use("fmt")
+	g.p("// 事件 discriminator：sha256(\"event:<Name>\")[:8]")
+	g.p("var (")
+	for _, ev := range g.idl.Events {
+		g.p("%sDiscriminator = %s", ev.Name, discriminatorLiteral(ev.Discriminator))
+	}
+	g.p(")")
+	g.p("")
+	for _, ev := range g.idl.Events {
+		if _, ok := g.idl.FindType(ev.Name); !ok {
+			return fmt.Errorf("event %s has no type definition", ev.Name)
+		}
+		g.p("// Decode%s 解码 \"Program data:\" 日志中的 %s 事件（已 base64 解码）", ev.Name, ev.Name)
+		g.p("func Decode%s(data []byte) (*%s, error) {", ev.Name, ev.Name)
+		g.p("body, err := anchor.CheckDiscriminator(data, %sDiscriminator)", ev.Name)
+		g.p("if err != nil {")
+		g.p("return nil, fmt.Errorf(\"decode %s: %%w\", err)", ev.Name)
+		g.p("}")
+		g.p("var v %s", ev.Name)
+		g.p("d := borsh.NewDecoder(body)")
+		g.p("v.UnmarshalBorsh(d)")
+		g.p("if err := d.Err(); err != nil {")
+		g.p("return nil, fmt.Errorf(\"decode %s: %%w\", err)", ev.Name)
+		g.p("}")
+		g.p("return &v, nil")
+		g.p("}")
+		g.p("")
+	}
+
+	g.p("// DecodeEvent 根据 discriminator 识别并解码本程序的任意事件，返回事件名")
+	g.p("func DecodeEvent(data []byte) (string, any, error) {")
+	g.p("if len(data) < anchor.DiscriminatorSize {")
+	g.p("return \"\", nil, fmt.Errorf(\"event data too short: %%d bytes\", len(data))")
+	g.p("}")
+	g.p("switch anchor.Discriminator(data[:anchor.DiscriminatorSize]) {")
+	for _, ev := range g.idl.Events {
+		g.p("case %sDiscriminator:", ev.Name)
+		g.p("v, err := Decode%s(data)", ev.Name)
+		g.p("return %q, v, err", ev.Name)
+	}
+	g.p("}")
+	g.p("return \"\", nil, fmt.Errorf(\"unknown event discriminator %%x\", data[:anchor.DiscriminatorSize])")
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// 指令
+// ---------------------------------------------------------------------------
+
+func (g *generator) genInstructions() error {
+	if len(g.idl.Instructions) == 0 {
+		return nil
+	}
+	g.use("sdk/anchor")
+	g.use("sdk/borsh")
+	g.use("github.com/blocto/solana-go-sdk/common")
+	g.use("github.com/blocto/solana-go-sdk/types")
+
+	g.p("// 指令 discriminator：sha256(\"global:<name>\")[:8]")
+	g.p("var (")
+	for _, ix := range g.idl.Instructions {
+		g.p("%sInstructionDiscriminator = %s", exportedName(ix.Name), discriminatorLiteral(ix.Discriminator))
+	}
+	g.p(")")
+	g.p("")
+
+	for _, ix := range g.idl.Instructions {
+		if err := g.genInstruction(ix); err != nil {
+			return fmt.Errorf("instruction %s: %w", ix.Name, err)
+		}
+	}
+	return nil
+}
+
+func (g *generator) genInstruction(ix anchor.IDLInstruction) error {
+	name := exportedName(ix.Name)
+
+	g.p("// %sArgs 是 %s 指令的参数（按 IDL 顺序 Borsh 编码）", name, ix.Name)
+	g.p("type %sArgs struct {", name)
+	for _, a := range ix.Args {
+		typ, err := g.goType(a.Type)
+		if err != nil {
+			return fmt.Errorf("arg %s: %w", a.Name, err)
+		}
+		g.docs(a.Docs)
+		g.p("%s %s", exportedName(a.Name), typ)
+	}
+	g.p("}")
+	g.p("")
+
+	g.p("// %sAccounts 是 %s 指令的账户列表", name, ix.Name)
+	g.p("type %sAccounts struct {", name)
+	for _, acc := range ix.Accounts {
+		g.docs(acc.Docs)
+		var flags []string
+		if acc.Writable {
+			flags = append(flags, "writable")
+		}
+		if acc.Signer {
+			flags = append(flags, "signer")
+		}
+		if acc.Optional {
+			flags = append(flags, "optional")
+		}
+		if acc.Address != "" {
+			flags = append(flags, "默认 "+acc.Address)
+		}
+		if acc.PDA != nil {
+			flags = append(flags, "PDA")
+		}
+		comment := ""
+		if len(flags) > 0 {
+			comment = " // " + strings.Join(flags, ", ")
+		}
+		g.p("%s common.PublicKey%s", exportedName(acc.Name), comment)
+	}
+	g.p("}")
+	g.p("")
+
+	if len(ix.Docs) > 0 {
+		g.p("// New%sInstruction 构造 %s 指令。", name, ix.Name)
+		g.p("//")
+		g.docs(ix.Docs)
+	} else {
+		g.p("// New%sInstruction 构造 %s 指令", name, ix.Name)
+	}
+	g.p("func New%sInstruction(accounts %sAccounts, args %sArgs) types.Instruction {", name, name, name)
+	g.p("e := borsh.NewEncoder()")
+	g.p("e.WriteRaw(%sInstructionDiscriminator[:])", name)
+	for _, a := range ix.Args {
+		if err := g.encode("args."+exportedName(a.Name), a.Type, 0); err != nil {
+			return err
+		}
+	}
+	for _, acc := range ix.Accounts {
+		field := "accounts." + exportedName(acc.Name)
+		switch {
+		case acc.Address != "":
+			g.p("if %s == (common.PublicKey{}) {", field)
+			g.p("%s = common.PublicKeyFromString(%q)", field, acc.Address)
+			g.p("}")
+		case acc.Optional:
+			// Anchor 约定：未提供的可选账户以程序 ID 占位
+			g.p("if %s == (common.PublicKey{}) {", field)
+			g.p("%s = ProgramID", field)
+			g.p("}")
+		}
+	}
+	g.p("return types.Instruction{")
+	g.p("ProgramID: ProgramID,")
+	g.p("Accounts: []types.AccountMeta{")
+	for _, acc := range ix.Accounts {
+		g.p("{PubKey: accounts.%s, IsSigner: %t, IsWritable: %t},", exportedName(acc.Name), acc.Signer, acc.Writable)
+	}
+	g.p("},")
+	g.p("Data: e.Bytes(),")
+	g.p("}")
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// PDA
+// ---------------------------------------------------------------------------
+
+type pdaParam struct {
+	name   string
+	goType string
+}
+
+type pdaFunc struct {
+	account string
+	ix      string
+	key     string
+	params  []pdaParam
+	exprs   []string
+	desc    []string
+	specs   []string // anchor.SeedSpec 字面量，用于 PDATemplates
+}
+
+func (g *generator) buildPDA(ix anchor.IDLInstruction, acc anchor.IDLAccountArg) (pdaFunc, error) {
+	f := pdaFunc{account: acc.Name, ix: ix.Name}
+	seen := map[string]bool{}
+	var keyParts []string
+	for _, s := range acc.PDA.Seeds {
+		switch s.Kind {
+		case "const":
+			if isPrintable(s.Value) {
+				f.exprs = append(f.exprs, fmt.Sprintf("[]byte(%q)", string(s.Value)))
+				f.desc = append(f.desc, strconv.Quote(string(s.Value)))
+			} else {
+				parts := make([]string, len(s.Value))
+				for i, b := range s.Value {
+					parts[i] = strconv.Itoa(int(b))
+				}
+				f.exprs = append(f.exprs, "[]byte{"+strings.Join(parts, ", ")+"}")
+				f.desc = append(f.desc, "0x"+fmt.Sprintf("%x", s.Value))
+			}
+			f.specs = append(f.specs, fmt.Sprintf("{Kind: anchor.SeedConst, Value: %s}", f.exprs[len(f.exprs)-1]))
+			keyParts = append(keyParts, "const:"+string(s.Value))
+		case "account":
+			if strings.Contains(s.Path, ".") {
+				return f, fmt.Errorf("account %s: nested seed path %q is not supported", acc.Name, s.Path)
+			}
+			p := localName(s.Path)
+			if !seen[p] {
+				f.params = append(f.params, pdaParam{p, "common.PublicKey"})
+				seen[p] = true
+			}
+			f.exprs = append(f.exprs, p+".Bytes()")
+			f.desc = append(f.desc, s.Path)
+			f.specs = append(f.specs, fmt.Sprintf("{Kind: anchor.SeedPubkey, Name: %q}", s.Path))
+			keyParts = append(keyParts, "account:"+s.Path)
+		case "arg":
+			arg, ok := findArg(ix, s.Path)
+			if !ok {
+				return f, fmt.Errorf("account %s: seed arg %q not found", acc.Name, s.Path)
+			}
+			p := localName(s.Path)
+			typ, expr, err := seedExpr(p, arg.Type)
+			if err != nil {
+				return f, fmt.Errorf("account %s: %w", acc.Name, err)
+			}
+			if !seen[p] {
+				f.params = append(f.params, pdaParam{p, typ})
+				seen[p] = true
+			}
+			f.exprs = append(f.exprs, expr)
+			f.desc = append(f.desc, s.Path)
+			f.specs = append(f.specs, fmt.Sprintf("{Kind: anchor.%s, Name: %q}", seedKinds[arg.Type.Primitive], s.Path))
+			keyParts = append(keyParts, "arg:"+arg.Type.Primitive)
+		default:
+			return f, fmt.Errorf("account %s: unsupported seed kind %q", acc.Name, s.Kind)
+		}
+	}
+	f.key = strings.Join(keyParts, "|")
+	return f, nil
+}
+
+// findArg 按名称查找指令参数；Rust 端未使用的参数在 IDL 中带有下划线前缀
+func findArg(ix anchor.IDLInstruction, path string) (anchor.IDLField, bool) {
+	for _, a := range ix.Args {
+		if a.Name == path || strings.TrimLeft(a.Name, "_") == strings.TrimLeft(path, "_") {
+			return a, true
+		}
+	}
+	return anchor.IDLField{}, false
+}
+
+func seedExpr(param string, t anchor.IDLType) (string, string, error) {
+	switch t.Primitive {
+	case "string":
+		return "string", "[]byte(" + param + ")", nil
+	case "bytes":
+		return "[]byte", param, nil
+	case "pubkey":
+		return "common.PublicKey", param + ".Bytes()", nil
+	case "u8":
+		return "uint8", "[]byte{" + param + "}", nil
+	case "u16":
+		return "uint16", "anchor.U16Seed(" + param + ")", nil
+	case "u32":
+		return "uint32", "anchor.U32Seed(" + param + ")", nil
+	case "u64":
+		return "uint64", "anchor.U64Seed(" + param + ")", nil
+	case "i64":
+		return "int64", "anchor.I64Seed(" + param + ")", nil
+	}
+	return "", "", fmt.Errorf("unsupported seed arg type %+v", t)
+}
+
+// seedKinds 把 IDL 参数类型映射为 anchor.SeedKind 常量名
+var seedKinds = map[string]string{
+	"string": "SeedString",
+	"bytes":  "SeedHex",
+	"pubkey": "SeedPubkey",
+	"u8":     "SeedU8",
+	"u16":    "SeedU16",
+	"u32":    "SeedU32",
+	"u64":    "SeedU64",
+	"i64":    "SeedI64",
+}
+
+func isPrintable(b []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *generator) genPDAs() error {
+	var funcs []pdaFunc
+	keys := map[string]map[string]bool{}
+	for _, ix := range g.idl.Instructions {
+		for _, acc := range ix.Accounts {
+			if acc.PDA == nil {
+				continue
+			}
+			f, err := g.buildPDA(ix, acc)
+			if err != nil {
+				return fmt.Errorf("instruction %s: %w", ix.Name, err)
+			}
+			if keys[acc.Name] == nil {
+				keys[acc.Name] = map[string]bool{}
+			}
+			if keys[acc.Name][f.key] {
+				continue
+			}
+			keys[acc.Name][f.key] = true
+			funcs = append(funcs, f)
+		}
+	}
+	if len(funcs) == 0 {
+		return nil
+	}
+	g.use("github.com/blocto/solana-go-sdk/common")
+	for _, f := range funcs {
+		// 同名账户在不同指令中使用不同 seeds 时，以指令名区分
+		name := "Find" + exportedName(f.account) + "Address"
+		if len(keys[f.account]) > 1 {
+			name = "Find" + exportedName(f.ix) + exportedName(f.account) + "Address"
+		}
+		params := make([]string, len(f.params))
+		for i, p := range f.params {
+			params[i] = p.name + " " + p.goType
+			if strings.HasPrefix(p.goType, "common.") {
+				g.use("github.com/blocto/solana-go-sdk/common")
+			}
+		}
+		for _, e := range f.exprs {
+			if strings.HasPrefix(e, "anchor.") {
+				g.use("sdk/anchor")
+			}
+		}
+		g.p("// %s 派生 %s PDA：seeds = [%s]", name, f.account, strings.Join(f.desc, ", "))
+		g.p("func %s(%s) (common.PublicKey, uint8, error) {", name, strings.Join(params, ", "))
+		g.p("return common.FindProgramAddress([][]byte{%s}, ProgramID)", strings.Join(f.exprs, ", "))
+		g.p("}")
+		g.p("")
+	}
+
+	g.use("sdk/anchor")
+	g.p("// PDATemplates 列出各 PDA 账户的 seed 模板，供 anchor.FindPDA 按地址反查")
+	g.p("var PDATemplates = []anchor.PDATemplate{")
+	for _, f := range funcs {
+		g.p("{Program: %q, Account: %q, ProgramID: ProgramID, Seeds: []anchor.SeedSpec{%s}},", g.idl.Metadata.Name, f.account, strings.Join(f.specs, ", "))
+	}
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// 错误码
+// ---------------------------------------------------------------------------
+
+func (g *generator) genErrors() error {
+	if len(g.idl.Errors) == 0 {
+		return nil
+	}
+	g.use("fmt")
+	g.use("sdk/anchor")
+	g.use("github.com/blocto/solana-go-sdk/common")
+	g.p("// ErrorCode 是程序 #[error_code] 定义的自定义错误码（从 6000 起）")
+	g.p("type ErrorCode uint32")
+	g.p("")
+	g.p("const (")
+	for _, e := range g.idl.Errors {
+		g.p("Err%s ErrorCode = %d", exportedName(e.Name), e.Code)
+	}
+	g.p(")")
+	g.p("")
+	g.p("// Name 返回错误码在 Rust 中的变体名")
+	g.p("func (c ErrorCode) Name() string {")
+	g.p("switch c {")
+	for _, e := range g.idl.Errors {
+		g.p("case Err%s:", exportedName(e.Name))
+		g.p("return %q", e.Name)
+	}
+	g.p("}")
+	g.p("return \"\"")
+	g.p("}")
+	g.p("")
+	g.p("// Message 返回 #[msg(...)] 中的错误描述")
+	g.p("func (c ErrorCode) Message() string {")
+	g.p("switch c {")
+	for _, e := range g.idl.Errors {
+		g.p("case Err%s:", exportedName(e.Name))
+		g.p("return %q", e.Msg)
+	}
+	g.p("}")
+	g.p("return \"\"")
+	g.p("}")
+	g.p("")
+	g.p("// Code 返回数值错误码")
+	g.p("func (c ErrorCode) Code() uint32 {")
+	g.p("return uint32(c)")
+	g.p("}")
+	g.p("")
+	g.p("// Program 返回定义该错误码的程序，配合 anchor.ProgramError 支持 errors.Is")
+	g.p("func (c ErrorCode) Program() common.PublicKey {")
+	g.p("return ProgramID")
+	g.p("}")
+	g.p("")
+	g.p("func (c ErrorCode) Error() string {")
+	g.p("if name := c.Name(); name != \"\" {")
+	g.p("return fmt.Sprintf(\"%%s (%%d): %%s\", name, uint32(c), c.Message())")
+	g.p("}")
+	g.p("return fmt.Sprintf(\"unknown error code %%d\", uint32(c))")
+	g.p("}")
+	g.p("")
+	g.p("// Errors 是 IDL 中的错误表，init 时登记到 anchor.DecodeError 使用的注册表")
+	g.p("var Errors = []anchor.IDLError{")
+	for _, e := range g.idl.Errors {
+		g.p("{Code: %d, Name: %q, Msg: %q},", e.Code, e.Name, e.Msg)
+	}
+	g.p("}")
+	g.p("")
+	g.p("func init() {")
+	g.p("anchor.RegisterErrors(ProgramID, Errors)")
+	g.p("}")
+	g.p("")
+	return nil
+}
@@ -0,0 +1,55 @@
+// anchorgen 读取 Anchor IDL 并生成带类型的 Go 客户端包：
+// 指令构造器、账户结构体及 Borsh 解码、PDA 派生函数、事件解码与错误码枚举。
+//
+// 通常通过 go generate 调用：
+//
+//	//go:generate go run sdk/cmd/anchorgen -idl ../../idl/favorite.json -pkg favorite -out favorite_gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+
+	"sdk/anchor"
+)
+
+func main() {
+	idlPath := flag.String("idl", "", "Path to Anchor IDL JSON (target/idl/<program>.json)")
+	pkg := flag.String("pkg", "", "Go package name (default: IDL metadata name)")
+	out := flag.String("out", "", "Output file (default: stdout)")
+	flag.Parse()
+	if *idlPath == "" {
+		log.Fatal("missing --idl")
+	}
+
+	idl, err := anchor.LoadIDL(*idlPath)
+	if err != nil {
+		log.Fatalf("load idl: %v", err)
+	}
+	name := *pkg
+	if name == "" {
+		name = idl.Metadata.Name
+	}
+
+	src, err := generate(idl, name, filepath.Base(*idlPath))
+	if err != nil {
+		log.Fatalf("generate: %v", err)
+	}
+	formatted, err := format.Source(src)
+	if err != nil {
+		// 输出未格式化的源码便于定位生成器问题
+		os.Stderr.Write(src)
+		log.Fatalf("gofmt generated code: %v", err)
+	}
+	if *out == "" {
+		fmt.Print(string(formatted))
+		return
+	}
+	if err := os.WriteFile(*out, formatted, 0o644); err != nil {
+		log.Fatalf("write %s: %v", *out, err)
+	}
+}
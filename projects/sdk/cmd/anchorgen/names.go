@@ -0,0 +1,78 @@
+package main
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// 需要整体大写的缩写
+var initialisms = map[string]string{
+	"id":   "ID",
+	"pda":  "PDA",
+	"url":  "URL",
+	"uri":  "URI",
+	"ui":   "UI",
+	"json": "JSON",
+	"alt":  "ALT",
+}
+
+// exportedName 把 snake_case / camelCase 名称转为导出的 Go 标识符，
+// 例如 "_poll_id" -> "PollID"，"system_program" -> "SystemProgram"
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, part := range splitWords(name) {
+		if up, ok := initialisms[strings.ToLower(part)]; ok {
+			b.WriteString(up)
+			continue
+		}
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
+
+// localName 生成非导出的局部变量名，例如 "poll_id" -> "pollID"
+func localName(name string) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return "v"
+	}
+	var b strings.Builder
+	b.WriteString(strings.ToLower(words[0]))
+	for _, part := range words[1:] {
+		if up, ok := initialisms[strings.ToLower(part)]; ok {
+			b.WriteString(up)
+			continue
+		}
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	s := b.String()
+	if token.Lookup(s).IsKeyword() {
+		s += "_"
+	}
+	return s
+}
+
+// splitWords 按下划线与大小写边界切分名称
+func splitWords(name string) []string {
+	var words []string
+	for _, seg := range strings.Split(name, "_") {
+		if seg == "" {
+			continue
+		}
+		start := 0
+		r := []rune(seg)
+		for i := 1; i < len(r); i++ {
+			if unicode.IsUpper(r[i]) && !unicode.IsUpper(r[i-1]) {
+				words = append(words, string(r[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(r[start:]))
+	}
+	return words
+}
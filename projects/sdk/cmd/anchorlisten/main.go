@@ -0,0 +1,118 @@
+// anchorlisten 订阅程序日志，解码 Anchor 事件（emit!）并逐行输出 JSON：
+//
+//	go run sdk/cmd/anchorlisten -program chain -cluster devnet -cursor chain.cursor
+//
+// 默认通过 logsSubscribe 接收推送，websocket 不可用时退回 getSignaturesForAddress 轮询；
+// 指定 -cursor 时每处理完一笔交易都会记录签名，重启后从该签名继续。
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+
+	"sdk/anchor"
+	"sdk/programs/chain"
+	"sdk/ws"
+)
+
+// programs 是已生成事件解码器的程序
+var programs = map[string]struct {
+	id     common.PublicKey
+	decode anchor.EventDecoder
+}{
+	"chain": {chain.ProgramID, chain.DecodeEvent},
+}
+
+func main() {
+	program := flag.String("program", "", "Program name: "+strings.Join(programNames(), "|"))
+	cluster := flag.String("cluster", "local", "Cluster: devnet|testnet|mainnet|local")
+	rpcURL := flag.String("rpc", "", "Custom RPC endpoint URL (override)")
+	wsURL := flag.String("ws", "", "Custom websocket endpoint URL (default: derived from RPC URL)")
+	poll := flag.Bool("poll", false, "Disable websocket and only poll getSignaturesForAddress")
+	interval := flag.Duration("interval", 5*time.Second, "Polling interval / reconnect backoff")
+	commitment := flag.String("commitment", "confirmed", "Commitment: confirmed|finalized")
+	since := flag.String("since", "", "Resume after this signature (overrides --cursor contents)")
+	cursor := flag.String("cursor", "", "File storing the last processed signature")
+	flag.Parse()
+
+	p, ok := programs[*program]
+	if !ok {
+		log.Fatalf("unknown --program %q (known: %s)", *program, strings.Join(programNames(), ", "))
+	}
+	endpoint := strings.TrimSpace(*rpcURL)
+	if endpoint == "" {
+		endpoint = endpointFor(*cluster)
+	}
+
+	l := &anchor.Listener{
+		RPC:          client.NewClient(endpoint),
+		ProgramID:    p.id,
+		Decode:       p.decode,
+		Commitment:   rpc.Commitment(*commitment),
+		PollInterval: *interval,
+		Since:        strings.TrimSpace(*since),
+		OnError: func(err error) {
+			log.Printf("listener: %v", err)
+		},
+	}
+	if !*poll {
+		l.WSEndpoint = strings.TrimSpace(*wsURL)
+		if l.WSEndpoint == "" {
+			l.WSEndpoint = ws.EndpointFor(endpoint)
+		}
+	}
+	if *cursor != "" {
+		if l.Since == "" {
+			if b, err := os.ReadFile(*cursor); err == nil {
+				l.Since = strings.TrimSpace(string(b))
+			} else if !os.IsNotExist(err) {
+				log.Fatalf("read cursor: %v", err)
+			}
+		}
+		l.Checkpoint = func(sig string) error {
+			return os.WriteFile(*cursor, []byte(sig+"\n"), 0o644)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	enc := json.NewEncoder(os.Stdout)
+	if err := l.Run(ctx, func(ev anchor.Event) error {
+		return enc.Encode(ev)
+	}); err != nil {
+		log.Fatalf("listen: %v", err)
+	}
+}
+
+func programNames() []string {
+	names := make([]string, 0, len(programs))
+	for name := range programs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func endpointFor(cluster string) string {
+	switch strings.ToLower(strings.TrimSpace(cluster)) {
+	case "mainnet", "mainnet-beta":
+		return rpc.MainnetRPCEndpoint
+	case "testnet":
+		return rpc.TestnetRPCEndpoint
+	case "devnet":
+		return rpc.DevnetRPCEndpoint
+	default:
+		return "http://127.0.0.1:8899"
+	}
+}
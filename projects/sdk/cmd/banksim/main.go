@@ -0,0 +1,99 @@
+// banksim 在本地端口上提供内存中的 Solana bank 模拟器（sdk/banksim）的 JSON-RPC 接口，
+// 各 Go 客户端可用 --rpc 指向它，无需启动 validator：
+//
+//	go run sdk/cmd/banksim -listen 127.0.0.1:8899 -fund ~/.config/solana/id.json=10000000000
+//	go run . transfer --fromFile ~/.config/solana/id.json --to <addr> --lamports 1000 --rpc http://127.0.0.1:8899
+//
+// -fund 可重复，值为 <base58 地址或 keypair 文件>=<lamports>。
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+
+	"sdk/banksim"
+)
+
+// fundFlags 收集重复的 -fund 参数
+type fundFlags []string
+
+func (f *fundFlags) String() string     { return strings.Join(*f, ",") }
+func (f *fundFlags) Set(v string) error { *f = append(*f, v); return nil }
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8899", "Listen address")
+	lamportsPerSig := flag.Uint64("lamports-per-signature", banksim.DefaultLamportsPerSignature, "Fee per signature")
+	maxAge := flag.Uint64("max-blockhash-age", banksim.DefaultMaxBlockhashAge, "Slots a blockhash stays valid")
+	var funds fundFlags
+	flag.Var(&funds, "fund", "Initial balance: <address|keypair.json>=<lamports> (repeatable)")
+	flag.Parse()
+
+	bank := banksim.New(banksim.Config{LamportsPerSignature: *lamportsPerSig, MaxBlockhashAge: *maxAge})
+	for _, f := range funds {
+		key, lamports, err := parseFund(f)
+		if err != nil {
+			log.Fatalf("invalid -fund %q: %v", f, err)
+		}
+		bank.Fund(key, lamports)
+		log.Printf("banksim: funded %s with %d lamports", key.ToBase58(), lamports)
+	}
+
+	srv := &http.Server{Addr: *listen, Handler: bank.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+	log.Printf("banksim: JSON-RPC on http://%s", *listen)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+// parseFund 解析 <base58 地址或 keypair 文件>=<lamports>
+func parseFund(s string) (common.PublicKey, uint64, error) {
+	target, amount, ok := strings.Cut(s, "=")
+	if !ok {
+		return common.PublicKey{}, 0, errors.New("want <address|keypair.json>=<lamports>")
+	}
+	lamports, err := strconv.ParseUint(amount, 10, 64)
+	if err != nil {
+		return common.PublicKey{}, 0, fmt.Errorf("invalid lamports: %w", err)
+	}
+	if b, err := base58.Decode(target); err == nil && len(b) == 32 {
+		return common.PublicKeyFromBytes(b), lamports, nil
+	}
+	data, err := os.ReadFile(target)
+	if err != nil {
+		return common.PublicKey{}, 0, err
+	}
+	// keypair 文件是 64 个整数的数组（solana-keygen 格式）
+	var ints []int
+	if err := json.Unmarshal(data, &ints); err != nil {
+		return common.PublicKey{}, 0, fmt.Errorf("invalid keypair file: %w", err)
+	}
+	secret := make([]byte, len(ints))
+	for i, v := range ints {
+		secret[i] = byte(v)
+	}
+	acc, err := types.AccountFromBytes(secret)
+	if err != nil {
+		return common.PublicKey{}, 0, err
+	}
+	return acc.PublicKey, lamports, nil
+}
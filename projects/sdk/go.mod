@@ -0,0 +1,14 @@
+module sdk
+
+go 1.23.4
+
+require (
+	github.com/blocto/solana-go-sdk v1.30.0
+	github.com/gorilla/websocket v1.5.3
+	github.com/mr-tron/base58 v1.2.0
+)
+
+require (
+	filippo.io/edwards25519 v1.0.0-rc.1 // indirect
+	github.com/near/borsh-go v0.3.2-0.20220516180422-1ff87d108454 // indirect
+)
@@ -0,0 +1,22 @@
+// Package idl 内嵌各 Anchor 程序的 IDL（anchor build 生成的 target/idl/*.json），
+// 供需要在运行时读取类型定义的工具使用，例如账户大小与租金计算。
+package idl
+
+import (
+	"embed"
+	"fmt"
+
+	"sdk/anchor"
+)
+
+//go:embed *.json
+var files embed.FS
+
+// Load 按程序名（favorite、voting、chain）解析内嵌的 IDL
+func Load(program string) (*anchor.IDL, error) {
+	data, err := files.ReadFile(program + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("no embedded idl for program %q", program)
+	}
+	return anchor.ParseIDL(data)
+}
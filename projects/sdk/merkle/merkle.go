@@ -0,0 +1,149 @@
+// Package merkle 构建投票程序 MerkleAllowlist 模式使用的地址白名单 Merkle 树，
+// 并生成 / 校验成员证明。哈希规则与链上 verify_allowlist_proof 一致：
+//
+//	leaf = sha256(0x00 || pubkey)
+//	node = sha256(0x01 || min(a, b) || max(a, b))
+//
+// 子节点按字节序排序后再哈希，因此证明只需兄弟节点哈希，无需左右标记。
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/blocto/solana-go-sdk/common"
+)
+
+// Hash 是树节点哈希
+type Hash [32]byte
+
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// MarshalText 以十六进制编码，便于 JSON 输出
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText 解析 64 位十六进制字符串
+func (h *Hash) UnmarshalText(text []byte) error {
+	parsed, err := ParseHash(string(text))
+	if err != nil {
+		return err
+	}
+	*h = parsed
+	return nil
+}
+
+// ParseHash 解析 64 位十六进制哈希（可带 0x 前缀）
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+	if len(s) >= 2 && (s[:2] == "0x" || s[:2] == "0X") {
+		s = s[2:]
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(h) {
+		return h, fmt.Errorf("invalid merkle hash %q: want 64 hex characters", s)
+	}
+	copy(h[:], b)
+	return h, nil
+}
+
+// Leaf 计算地址对应的叶子哈希
+func Leaf(pk common.PublicKey) Hash {
+	return sha256.Sum256(append([]byte{0}, pk.Bytes()...))
+}
+
+// Node 计算两个子节点的父节点哈希（与顺序无关）
+func Node(a, b Hash) Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	buf := make([]byte, 0, 1+2*len(a))
+	buf = append(buf, 1)
+	buf = append(buf, a[:]...)
+	buf = append(buf, b[:]...)
+	return sha256.Sum256(buf)
+}
+
+// Tree 是按叶子哈希排序构建的 Merkle 树；奇数个节点时最后一个直接晋升到上一层
+type Tree struct {
+	levels [][]Hash // levels[0] 为叶子，最后一层为根
+	index  map[Hash]int
+}
+
+// New 由地址列表构建树，重复地址只保留一个
+func New(addrs []common.PublicKey) (*Tree, error) {
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("merkle tree needs at least one address")
+	}
+	seen := make(map[Hash]bool, len(addrs))
+	leaves := make([]Hash, 0, len(addrs))
+	for _, pk := range addrs {
+		leaf := Leaf(pk)
+		if !seen[leaf] {
+			seen[leaf] = true
+			leaves = append(leaves, leaf)
+		}
+	}
+	// 叶子排序后，同一地址集合无论输入顺序都得到相同的根
+	sort.Slice(leaves, func(i, j int) bool { return bytes.Compare(leaves[i][:], leaves[j][:]) < 0 })
+
+	t := &Tree{levels: [][]Hash{leaves}, index: make(map[Hash]int, len(leaves))}
+	for i, leaf := range leaves {
+		t.index[leaf] = i
+	}
+	for level := leaves; len(level) > 1; {
+		next := make([]Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, Node(level[i], level[i+1]))
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t, nil
+}
+
+// Root 返回树根，即投票程序 MerkleAllowlist 的 root
+func (t *Tree) Root() Hash {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Len 返回去重后的地址数
+func (t *Tree) Len() int {
+	return len(t.levels[0])
+}
+
+// Proof 返回地址从叶子到根的兄弟节点哈希，不在白名单中时报错
+func (t *Tree) Proof(pk common.PublicKey) ([]Hash, error) {
+	i, ok := t.index[Leaf(pk)]
+	if !ok {
+		return nil, fmt.Errorf("%s is not in the allowlist", pk.ToBase58())
+	}
+	proof := []Hash{}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := i ^ 1
+		if sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		i /= 2
+	}
+	return proof, nil
+}
+
+// Verify 按链上规则校验证明
+func Verify(root Hash, pk common.PublicKey, proof []Hash) bool {
+	node := Leaf(pk)
+	for _, sibling := range proof {
+		node = Node(node, sibling)
+	}
+	return node == root
+}
@@ -0,0 +1,147 @@
+package merkle
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// keyOf 返回 32 个字节均为 b 的公钥，用于固定的测试向量
+func keyOf(b byte) common.PublicKey {
+	return common.PublicKeyFromBytes(bytes.Repeat([]byte{b}, 32))
+}
+
+func randomKeys(n int) []common.PublicKey {
+	keys := make([]common.PublicKey, n)
+	for i := range keys {
+		keys[i] = types.NewAccount().PublicKey
+	}
+	return keys
+}
+
+// TestKnownAnswer 与 vote/voting/tests/voting.ts 中的向量相同，
+// 两边的实现都必须得到这些哈希
+func TestKnownAnswer(t *testing.T) {
+	leaves := map[byte]string{
+		1: "dcffe786ded16d283c663846ad0c4ff26558fccde36ca9d30b2ea19eade9fc0e",
+		2: "cba8c596120bdb69debbd923d92cba948bde7c7d06a465a1bb7d98d3116038fa",
+		3: "acaa04663a8547a2f70c60cc18f9378796b13c4f9a08f70d6adae662365b30c6",
+	}
+	for b, want := range leaves {
+		if got := Leaf(keyOf(b)).String(); got != want {
+			t.Fatalf("Leaf(%d...) = %s, want %s", b, got, want)
+		}
+	}
+
+	for _, tt := range []struct {
+		name string
+		keys []common.PublicKey
+		root string
+	}{
+		{"two leaves", []common.PublicKey{keyOf(1), keyOf(2)}, "eba78221b5ef7ed38c4b246fda4b1a3b283ce4c19047907b43676eb2863585d6"},
+		{"three leaves", []common.PublicKey{keyOf(1), keyOf(2), keyOf(3)}, "b9fa49a224871b2afd7bf8352832868661aa627cbb176cc8934edd575d35c701"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := New(tt.keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tree.Root().String(); got != tt.root {
+				t.Fatalf("root = %s, want %s", got, tt.root)
+			}
+		})
+	}
+}
+
+func TestProofs(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4, 5, 7, 8, 33} {
+		keys := randomKeys(n)
+		tree, err := New(keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tree.Len() != n {
+			t.Fatalf("%d keys: Len() = %d", n, tree.Len())
+		}
+		for _, pk := range keys {
+			proof, err := tree.Proof(pk)
+			if err != nil {
+				t.Fatalf("%d keys: %v", n, err)
+			}
+			if !Verify(tree.Root(), pk, proof) {
+				t.Fatalf("%d keys: proof of %s does not verify", n, pk.ToBase58())
+			}
+			// 篡改任一兄弟节点后证明失效
+			if len(proof) > 0 {
+				proof[0][0] ^= 1
+				if Verify(tree.Root(), pk, proof) {
+					t.Fatalf("%d keys: a tampered proof verifies", n)
+				}
+			}
+		}
+		outsider := types.NewAccount().PublicKey
+		if _, err := tree.Proof(outsider); err == nil {
+			t.Fatalf("%d keys: proof for an address outside the allowlist", n)
+		}
+	}
+}
+
+func TestSingleLeaf(t *testing.T) {
+	pk := keyOf(1)
+	tree, err := New([]common.PublicKey{pk})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 单个地址时根就是叶子，证明为空
+	if tree.Root() != Leaf(pk) {
+		t.Fatalf("root = %s, want the leaf %s", tree.Root(), Leaf(pk))
+	}
+	proof, err := tree.Proof(pk)
+	if err != nil || len(proof) != 0 {
+		t.Fatalf("proof = %v, %v; want empty", proof, err)
+	}
+	if !Verify(tree.Root(), pk, proof) {
+		t.Fatal("empty proof does not verify")
+	}
+	if _, err := New(nil); err == nil {
+		t.Fatal("a tree without addresses was built")
+	}
+}
+
+func TestDuplicatesAndOrder(t *testing.T) {
+	keys := randomKeys(5)
+	tree, err := New(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 重复地址被去掉，输入顺序不影响根
+	shuffled := []common.PublicKey{keys[4], keys[2], keys[2], keys[0], keys[3], keys[1], keys[4]}
+	other, err := New(shuffled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Len() != len(keys) || other.Root() != tree.Root() {
+		t.Fatalf("Len() = %d, root %s; want %d, %s", other.Len(), other.Root(), len(keys), tree.Root())
+	}
+}
+
+func TestParseHash(t *testing.T) {
+	h := Leaf(keyOf(1))
+	for _, s := range []string{h.String(), "0x" + h.String()} {
+		got, err := ParseHash(s)
+		if err != nil || got != h {
+			t.Fatalf("ParseHash(%q) = %s, %v", s, got, err)
+		}
+	}
+	for _, s := range []string{"", "zz", h.String()[:62]} {
+		if _, err := ParseHash(s); err == nil {
+			t.Fatalf("ParseHash(%q) succeeded", s)
+		}
+	}
+	var text Hash
+	if err := text.UnmarshalText([]byte(h.String())); err != nil || text != h {
+		t.Fatalf("UnmarshalText = %s, %v", text, err)
+	}
+}
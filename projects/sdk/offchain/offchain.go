@@ -0,0 +1,111 @@
+// Package offchain 实现 Solana 链下消息签名（与 `solana sign-offchain-message`
+// 相同的 v0 信封格式）以及原始 ed25519 签名的校验：
+//
+//	"\xffsolana offchain" || version(u8=0) || format(u8) || len(u16 LE) || message
+//
+// 信封前缀以 0xff 开头，不可能是合法的交易消息，因此签名无法被重放为交易。
+package offchain
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// SigningDomain 是所有链下消息的固定前缀
+const SigningDomain = "\xffsolana offchain"
+
+const (
+	// MaxLedgerLen 是硬件钱包可显示的最长消息
+	MaxLedgerLen = 1212
+	// MaxLen 是 v0 格式允许的最长消息（整个信封不超过 65535 字节）
+	MaxLen = 65515
+)
+
+// Format 是 v0 信封中的消息格式
+type Format uint8
+
+const (
+	// RestrictedASCII 仅含可打印 ASCII（0x20-0x7e），长度不超过 MaxLedgerLen
+	RestrictedASCII Format = iota
+	// LimitedUTF8 为 UTF-8，长度不超过 MaxLedgerLen
+	LimitedUTF8
+	// ExtendedUTF8 为 UTF-8，长度不超过 MaxLen
+	ExtendedUTF8
+)
+
+func (f Format) String() string {
+	switch f {
+	case RestrictedASCII:
+		return "restricted-ascii"
+	case LimitedUTF8:
+		return "limited-utf8"
+	case ExtendedUTF8:
+		return "extended-utf8"
+	}
+	return fmt.Sprintf("format(%d)", uint8(f))
+}
+
+// Detect 按 Solana CLI 的规则选择能容纳 message 的最严格格式
+func Detect(message []byte) (Format, error) {
+	switch {
+	case len(message) == 0:
+		return 0, errors.New("offchain message is empty")
+	case len(message) > MaxLen:
+		return 0, fmt.Errorf("offchain message is %d bytes, max %d", len(message), MaxLen)
+	case !utf8.Valid(message):
+		return 0, errors.New("offchain message is not valid UTF-8")
+	case len(message) > MaxLedgerLen:
+		return ExtendedUTF8, nil
+	}
+	for _, b := range message {
+		if b < 0x20 || b > 0x7e {
+			return LimitedUTF8, nil
+		}
+	}
+	return RestrictedASCII, nil
+}
+
+// Encode 返回 message 的 v0 信封，即实际被签名的字节
+func Encode(message []byte) ([]byte, error) {
+	format, err := Detect(message)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, len(SigningDomain)+4+len(message))
+	buf = append(buf, SigningDomain...)
+	buf = append(buf, 0, byte(format))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(message)))
+	return append(buf, message...), nil
+}
+
+// Sign 用 signer 对 message 的信封签名
+func Sign(signer types.Account, message []byte) ([]byte, error) {
+	envelope, err := Encode(message)
+	if err != nil {
+		return nil, err
+	}
+	return signer.Sign(envelope), nil
+}
+
+// Verify 校验 signature 是否为 pubkey 对 message 信封的签名
+func Verify(pubkey common.PublicKey, message, signature []byte) (bool, error) {
+	envelope, err := Encode(message)
+	if err != nil {
+		return false, err
+	}
+	return VerifyRaw(pubkey, envelope, signature), nil
+}
+
+// VerifyRaw 校验对原始字节的 ed25519 签名
+func VerifyRaw(pubkey common.PublicKey, message, signature []byte) bool {
+	if len(signature) != ed25519.SignatureSize {
+		return false
+	}
+	return ed25519.Verify(pubkey.Bytes(), message, signature)
+}
@@ -0,0 +1,127 @@
+package offchain
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+)
+
+// TestEncodeKnownAnswer 使用 solana-sdk offchain_message 单元测试中的 v0 向量
+// （"Test Message"）。该测试同时给出信封的 sha256，用于独立核对字节；
+// 向量取自 solana-sdk 源码，并非本地运行 solana-cli 生成。
+func TestEncodeKnownAnswer(t *testing.T) {
+	want := []byte{
+		255, 115, 111, 108, 97, 110, 97, 32, 111, 102, 102, 99, 104, 97, 105, 110,
+		0, 0, 12, 0,
+		84, 101, 115, 116, 32, 77, 101, 115, 115, 97, 103, 101,
+	}
+	got, err := Encode([]byte("Test Message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("Encode = %v\nwant %v", got, want)
+	}
+	hash := sha256.Sum256(got)
+	if h := base58.Encode(hash[:]); h != "HG5JydBGjtjTfD3sSn21ys5NTWPpXzmqifiGC2BVUjkD" {
+		t.Fatalf("envelope hash = %s", h)
+	}
+}
+
+func TestEncodeUTF8Header(t *testing.T) {
+	message := []byte("Тестовое сообщение")
+	got, err := Encode(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := append([]byte(SigningDomain), 0, byte(LimitedUTF8), byte(len(message)), 0)
+	if !bytes.HasPrefix(got, header) || !bytes.Equal(got[len(header):], message) {
+		t.Fatalf("Encode = %v", got)
+	}
+}
+
+func TestDetectBoundaries(t *testing.T) {
+	ascii := func(n int) []byte { return bytes.Repeat([]byte("a"), n) }
+	// "é" 为两字节，补一个 ASCII 字节得到指定长度的非 ASCII 消息
+	utf8 := func(n int) []byte {
+		return append(bytes.Repeat([]byte("é"), n/2), ascii(n%2)...)
+	}
+	tests := []struct {
+		name    string
+		message []byte
+		want    Format
+		wantErr bool
+	}{
+		{"empty", nil, 0, true},
+		{"ascii at the ledger limit", ascii(MaxLedgerLen), RestrictedASCII, false},
+		{"ascii past the ledger limit", ascii(MaxLedgerLen + 1), ExtendedUTF8, false},
+		{"control character", []byte("line\nbreak"), LimitedUTF8, false},
+		{"utf8 at the ledger limit", utf8(MaxLedgerLen), LimitedUTF8, false},
+		{"utf8 past the ledger limit", utf8(MaxLedgerLen + 1), ExtendedUTF8, false},
+		{"at the max length", ascii(MaxLen), ExtendedUTF8, false},
+		{"past the max length", ascii(MaxLen + 1), 0, true},
+		{"invalid utf8", []byte{0xff, 0xfe}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Detect(tt.message)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("Detect(%d bytes) = %s, %v; want %s, error %v", len(tt.message), got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	// 最长消息的信封恰好是 u16 能表示的 65535 字节
+	envelope, err := Encode(ascii(MaxLen))
+	if err != nil || len(envelope) != 65535 {
+		t.Fatalf("envelope of %d bytes is %d bytes: %v", MaxLen, len(envelope), err)
+	}
+	if envelope[len(SigningDomain)+2] != 0xeb || envelope[len(SigningDomain)+3] != 0xff {
+		t.Fatalf("length field = % x, want eb ff", envelope[len(SigningDomain)+2:len(SigningDomain)+4])
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	signer, other := types.NewAccount(), types.NewAccount()
+	message := []byte("Test Message")
+	sig, err := Sign(signer, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope, _ := Encode(message)
+	// 签名的是信封而不是原始消息
+	if !bytes.Equal(sig, ed25519.Sign(signer.PrivateKey, envelope)) {
+		t.Fatal("signature is not over the envelope")
+	}
+	if VerifyRaw(signer.PublicKey, message, sig) {
+		t.Fatal("signature verifies over the bare message")
+	}
+
+	for _, tt := range []struct {
+		name    string
+		signer  types.Account
+		message []byte
+		sig     []byte
+		want    bool
+	}{
+		{"valid", signer, message, sig, true},
+		{"other key", other, message, sig, false},
+		{"other message", signer, []byte("Test message"), sig, false},
+		{"short signature", signer, message, sig[:63], false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := Verify(tt.signer.PublicKey, tt.message, tt.sig)
+			if err != nil || ok != tt.want {
+				t.Fatalf("Verify = %v, %v; want %v", ok, err, tt.want)
+			}
+		})
+	}
+	if _, err := Verify(signer.PublicKey, []byte(strings.Repeat("a", MaxLen+1)), sig); err == nil {
+		t.Fatal("Verify accepted an over-long message")
+	}
+}
@@ -0,0 +1,151 @@
+// Code generated by anchorgen from chain.json. DO NOT EDIT.
+
+// Package chain 是 Anchor 程序 chain 的类型化客户端。
+package chain
+
+import (
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/anchor"
+	"sdk/borsh"
+)
+
+// ProgramID 是程序 declare_id! 声明的地址
+var ProgramID = common.PublicKeyFromString("A41gXaRcvZDSFEf2vLg1wjxKwi3ybbT3f2yvd6ZhBYer")
+
+// BalanceEvent 对应程序中的同名结构体
+//
+// 用于在链上事件中输出余额信息，方便客户端解析。
+type BalanceEvent struct {
+	// 被查询的钱包地址
+	Wallet common.PublicKey `json:"wallet"`
+	// 余额（单位：lamports；1 SOL = 1_000_000_000 lamports）
+	Lamports uint64 `json:"lamports"`
+}
+
+// MarshalBorsh 按字段顺序写出 Borsh 编码
+func (v *BalanceEvent) MarshalBorsh(e *borsh.Encoder) {
+	e.WritePubkey(v.Wallet)
+	e.WriteU64(v.Lamports)
+}
+
+// UnmarshalBorsh 按字段顺序读取 Borsh 编码，错误记录在 d.Err() 中
+func (v *BalanceEvent) UnmarshalBorsh(d *borsh.Decoder) {
+	v.Wallet = d.ReadPubkey()
+	v.Lamports = d.ReadU64()
+}
+
+// 事件 discriminator：sha256("event:<Name>")[:8]
+var (
+	BalanceEventDiscriminator = anchor.Discriminator{225, 207, 79, 11, 104, 145, 221, 90}
+)
+
+// DecodeBalanceEvent 解码 "Program data:" 日志中的 BalanceEvent 事件（已 base64 解码）
+func DecodeBalanceEvent(data []byte) (*BalanceEvent, error) {
+	body, err := anchor.CheckDiscriminator(data, BalanceEventDiscriminator)
+	if err != nil {
+		return nil, fmt.Errorf("decode BalanceEvent: %w", err)
+	}
+	var v BalanceEvent
+	d := borsh.NewDecoder(body)
+	v.UnmarshalBorsh(d)
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("decode BalanceEvent: %w", err)
+	}
+	return &v, nil
+}
+
+// DecodeEvent 根据 discriminator 识别并解码本程序的任意事件，返回事件名
+func DecodeEvent(data []byte) (string, any, error) {
+	if len(data) < anchor.DiscriminatorSize {
+		return "", nil, fmt.Errorf("event data too short: %d bytes", len(data))
+	}
+	switch anchor.Discriminator(data[:anchor.DiscriminatorSize]) {
+	case BalanceEventDiscriminator:
+		v, err := DecodeBalanceEvent(data)
+		return "BalanceEvent", v, err
+	}
+	return "", nil, fmt.Errorf("unknown event discriminator %x", data[:anchor.DiscriminatorSize])
+}
+
+// 指令 discriminator：sha256("global:<name>")[:8]
+var (
+	CreateWalletInstructionDiscriminator = anchor.Discriminator{82, 172, 128, 18, 161, 207, 88, 63}
+	GetBalanceInstructionDiscriminator   = anchor.Discriminator{5, 173, 180, 151, 243, 81, 233, 55}
+)
+
+// CreateWalletArgs 是 create_wallet 指令的参数（按 IDL 顺序 Borsh 编码）
+type CreateWalletArgs struct {
+	Seed            string
+	InitialLamports uint64
+}
+
+// CreateWalletAccounts 是 create_wallet 指令的账户列表
+type CreateWalletAccounts struct {
+	// 出资者（签名者），为新账户支付租金与初始转账
+	Payer common.PublicKey // writable, signer
+	// 系统钱包账户（PDA）
+	Wallet common.PublicKey // writable, PDA
+	// 系统程序
+	SystemProgram common.PublicKey // 默认 11111111111111111111111111111111
+}
+
+// NewCreateWalletInstruction 构造 create_wallet 指令。
+//
+// 创建一个系统钱包账户（PDA），并可选择转入初始 SOL。
+func NewCreateWalletInstruction(accounts CreateWalletAccounts, args CreateWalletArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(CreateWalletInstructionDiscriminator[:])
+	e.WriteString(args.Seed)
+	e.WriteU64(args.InitialLamports)
+	if accounts.SystemProgram == (common.PublicKey{}) {
+		accounts.SystemProgram = common.PublicKeyFromString("11111111111111111111111111111111")
+	}
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.Payer, IsSigner: true, IsWritable: true},
+			{PubKey: accounts.Wallet, IsSigner: false, IsWritable: true},
+			{PubKey: accounts.SystemProgram, IsSigner: false, IsWritable: false},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// GetBalanceArgs 是 get_balance 指令的参数（按 IDL 顺序 Borsh 编码）
+type GetBalanceArgs struct {
+}
+
+// GetBalanceAccounts 是 get_balance 指令的账户列表
+type GetBalanceAccounts struct {
+	// 要查询余额的系统账户
+	Wallet common.PublicKey
+}
+
+// NewGetBalanceInstruction 构造 get_balance 指令。
+//
+// 查询任意系统钱包账户的 SOL 余额（以 lamports 计）。
+func NewGetBalanceInstruction(accounts GetBalanceAccounts, args GetBalanceArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(GetBalanceInstructionDiscriminator[:])
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.Wallet, IsSigner: false, IsWritable: false},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// FindWalletAddress 派生 wallet PDA：seeds = ["wallet", payer]
+func FindWalletAddress(payer common.PublicKey) (common.PublicKey, uint8, error) {
+	return common.FindProgramAddress([][]byte{[]byte("wallet"), payer.Bytes()}, ProgramID)
+}
+
+// PDATemplates 列出各 PDA 账户的 seed 模板，供 anchor.FindPDA 按地址反查
+var PDATemplates = []anchor.PDATemplate{
+	{Program: "chain", Account: "wallet", ProgramID: ProgramID, Seeds: []anchor.SeedSpec{{Kind: anchor.SeedConst, Value: []byte("wallet")}, {Kind: anchor.SeedPubkey, Name: "payer"}}},
+}
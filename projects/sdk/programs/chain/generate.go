@@ -0,0 +1,3 @@
+package chain
+
+//go:generate go run sdk/cmd/anchorgen -idl ../../idl/chain.json -pkg chain -out chain_gen.go
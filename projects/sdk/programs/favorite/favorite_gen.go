@@ -0,0 +1,423 @@
+// Code generated by anchorgen from favorite.json. DO NOT EDIT.
+
+// Package favorite 是 Anchor 程序 favorite 的类型化客户端。
+package favorite
+
+import (
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/anchor"
+	"sdk/borsh"
+)
+
+// ProgramID 是程序 declare_id! 声明的地址
+var ProgramID = common.PublicKeyFromString("AdUTQjW9iWgWwjsr7n5RjVLjt1VGNtBSviJQtk18ESxQ")
+
+// Favorite 对应程序中的同名结构体
+//
+// On-chain account that stores the user's preferences.
+type Favorite struct {
+	// Owner of the account. Kept first, at offset 8, so a memcmp filter
+	// finds every default and named favorites account of a user.
+	User common.PublicKey `json:"user"`
+	// Favorite number (64-bit unsigned integer).
+	Number uint64 `json:"number"`
+	// Favorite color. Bounded string with a maximum length of 10 characters.
+	Color string `json:"color"`
+	// Favorite hobbies. A vector with at most 10 strings,
+	// where each string is at most 50 characters.
+	Hobbies []string `json:"hobbies"`
+}
+
+// MarshalBorsh 按字段顺序写出 Borsh 编码
+func (v *Favorite) MarshalBorsh(e *borsh.Encoder) {
+	e.WritePubkey(v.User)
+	e.WriteU64(v.Number)
+	e.WriteString(v.Color)
+	e.WriteLen(len(v.Hobbies))
+	for _, v0 := range v.Hobbies {
+		e.WriteString(v0)
+	}
+}
+
+// UnmarshalBorsh 按字段顺序读取 Borsh 编码，错误记录在 d.Err() 中
+func (v *Favorite) UnmarshalBorsh(d *borsh.Decoder) {
+	v.User = d.ReadPubkey()
+	v.Number = d.ReadU64()
+	v.Color = d.ReadString()
+	v.Hobbies = make([]string, d.ReadLen(4))
+	for i0 := range v.Hobbies {
+		v.Hobbies[i0] = d.ReadString()
+	}
+}
+
+// FavoritesUpdated 对应程序中的同名结构体
+//
+// Emitted on every write to a favorites account. Replaying these events in
+// order reconstructs the history of a user's default favorites or profile.
+type FavoritesUpdated struct {
+	// Owner of the favorites account.
+	User common.PublicKey `json:"user"`
+	// Profile name; empty for the default favorites account.
+	Profile string   `json:"profile"`
+	Number  uint64   `json:"number"`
+	Color   string   `json:"color"`
+	Hobbies []string `json:"hobbies"`
+	// Cluster unix timestamp of the write.
+	UpdatedAt int64 `json:"updatedAt"`
+}
+
+// MarshalBorsh 按字段顺序写出 Borsh 编码
+func (v *FavoritesUpdated) MarshalBorsh(e *borsh.Encoder) {
+	e.WritePubkey(v.User)
+	e.WriteString(v.Profile)
+	e.WriteU64(v.Number)
+	e.WriteString(v.Color)
+	e.WriteLen(len(v.Hobbies))
+	for _, v0 := range v.Hobbies {
+		e.WriteString(v0)
+	}
+	e.WriteI64(v.UpdatedAt)
+}
+
+// UnmarshalBorsh 按字段顺序读取 Borsh 编码，错误记录在 d.Err() 中
+func (v *FavoritesUpdated) UnmarshalBorsh(d *borsh.Decoder) {
+	v.User = d.ReadPubkey()
+	v.Profile = d.ReadString()
+	v.Number = d.ReadU64()
+	v.Color = d.ReadString()
+	v.Hobbies = make([]string, d.ReadLen(4))
+	for i0 := range v.Hobbies {
+		v.Hobbies[i0] = d.ReadString()
+	}
+	v.UpdatedAt = d.ReadI64()
+}
+
+// 账户 discriminator：sha256("account:<Name>")[:8]
+var (
+	FavoriteDiscriminator = anchor.Discriminator{65, 171, 165, 33, 221, 211, 185, 49}
+)
+
+// DecodeFavorite 校验 discriminator 并解码 Favorite 账户数据
+func DecodeFavorite(data []byte) (*Favorite, error) {
+	body, err := anchor.CheckDiscriminator(data, FavoriteDiscriminator)
+	if err != nil {
+		return nil, fmt.Errorf("decode Favorite: %w", err)
+	}
+	var v Favorite
+	d := borsh.NewDecoder(body)
+	v.UnmarshalBorsh(d)
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("decode Favorite: %w", err)
+	}
+	return &v, nil
+}
+
+// DecodeAccount 根据 discriminator 识别并解码本程序的任意账户，返回账户类型名
+func DecodeAccount(data []byte) (string, any, error) {
+	if len(data) < anchor.DiscriminatorSize {
+		return "", nil, fmt.Errorf("account data too short: %d bytes", len(data))
+	}
+	switch anchor.Discriminator(data[:anchor.DiscriminatorSize]) {
+	case FavoriteDiscriminator:
+		v, err := DecodeFavorite(data)
+		return "Favorite", v, err
+	}
+	return "", nil, fmt.Errorf("unknown account discriminator %x", data[:anchor.DiscriminatorSize])
+}
+
+// 事件 discriminator：sha256("event:<Name>")[:8]
+var (
+	FavoritesUpdatedDiscriminator = anchor.Discriminator{128, 101, 253, 142, 184, 53, 38, 193}
+)
+
+// DecodeFavoritesUpdated 解码 "Program data:" 日志中的 FavoritesUpdated 事件（已 base64 解码）
+func DecodeFavoritesUpdated(data []byte) (*FavoritesUpdated, error) {
+	body, err := anchor.CheckDiscriminator(data, FavoritesUpdatedDiscriminator)
+	if err != nil {
+		return nil, fmt.Errorf("decode FavoritesUpdated: %w", err)
+	}
+	var v FavoritesUpdated
+	d := borsh.NewDecoder(body)
+	v.UnmarshalBorsh(d)
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("decode FavoritesUpdated: %w", err)
+	}
+	return &v, nil
+}
+
+// DecodeEvent 根据 discriminator 识别并解码本程序的任意事件，返回事件名
+func DecodeEvent(data []byte) (string, any, error) {
+	if len(data) < anchor.DiscriminatorSize {
+		return "", nil, fmt.Errorf("event data too short: %d bytes", len(data))
+	}
+	switch anchor.Discriminator(data[:anchor.DiscriminatorSize]) {
+	case FavoritesUpdatedDiscriminator:
+		v, err := DecodeFavoritesUpdated(data)
+		return "FavoritesUpdated", v, err
+	}
+	return "", nil, fmt.Errorf("unknown event discriminator %x", data[:anchor.DiscriminatorSize])
+}
+
+// 指令 discriminator：sha256("global:<name>")[:8]
+var (
+	InitializeInstructionDiscriminator   = anchor.Discriminator{175, 175, 109, 31, 13, 152, 155, 237}
+	UpdateInstructionDiscriminator       = anchor.Discriminator{219, 200, 88, 176, 158, 63, 253, 127}
+	CloseInstructionDiscriminator        = anchor.Discriminator{98, 165, 201, 177, 108, 65, 206, 96}
+	SetProfileInstructionDiscriminator   = anchor.Discriminator{221, 221, 195, 121, 133, 71, 113, 170}
+	CloseProfileInstructionDiscriminator = anchor.Discriminator{167, 36, 181, 8, 136, 158, 46, 207}
+)
+
+// InitializeArgs 是 initialize 指令的参数（按 IDL 顺序 Borsh 编码）
+type InitializeArgs struct {
+	Number  uint64
+	Color   string
+	Hobbies []string
+}
+
+// InitializeAccounts 是 initialize 指令的账户列表
+type InitializeAccounts struct {
+	User          common.PublicKey // writable, signer
+	Favorites     common.PublicKey // writable, PDA
+	SystemProgram common.PublicKey // 默认 11111111111111111111111111111111
+}
+
+// NewInitializeInstruction 构造 initialize 指令。
+//
+// Creates or updates the user's favorites account.
+func NewInitializeInstruction(accounts InitializeAccounts, args InitializeArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(InitializeInstructionDiscriminator[:])
+	e.WriteU64(args.Number)
+	e.WriteString(args.Color)
+	e.WriteLen(len(args.Hobbies))
+	for _, v0 := range args.Hobbies {
+		e.WriteString(v0)
+	}
+	if accounts.SystemProgram == (common.PublicKey{}) {
+		accounts.SystemProgram = common.PublicKeyFromString("11111111111111111111111111111111")
+	}
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.User, IsSigner: true, IsWritable: true},
+			{PubKey: accounts.Favorites, IsSigner: false, IsWritable: true},
+			{PubKey: accounts.SystemProgram, IsSigner: false, IsWritable: false},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// UpdateArgs 是 update 指令的参数（按 IDL 顺序 Borsh 编码）
+type UpdateArgs struct {
+	Number  uint64
+	Color   string
+	Hobbies []string
+}
+
+// UpdateAccounts 是 update 指令的账户列表
+type UpdateAccounts struct {
+	User          common.PublicKey // writable, signer
+	Favorites     common.PublicKey // writable, PDA
+	SystemProgram common.PublicKey // 默认 11111111111111111111111111111111
+}
+
+// NewUpdateInstruction 构造 update 指令。
+//
+// Overwrites the user's default favorites; the previous values remain
+// in the `FavoritesUpdated` event of the earlier write.
+func NewUpdateInstruction(accounts UpdateAccounts, args UpdateArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(UpdateInstructionDiscriminator[:])
+	e.WriteU64(args.Number)
+	e.WriteString(args.Color)
+	e.WriteLen(len(args.Hobbies))
+	for _, v0 := range args.Hobbies {
+		e.WriteString(v0)
+	}
+	if accounts.SystemProgram == (common.PublicKey{}) {
+		accounts.SystemProgram = common.PublicKeyFromString("11111111111111111111111111111111")
+	}
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.User, IsSigner: true, IsWritable: true},
+			{PubKey: accounts.Favorites, IsSigner: false, IsWritable: true},
+			{PubKey: accounts.SystemProgram, IsSigner: false, IsWritable: false},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// CloseArgs 是 close 指令的参数（按 IDL 顺序 Borsh 编码）
+type CloseArgs struct {
+}
+
+// CloseAccounts 是 close 指令的账户列表
+type CloseAccounts struct {
+	User      common.PublicKey // writable, signer
+	Favorites common.PublicKey // writable, PDA
+}
+
+// NewCloseInstruction 构造 close 指令。
+//
+// Closes the user's favorites account and returns its rent to the user.
+func NewCloseInstruction(accounts CloseAccounts, args CloseArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(CloseInstructionDiscriminator[:])
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.User, IsSigner: true, IsWritable: true},
+			{PubKey: accounts.Favorites, IsSigner: false, IsWritable: true},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// SetProfileArgs 是 set_profile 指令的参数（按 IDL 顺序 Borsh 编码）
+type SetProfileArgs struct {
+	Profile string
+	Number  uint64
+	Color   string
+	Hobbies []string
+}
+
+// SetProfileAccounts 是 set_profile 指令的账户列表
+type SetProfileAccounts struct {
+	User             common.PublicKey // writable, signer
+	ProfileFavorites common.PublicKey // writable, PDA
+	SystemProgram    common.PublicKey // 默认 11111111111111111111111111111111
+}
+
+// NewSetProfileInstruction 构造 set_profile 指令。
+//
+// Creates or updates one of the user's named profiles.
+func NewSetProfileInstruction(accounts SetProfileAccounts, args SetProfileArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(SetProfileInstructionDiscriminator[:])
+	e.WriteString(args.Profile)
+	e.WriteU64(args.Number)
+	e.WriteString(args.Color)
+	e.WriteLen(len(args.Hobbies))
+	for _, v0 := range args.Hobbies {
+		e.WriteString(v0)
+	}
+	if accounts.SystemProgram == (common.PublicKey{}) {
+		accounts.SystemProgram = common.PublicKeyFromString("11111111111111111111111111111111")
+	}
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.User, IsSigner: true, IsWritable: true},
+			{PubKey: accounts.ProfileFavorites, IsSigner: false, IsWritable: true},
+			{PubKey: accounts.SystemProgram, IsSigner: false, IsWritable: false},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// CloseProfileArgs 是 close_profile 指令的参数（按 IDL 顺序 Borsh 编码）
+type CloseProfileArgs struct {
+	Profile string
+}
+
+// CloseProfileAccounts 是 close_profile 指令的账户列表
+type CloseProfileAccounts struct {
+	User             common.PublicKey // writable, signer
+	ProfileFavorites common.PublicKey // writable, PDA
+}
+
+// NewCloseProfileInstruction 构造 close_profile 指令。
+//
+// Closes one of the user's named profiles and returns its rent to the user.
+// An empty name is rejected, so this never closes the default account.
+func NewCloseProfileInstruction(accounts CloseProfileAccounts, args CloseProfileArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(CloseProfileInstructionDiscriminator[:])
+	e.WriteString(args.Profile)
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.User, IsSigner: true, IsWritable: true},
+			{PubKey: accounts.ProfileFavorites, IsSigner: false, IsWritable: true},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// FindFavoritesAddress 派生 favorites PDA：seeds = ["favorites", user]
+func FindFavoritesAddress(user common.PublicKey) (common.PublicKey, uint8, error) {
+	return common.FindProgramAddress([][]byte{[]byte("favorites"), user.Bytes()}, ProgramID)
+}
+
+// FindProfileFavoritesAddress 派生 profile_favorites PDA：seeds = ["favorites", user, profile]
+func FindProfileFavoritesAddress(user common.PublicKey, profile string) (common.PublicKey, uint8, error) {
+	return common.FindProgramAddress([][]byte{[]byte("favorites"), user.Bytes(), []byte(profile)}, ProgramID)
+}
+
+// PDATemplates 列出各 PDA 账户的 seed 模板，供 anchor.FindPDA 按地址反查
+var PDATemplates = []anchor.PDATemplate{
+	{Program: "favorite", Account: "favorites", ProgramID: ProgramID, Seeds: []anchor.SeedSpec{{Kind: anchor.SeedConst, Value: []byte("favorites")}, {Kind: anchor.SeedPubkey, Name: "user"}}},
+	{Program: "favorite", Account: "profile_favorites", ProgramID: ProgramID, Seeds: []anchor.SeedSpec{{Kind: anchor.SeedConst, Value: []byte("favorites")}, {Kind: anchor.SeedPubkey, Name: "user"}, {Kind: anchor.SeedString, Name: "profile"}}},
+}
+
+// ErrorCode 是程序 #[error_code] 定义的自定义错误码（从 6000 起）
+type ErrorCode uint32
+
+const (
+	ErrEmptyProfile   ErrorCode = 6000
+	ErrProfileTooLong ErrorCode = 6001
+)
+
+// Name 返回错误码在 Rust 中的变体名
+func (c ErrorCode) Name() string {
+	switch c {
+	case ErrEmptyProfile:
+		return "EmptyProfile"
+	case ErrProfileTooLong:
+		return "ProfileTooLong"
+	}
+	return ""
+}
+
+// Message 返回 #[msg(...)] 中的错误描述
+func (c ErrorCode) Message() string {
+	switch c {
+	case ErrEmptyProfile:
+		return "Profile name must not be empty"
+	case ErrProfileTooLong:
+		return "Profile name is longer than 32 bytes"
+	}
+	return ""
+}
+
+// Code 返回数值错误码
+func (c ErrorCode) Code() uint32 {
+	return uint32(c)
+}
+
+// Program 返回定义该错误码的程序，配合 anchor.ProgramError 支持 errors.Is
+func (c ErrorCode) Program() common.PublicKey {
+	return ProgramID
+}
+
+func (c ErrorCode) Error() string {
+	if name := c.Name(); name != "" {
+		return fmt.Sprintf("%s (%d): %s", name, uint32(c), c.Message())
+	}
+	return fmt.Sprintf("unknown error code %d", uint32(c))
+}
+
+// Errors 是 IDL 中的错误表，init 时登记到 anchor.DecodeError 使用的注册表
+var Errors = []anchor.IDLError{
+	{Code: 6000, Name: "EmptyProfile", Msg: "Profile name must not be empty"},
+	{Code: 6001, Name: "ProfileTooLong", Msg: "Profile name is longer than 32 bytes"},
+}
+
+func init() {
+	anchor.RegisterErrors(ProgramID, Errors)
+}
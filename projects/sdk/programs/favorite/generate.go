@@ -0,0 +1,3 @@
+package favorite
+
+//go:generate go run sdk/cmd/anchorgen -idl ../../idl/favorite.json -pkg favorite -out favorite_gen.go
@@ -0,0 +1,9 @@
+package favorite
+
+import "sdk/anchor"
+
+// MaxLens 对应 favorite.rs 中 Favorite 的 #[max_len] 注解，用于计算账户大小
+var MaxLens = anchor.MaxLens{
+	"Favorite.color":   {10},
+	"Favorite.hobbies": {10, 50},
+}
@@ -0,0 +1,3 @@
+package voting
+
+//go:generate go run sdk/cmd/anchorgen -idl ../../idl/voting.json -pkg voting -out voting_gen.go
@@ -0,0 +1,12 @@
+package voting
+
+import "sdk/anchor"
+
+// MaxLens 对应 voting 程序 lib.rs 中的 #[max_len] 注解，用于计算账户大小
+var MaxLens = anchor.MaxLens{
+	"Poll.poll_name":                  {10},
+	"Poll.poll_desc":                  {100},
+	"Poll.winner":                     {10},
+	"CandidateAccount.candidate_name": {10},
+	"VoteReceipt.candidate":           {10},
+}
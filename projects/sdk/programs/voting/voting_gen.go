@@ -0,0 +1,719 @@
+// Code generated by anchorgen from voting.json. DO NOT EDIT.
+
+// Package voting 是 Anchor 程序 voting 的类型化客户端。
+package voting
+
+import (
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/anchor"
+	"sdk/borsh"
+)
+
+// ProgramID 是程序 declare_id! 声明的地址
+var ProgramID = common.PublicKeyFromString("31Tq6cGFa1CU8JaU51snTvKaXaKqWP3M3dFBWNXeJqYj")
+
+// CandidateAccount 对应程序中的同名结构体
+type CandidateAccount struct {
+	CandidateName  string `json:"candidateName"`
+	CandidateVotes uint64 `json:"candidateVotes"`
+}
+
+// MarshalBorsh 按字段顺序写出 Borsh 编码
+func (v *CandidateAccount) MarshalBorsh(e *borsh.Encoder) {
+	e.WriteString(v.CandidateName)
+	e.WriteU64(v.CandidateVotes)
+}
+
+// UnmarshalBorsh 按字段顺序读取 Borsh 编码，错误记录在 d.Err() 中
+func (v *CandidateAccount) UnmarshalBorsh(d *borsh.Decoder) {
+	v.CandidateName = d.ReadString()
+	v.CandidateVotes = d.ReadU64()
+}
+
+// Poll 对应程序中的同名结构体
+type Poll struct {
+	PollName      string `json:"pollName"`
+	PollDesc      string `json:"pollDesc"`
+	PollVoteStart uint64 `json:"pollVoteStart"`
+	PollVoteEnd   uint64 `json:"pollVoteEnd"`
+	PollVoteIndex uint64 `json:"pollVoteIndex"`
+	// Signer of initialize_poll; the only key allowed to update or close the poll.
+	Authority common.PublicKey `json:"authority"`
+	// Set by finalize_results once voting has ended.
+	Finalized bool `json:"finalized"`
+	// Winning candidate name, empty until finalized (or if the poll had no candidates).
+	Winner      string `json:"winner"`
+	WinnerVotes uint64 `json:"winnerVotes"`
+	// Who may vote and how votes are weighted.
+	Mode VotingMode `json:"mode"`
+	// Open vote receipts; close_poll requires close_receipts to bring it to zero.
+	ReceiptCount uint64 `json:"receiptCount"`
+}
+
+// MarshalBorsh 按字段顺序写出 Borsh 编码
+func (v *Poll) MarshalBorsh(e *borsh.Encoder) {
+	e.WriteString(v.PollName)
+	e.WriteString(v.PollDesc)
+	e.WriteU64(v.PollVoteStart)
+	e.WriteU64(v.PollVoteEnd)
+	e.WriteU64(v.PollVoteIndex)
+	e.WritePubkey(v.Authority)
+	e.WriteBool(v.Finalized)
+	e.WriteString(v.Winner)
+	e.WriteU64(v.WinnerVotes)
+	v.Mode.MarshalBorsh(e)
+	e.WriteU64(v.ReceiptCount)
+}
+
+// UnmarshalBorsh 按字段顺序读取 Borsh 编码，错误记录在 d.Err() 中
+func (v *Poll) UnmarshalBorsh(d *borsh.Decoder) {
+	v.PollName = d.ReadString()
+	v.PollDesc = d.ReadString()
+	v.PollVoteStart = d.ReadU64()
+	v.PollVoteEnd = d.ReadU64()
+	v.PollVoteIndex = d.ReadU64()
+	v.Authority = d.ReadPubkey()
+	v.Finalized = d.ReadBool()
+	v.Winner = d.ReadString()
+	v.WinnerVotes = d.ReadU64()
+	v.Mode.UnmarshalBorsh(d)
+	v.ReceiptCount = d.ReadU64()
+}
+
+// VoteReceipt 对应程序中的同名结构体
+//
+// Proof that `voter` voted in a poll, at PDA `[poll_id_le, voter]`.
+type VoteReceipt struct {
+	Voter     common.PublicKey `json:"voter"`
+	Candidate string           `json:"candidate"`
+	VotedAt   int64            `json:"votedAt"`
+	// Votes added to the candidate: 1, or the token balance in token-weighted polls.
+	Weight uint64 `json:"weight"`
+}
+
+// MarshalBorsh 按字段顺序写出 Borsh 编码
+func (v *VoteReceipt) MarshalBorsh(e *borsh.Encoder) {
+	e.WritePubkey(v.Voter)
+	e.WriteString(v.Candidate)
+	e.WriteI64(v.VotedAt)
+	e.WriteU64(v.Weight)
+}
+
+// UnmarshalBorsh 按字段顺序读取 Borsh 编码，错误记录在 d.Err() 中
+func (v *VoteReceipt) UnmarshalBorsh(d *borsh.Decoder) {
+	v.Voter = d.ReadPubkey()
+	v.Candidate = d.ReadString()
+	v.VotedAt = d.ReadI64()
+	v.Weight = d.ReadU64()
+}
+
+// VotingModeKind 是 VotingMode 的变体序号
+type VotingModeKind uint8
+
+const (
+	VotingModeKindOneWalletOneVote VotingModeKind = iota
+	VotingModeKindTokenWeighted
+	VotingModeKindMerkleAllowlist
+)
+
+func (k VotingModeKind) String() string {
+	switch k {
+	case VotingModeKindOneWalletOneVote:
+		return "OneWalletOneVote"
+	case VotingModeKindTokenWeighted:
+		return "TokenWeighted"
+	case VotingModeKindMerkleAllowlist:
+		return "MerkleAllowlist"
+	}
+	return fmt.Sprintf("VotingModeKind(%d)", uint8(k))
+}
+
+// VotingModeTokenWeighted 对应程序中的同名结构体
+//
+// VotingMode::TokenWeighted 变体的字段
+type VotingModeTokenWeighted struct {
+	Mint common.PublicKey `json:"mint"`
+}
+
+// MarshalBorsh 按字段顺序写出 Borsh 编码
+func (v *VotingModeTokenWeighted) MarshalBorsh(e *borsh.Encoder) {
+	e.WritePubkey(v.Mint)
+}
+
+// UnmarshalBorsh 按字段顺序读取 Borsh 编码，错误记录在 d.Err() 中
+func (v *VotingModeTokenWeighted) UnmarshalBorsh(d *borsh.Decoder) {
+	v.Mint = d.ReadPubkey()
+}
+
+// VotingModeMerkleAllowlist 对应程序中的同名结构体
+//
+// VotingMode::MerkleAllowlist 变体的字段
+type VotingModeMerkleAllowlist struct {
+	Root [32]uint8 `json:"root"`
+}
+
+// MarshalBorsh 按字段顺序写出 Borsh 编码
+func (v *VotingModeMerkleAllowlist) MarshalBorsh(e *borsh.Encoder) {
+	e.WriteRaw(v.Root[:])
+}
+
+// UnmarshalBorsh 按字段顺序读取 Borsh 编码，错误记录在 d.Err() 中
+func (v *VotingModeMerkleAllowlist) UnmarshalBorsh(d *borsh.Decoder) {
+	copy(v.Root[:], d.ReadRaw(32))
+}
+
+// VotingMode 对应程序中的同名枚举（Borsh 编码为 u8 变体序号 + 变体字段）
+//
+// How votes in a poll are counted and who may cast them.
+type VotingMode struct {
+	Kind            VotingModeKind             `json:"kind"`
+	TokenWeighted   *VotingModeTokenWeighted   `json:"tokenWeighted,omitempty"`
+	MerkleAllowlist *VotingModeMerkleAllowlist `json:"merkleAllowlist,omitempty"`
+}
+
+func (v *VotingMode) MarshalBorsh(e *borsh.Encoder) {
+	e.WriteU8(uint8(v.Kind))
+	switch v.Kind {
+	case VotingModeKindTokenWeighted:
+		f := v.TokenWeighted
+		if f == nil {
+			f = &VotingModeTokenWeighted{}
+		}
+		f.MarshalBorsh(e)
+	case VotingModeKindMerkleAllowlist:
+		f := v.MerkleAllowlist
+		if f == nil {
+			f = &VotingModeMerkleAllowlist{}
+		}
+		f.MarshalBorsh(e)
+	}
+}
+
+func (v *VotingMode) UnmarshalBorsh(d *borsh.Decoder) {
+	*v = VotingMode{Kind: VotingModeKind(d.ReadU8())}
+	switch v.Kind {
+	case VotingModeKindOneWalletOneVote:
+	case VotingModeKindTokenWeighted:
+		v.TokenWeighted = new(VotingModeTokenWeighted)
+		v.TokenWeighted.UnmarshalBorsh(d)
+	case VotingModeKindMerkleAllowlist:
+		v.MerkleAllowlist = new(VotingModeMerkleAllowlist)
+		v.MerkleAllowlist.UnmarshalBorsh(d)
+	default:
+		d.SetErr(fmt.Errorf("invalid VotingMode variant %d", uint8(v.Kind)))
+	}
+}
+
+// 账户 discriminator：sha256("account:<Name>")[:8]
+var (
+	CandidateAccountDiscriminator = anchor.Discriminator{69, 203, 73, 43, 203, 170, 96, 121}
+	PollDiscriminator             = anchor.Discriminator{110, 234, 167, 188, 231, 136, 153, 111}
+	VoteReceiptDiscriminator      = anchor.Discriminator{104, 20, 204, 252, 45, 84, 37, 195}
+)
+
+// DecodeCandidateAccount 校验 discriminator 并解码 CandidateAccount 账户数据
+func DecodeCandidateAccount(data []byte) (*CandidateAccount, error) {
+	body, err := anchor.CheckDiscriminator(data, CandidateAccountDiscriminator)
+	if err != nil {
+		return nil, fmt.Errorf("decode CandidateAccount: %w", err)
+	}
+	var v CandidateAccount
+	d := borsh.NewDecoder(body)
+	v.UnmarshalBorsh(d)
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("decode CandidateAccount: %w", err)
+	}
+	return &v, nil
+}
+
+// DecodePoll 校验 discriminator 并解码 Poll 账户数据
+func DecodePoll(data []byte) (*Poll, error) {
+	body, err := anchor.CheckDiscriminator(data, PollDiscriminator)
+	if err != nil {
+		return nil, fmt.Errorf("decode Poll: %w", err)
+	}
+	var v Poll
+	d := borsh.NewDecoder(body)
+	v.UnmarshalBorsh(d)
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("decode Poll: %w", err)
+	}
+	return &v, nil
+}
+
+// DecodeVoteReceipt 校验 discriminator 并解码 VoteReceipt 账户数据
+func DecodeVoteReceipt(data []byte) (*VoteReceipt, error) {
+	body, err := anchor.CheckDiscriminator(data, VoteReceiptDiscriminator)
+	if err != nil {
+		return nil, fmt.Errorf("decode VoteReceipt: %w", err)
+	}
+	var v VoteReceipt
+	d := borsh.NewDecoder(body)
+	v.UnmarshalBorsh(d)
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("decode VoteReceipt: %w", err)
+	}
+	return &v, nil
+}
+
+// DecodeAccount 根据 discriminator 识别并解码本程序的任意账户，返回账户类型名
+func DecodeAccount(data []byte) (string, any, error) {
+	if len(data) < anchor.DiscriminatorSize {
+		return "", nil, fmt.Errorf("account data too short: %d bytes", len(data))
+	}
+	switch anchor.Discriminator(data[:anchor.DiscriminatorSize]) {
+	case CandidateAccountDiscriminator:
+		v, err := DecodeCandidateAccount(data)
+		return "CandidateAccount", v, err
+	case PollDiscriminator:
+		v, err := DecodePoll(data)
+		return "Poll", v, err
+	case VoteReceiptDiscriminator:
+		v, err := DecodeVoteReceipt(data)
+		return "VoteReceipt", v, err
+	}
+	return "", nil, fmt.Errorf("unknown account discriminator %x", data[:anchor.DiscriminatorSize])
+}
+
+// 指令 discriminator：sha256("global:<name>")[:8]
+var (
+	ClosePollInstructionDiscriminator           = anchor.Discriminator{139, 213, 162, 65, 172, 150, 123, 67}
+	CloseReceiptsInstructionDiscriminator       = anchor.Discriminator{144, 230, 36, 140, 163, 61, 224, 177}
+	FinalizeResultsInstructionDiscriminator     = anchor.Discriminator{207, 226, 108, 46, 149, 229, 100, 127}
+	InitializeCandidateInstructionDiscriminator = anchor.Discriminator{210, 107, 118, 204, 255, 97, 112, 26}
+	InitializePollInstructionDiscriminator      = anchor.Discriminator{193, 22, 99, 197, 18, 33, 115, 117}
+	UpdatePollWindowInstructionDiscriminator    = anchor.Discriminator{26, 25, 98, 231, 103, 61, 227, 185}
+	VoteInstructionDiscriminator                = anchor.Discriminator{227, 110, 155, 23, 136, 126, 172, 25}
+)
+
+// ClosePollArgs 是 close_poll 指令的参数（按 IDL 顺序 Borsh 编码）
+type ClosePollArgs struct {
+	PollID uint64
+}
+
+// ClosePollAccounts 是 close_poll 指令的账户列表
+type ClosePollAccounts struct {
+	Authority   common.PublicKey // writable, signer
+	PollAccount common.PublicKey // writable, PDA
+}
+
+// NewClosePollInstruction 构造 close_poll 指令。
+//
+// Closes the poll and all of its candidate accounts, which must be passed
+// in `remaining_accounts`, returning their rent to the authority. Not
+// allowed while voting is open, or before `close_receipts` has closed every
+// vote receipt. Receipts or candidates left behind would carry over into a
+// new poll created with the same id.
+func NewClosePollInstruction(accounts ClosePollAccounts, args ClosePollArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(ClosePollInstructionDiscriminator[:])
+	e.WriteU64(args.PollID)
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.Authority, IsSigner: true, IsWritable: true},
+			{PubKey: accounts.PollAccount, IsSigner: false, IsWritable: true},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// CloseReceiptsArgs 是 close_receipts 指令的参数（按 IDL 顺序 Borsh 编码）
+type CloseReceiptsArgs struct {
+	PollID uint64
+}
+
+// CloseReceiptsAccounts 是 close_receipts 指令的账户列表
+type CloseReceiptsAccounts struct {
+	Authority   common.PublicKey // signer
+	PollAccount common.PublicKey // writable, PDA
+}
+
+// NewCloseReceiptsInstruction 构造 close_receipts 指令。
+//
+// Closes vote receipts, returning each receipt's rent to the voter who paid
+// it. `remaining_accounts` holds `[receipt, voter]` pairs; large polls call
+// it in batches before `close_poll`. Not allowed while voting is open.
+func NewCloseReceiptsInstruction(accounts CloseReceiptsAccounts, args CloseReceiptsArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(CloseReceiptsInstructionDiscriminator[:])
+	e.WriteU64(args.PollID)
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.Authority, IsSigner: true, IsWritable: false},
+			{PubKey: accounts.PollAccount, IsSigner: false, IsWritable: true},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// FinalizeResultsArgs 是 finalize_results 指令的参数（按 IDL 顺序 Borsh 编码）
+type FinalizeResultsArgs struct {
+	PollID uint64
+}
+
+// FinalizeResultsAccounts 是 finalize_results 指令的账户列表
+type FinalizeResultsAccounts struct {
+	Signer      common.PublicKey // signer
+	PollAccount common.PublicKey // writable, PDA
+}
+
+// NewFinalizeResultsInstruction 构造 finalize_results 指令。
+//
+// Records the winning candidate once voting has ended. Every candidate of
+// the poll must be passed in `remaining_accounts`; ties go to the
+// lexicographically smaller name.
+func NewFinalizeResultsInstruction(accounts FinalizeResultsAccounts, args FinalizeResultsArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(FinalizeResultsInstructionDiscriminator[:])
+	e.WriteU64(args.PollID)
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.Signer, IsSigner: true, IsWritable: false},
+			{PubKey: accounts.PollAccount, IsSigner: false, IsWritable: true},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// InitializeCandidateArgs 是 initialize_candidate 指令的参数（按 IDL 顺序 Borsh 编码）
+type InitializeCandidateArgs struct {
+	PollID    uint64
+	Candidate string
+}
+
+// InitializeCandidateAccounts 是 initialize_candidate 指令的账户列表
+type InitializeCandidateAccounts struct {
+	Authority        common.PublicKey // writable, signer
+	PollAccount      common.PublicKey // writable, PDA
+	CandidateAccount common.PublicKey // writable, PDA
+	SystemProgram    common.PublicKey // 默认 11111111111111111111111111111111
+}
+
+// NewInitializeCandidateInstruction 构造 initialize_candidate 指令。
+//
+// Adds a candidate. Only the poll authority can call it, and only before
+// voting starts.
+func NewInitializeCandidateInstruction(accounts InitializeCandidateAccounts, args InitializeCandidateArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(InitializeCandidateInstructionDiscriminator[:])
+	e.WriteU64(args.PollID)
+	e.WriteString(args.Candidate)
+	if accounts.SystemProgram == (common.PublicKey{}) {
+		accounts.SystemProgram = common.PublicKeyFromString("11111111111111111111111111111111")
+	}
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.Authority, IsSigner: true, IsWritable: true},
+			{PubKey: accounts.PollAccount, IsSigner: false, IsWritable: true},
+			{PubKey: accounts.CandidateAccount, IsSigner: false, IsWritable: true},
+			{PubKey: accounts.SystemProgram, IsSigner: false, IsWritable: false},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// InitializePollArgs 是 initialize_poll 指令的参数（按 IDL 顺序 Borsh 编码）
+type InitializePollArgs struct {
+	PollID uint64
+	Start  uint64
+	End    uint64
+	Name   string
+	Desc   string
+	Mode   VotingMode
+}
+
+// InitializePollAccounts 是 initialize_poll 指令的账户列表
+type InitializePollAccounts struct {
+	Signer common.PublicKey // writable, signer
+	// `init` fails if the poll already exists, so a live or finalized poll
+	// cannot be reset; a closed poll's id can be used again.
+	PollAccount   common.PublicKey // writable, PDA
+	SystemProgram common.PublicKey // 默认 11111111111111111111111111111111
+}
+
+// NewInitializePollInstruction 构造 initialize_poll 指令
+func NewInitializePollInstruction(accounts InitializePollAccounts, args InitializePollArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(InitializePollInstructionDiscriminator[:])
+	e.WriteU64(args.PollID)
+	e.WriteU64(args.Start)
+	e.WriteU64(args.End)
+	e.WriteString(args.Name)
+	e.WriteString(args.Desc)
+	args.Mode.MarshalBorsh(e)
+	if accounts.SystemProgram == (common.PublicKey{}) {
+		accounts.SystemProgram = common.PublicKeyFromString("11111111111111111111111111111111")
+	}
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.Signer, IsSigner: true, IsWritable: true},
+			{PubKey: accounts.PollAccount, IsSigner: false, IsWritable: true},
+			{PubKey: accounts.SystemProgram, IsSigner: false, IsWritable: false},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// UpdatePollWindowArgs 是 update_poll_window 指令的参数（按 IDL 顺序 Borsh 编码）
+type UpdatePollWindowArgs struct {
+	PollID uint64
+	Start  uint64
+	End    uint64
+}
+
+// UpdatePollWindowAccounts 是 update_poll_window 指令的账户列表
+type UpdatePollWindowAccounts struct {
+	Authority   common.PublicKey // signer
+	PollAccount common.PublicKey // writable, PDA
+}
+
+// NewUpdatePollWindowInstruction 构造 update_poll_window 指令。
+//
+// Moves the voting window. Only the poll authority can call it, and only
+// before voting ends; once voting has started the start time is fixed.
+func NewUpdatePollWindowInstruction(accounts UpdatePollWindowAccounts, args UpdatePollWindowArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(UpdatePollWindowInstructionDiscriminator[:])
+	e.WriteU64(args.PollID)
+	e.WriteU64(args.Start)
+	e.WriteU64(args.End)
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.Authority, IsSigner: true, IsWritable: false},
+			{PubKey: accounts.PollAccount, IsSigner: false, IsWritable: true},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// VoteArgs 是 vote 指令的参数（按 IDL 顺序 Borsh 编码）
+type VoteArgs struct {
+	PollID    uint64
+	Candidate string
+	Proof     [][32]uint8
+}
+
+// VoteAccounts 是 vote 指令的账户列表
+type VoteAccounts struct {
+	Signer           common.PublicKey // writable, signer
+	PollAccount      common.PublicKey // writable, PDA
+	CandidateAccount common.PublicKey // writable, PDA
+	// One receipt per voter and poll, paid for by the voter.
+	Receipt       common.PublicKey // writable, PDA
+	SystemProgram common.PublicKey // 默认 11111111111111111111111111111111
+	// The signer's token account for the poll mint; only used by token-weighted polls.
+	VoterTokenAccount common.PublicKey // optional
+}
+
+// NewVoteInstruction 构造 vote 指令。
+//
+// Casts the signer's vote. The poll's `mode` decides its weight: one per
+// wallet, the signer's balance of the poll mint (`voter_token_account`),
+// or one per wallet proven to be in the allowlist by `proof`.
+func NewVoteInstruction(accounts VoteAccounts, args VoteArgs) types.Instruction {
+	e := borsh.NewEncoder()
+	e.WriteRaw(VoteInstructionDiscriminator[:])
+	e.WriteU64(args.PollID)
+	e.WriteString(args.Candidate)
+	e.WriteLen(len(args.Proof))
+	for _, v0 := range args.Proof {
+		e.WriteRaw(v0[:])
+	}
+	if accounts.SystemProgram == (common.PublicKey{}) {
+		accounts.SystemProgram = common.PublicKeyFromString("11111111111111111111111111111111")
+	}
+	if accounts.VoterTokenAccount == (common.PublicKey{}) {
+		accounts.VoterTokenAccount = ProgramID
+	}
+	return types.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: accounts.Signer, IsSigner: true, IsWritable: true},
+			{PubKey: accounts.PollAccount, IsSigner: false, IsWritable: true},
+			{PubKey: accounts.CandidateAccount, IsSigner: false, IsWritable: true},
+			{PubKey: accounts.Receipt, IsSigner: false, IsWritable: true},
+			{PubKey: accounts.SystemProgram, IsSigner: false, IsWritable: false},
+			{PubKey: accounts.VoterTokenAccount, IsSigner: false, IsWritable: false},
+		},
+		Data: e.Bytes(),
+	}
+}
+
+// FindPollAccountAddress 派生 poll_account PDA：seeds = ["poll", poll_id]
+func FindPollAccountAddress(pollID uint64) (common.PublicKey, uint8, error) {
+	return common.FindProgramAddress([][]byte{[]byte("poll"), anchor.U64Seed(pollID)}, ProgramID)
+}
+
+// FindCandidateAccountAddress 派生 candidate_account PDA：seeds = [poll_id, candidate]
+func FindCandidateAccountAddress(pollID uint64, candidate string) (common.PublicKey, uint8, error) {
+	return common.FindProgramAddress([][]byte{anchor.U64Seed(pollID), []byte(candidate)}, ProgramID)
+}
+
+// FindReceiptAddress 派生 receipt PDA：seeds = [poll_id, signer]
+func FindReceiptAddress(pollID uint64, signer common.PublicKey) (common.PublicKey, uint8, error) {
+	return common.FindProgramAddress([][]byte{anchor.U64Seed(pollID), signer.Bytes()}, ProgramID)
+}
+
+// PDATemplates 列出各 PDA 账户的 seed 模板，供 anchor.FindPDA 按地址反查
+var PDATemplates = []anchor.PDATemplate{
+	{Program: "voting", Account: "poll_account", ProgramID: ProgramID, Seeds: []anchor.SeedSpec{{Kind: anchor.SeedConst, Value: []byte("poll")}, {Kind: anchor.SeedU64, Name: "poll_id"}}},
+	{Program: "voting", Account: "candidate_account", ProgramID: ProgramID, Seeds: []anchor.SeedSpec{{Kind: anchor.SeedU64, Name: "poll_id"}, {Kind: anchor.SeedString, Name: "candidate"}}},
+	{Program: "voting", Account: "receipt", ProgramID: ProgramID, Seeds: []anchor.SeedSpec{{Kind: anchor.SeedU64, Name: "poll_id"}, {Kind: anchor.SeedPubkey, Name: "signer"}}},
+}
+
+// ErrorCode 是程序 #[error_code] 定义的自定义错误码（从 6000 起）
+type ErrorCode uint32
+
+const (
+	ErrVotingNotStarted    ErrorCode = 6000
+	ErrVotingEnded         ErrorCode = 6001
+	ErrVotingNotEnded      ErrorCode = 6002
+	ErrVotingStarted       ErrorCode = 6003
+	ErrVotingActive        ErrorCode = 6004
+	ErrInvalidPollWindow   ErrorCode = 6005
+	ErrUnauthorized        ErrorCode = 6006
+	ErrPollFinalized       ErrorCode = 6007
+	ErrCandidateMismatch   ErrorCode = 6008
+	ErrAlreadyVoted        ErrorCode = 6009
+	ErrMissingTokenAccount ErrorCode = 6010
+	ErrInvalidTokenAccount ErrorCode = 6011
+	ErrNoVotingPower       ErrorCode = 6012
+	ErrNotAllowlisted      ErrorCode = 6013
+	ErrVoteOverflow        ErrorCode = 6014
+	ErrReceiptMismatch     ErrorCode = 6015
+	ErrReceiptsOpen        ErrorCode = 6016
+)
+
+// Name 返回错误码在 Rust 中的变体名
+func (c ErrorCode) Name() string {
+	switch c {
+	case ErrVotingNotStarted:
+		return "VotingNotStarted"
+	case ErrVotingEnded:
+		return "VotingEnded"
+	case ErrVotingNotEnded:
+		return "VotingNotEnded"
+	case ErrVotingStarted:
+		return "VotingStarted"
+	case ErrVotingActive:
+		return "VotingActive"
+	case ErrInvalidPollWindow:
+		return "InvalidPollWindow"
+	case ErrUnauthorized:
+		return "Unauthorized"
+	case ErrPollFinalized:
+		return "PollFinalized"
+	case ErrCandidateMismatch:
+		return "CandidateMismatch"
+	case ErrAlreadyVoted:
+		return "AlreadyVoted"
+	case ErrMissingTokenAccount:
+		return "MissingTokenAccount"
+	case ErrInvalidTokenAccount:
+		return "InvalidTokenAccount"
+	case ErrNoVotingPower:
+		return "NoVotingPower"
+	case ErrNotAllowlisted:
+		return "NotAllowlisted"
+	case ErrVoteOverflow:
+		return "VoteOverflow"
+	case ErrReceiptMismatch:
+		return "ReceiptMismatch"
+	case ErrReceiptsOpen:
+		return "ReceiptsOpen"
+	}
+	return ""
+}
+
+// Message 返回 #[msg(...)] 中的错误描述
+func (c ErrorCode) Message() string {
+	switch c {
+	case ErrVotingNotStarted:
+		return "Voting has not started yet"
+	case ErrVotingEnded:
+		return "Voting has ended"
+	case ErrVotingNotEnded:
+		return "Voting has not ended yet"
+	case ErrVotingStarted:
+		return "Voting has already started"
+	case ErrVotingActive:
+		return "Voting is in progress"
+	case ErrInvalidPollWindow:
+		return "Poll start must be before its end, and the end in the future"
+	case ErrUnauthorized:
+		return "Signer is not the poll authority"
+	case ErrPollFinalized:
+		return "Poll results are already finalized"
+	case ErrCandidateMismatch:
+		return "Candidate accounts do not match the poll"
+	case ErrAlreadyVoted:
+		return "This wallet has already voted in this poll"
+	case ErrMissingTokenAccount:
+		return "Token-weighted polls need the voter's token account"
+	case ErrInvalidTokenAccount:
+		return "Token account is not the voter's account for the poll mint"
+	case ErrNoVotingPower:
+		return "Voter holds no tokens of the poll mint"
+	case ErrNotAllowlisted:
+		return "Voter is not on the poll allowlist"
+	case ErrVoteOverflow:
+		return "Candidate vote count overflowed"
+	case ErrReceiptMismatch:
+		return "Vote receipt accounts do not match the poll"
+	case ErrReceiptsOpen:
+		return "Close the poll's vote receipts first"
+	}
+	return ""
+}
+
+// Code 返回数值错误码
+func (c ErrorCode) Code() uint32 {
+	return uint32(c)
+}
+
+// Program 返回定义该错误码的程序，配合 anchor.ProgramError 支持 errors.Is
+func (c ErrorCode) Program() common.PublicKey {
+	return ProgramID
+}
+
+func (c ErrorCode) Error() string {
+	if name := c.Name(); name != "" {
+		return fmt.Sprintf("%s (%d): %s", name, uint32(c), c.Message())
+	}
+	return fmt.Sprintf("unknown error code %d", uint32(c))
+}
+
+// Errors 是 IDL 中的错误表，init 时登记到 anchor.DecodeError 使用的注册表
+var Errors = []anchor.IDLError{
+	{Code: 6000, Name: "VotingNotStarted", Msg: "Voting has not started yet"},
+	{Code: 6001, Name: "VotingEnded", Msg: "Voting has ended"},
+	{Code: 6002, Name: "VotingNotEnded", Msg: "Voting has not ended yet"},
+	{Code: 6003, Name: "VotingStarted", Msg: "Voting has already started"},
+	{Code: 6004, Name: "VotingActive", Msg: "Voting is in progress"},
+	{Code: 6005, Name: "InvalidPollWindow", Msg: "Poll start must be before its end, and the end in the future"},
+	{Code: 6006, Name: "Unauthorized", Msg: "Signer is not the poll authority"},
+	{Code: 6007, Name: "PollFinalized", Msg: "Poll results are already finalized"},
+	{Code: 6008, Name: "CandidateMismatch", Msg: "Candidate accounts do not match the poll"},
+	{Code: 6009, Name: "AlreadyVoted", Msg: "This wallet has already voted in this poll"},
+	{Code: 6010, Name: "MissingTokenAccount", Msg: "Token-weighted polls need the voter's token account"},
+	{Code: 6011, Name: "InvalidTokenAccount", Msg: "Token account is not the voter's account for the poll mint"},
+	{Code: 6012, Name: "NoVotingPower", Msg: "Voter holds no tokens of the poll mint"},
+	{Code: 6013, Name: "NotAllowlisted", Msg: "Voter is not on the poll allowlist"},
+	{Code: 6014, Name: "VoteOverflow", Msg: "Candidate vote count overflowed"},
+	{Code: 6015, Name: "ReceiptMismatch", Msg: "Vote receipt accounts do not match the poll"},
+	{Code: 6016, Name: "ReceiptsOpen", Msg: "Close the poll's vote receipts first"},
+}
+
+func init() {
+	anchor.RegisterErrors(ProgramID, Errors)
+}
@@ -0,0 +1,314 @@
+package txbuilder
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/sysprog"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/anchor"
+)
+
+// ErrBlockhashExpired 表示交易在 blockhash 过期前仍未上链，可以安全地重新构造并发送
+var ErrBlockhashExpired = errors.New("txbuilder: blockhash expired before the transaction was confirmed")
+
+// Blockhash 是交易使用的 recent blockhash
+type Blockhash struct {
+	Hash string
+	// LastValidBlockHeight 超过后交易不会再被处理；0 表示不过期（durable nonce）
+	LastValidBlockHeight uint64
+	// Prepend 需放在交易最前面的指令（durable nonce 的 AdvanceNonceAccount）
+	Prepend []types.Instruction
+}
+
+// BlockhashSource 决定交易的 blockhash 来源
+type BlockhashSource func(ctx context.Context, c *client.Client) (Blockhash, error)
+
+// LatestBlockhash 每次构造时从 RPC 获取最新 blockhash（默认来源）
+func LatestBlockhash(commitment rpc.Commitment) BlockhashSource {
+	return func(ctx context.Context, c *client.Client) (Blockhash, error) {
+		latest, err := c.GetLatestBlockhashWithConfig(ctx, client.GetLatestBlockhashConfig{Commitment: commitment})
+		if err != nil {
+			return Blockhash{}, fmt.Errorf("failed to get latest blockhash: %w", err)
+		}
+		return Blockhash{Hash: latest.Blockhash, LastValidBlockHeight: latest.LatestValidBlockHeight}, nil
+	}
+}
+
+// FixedBlockhash 使用调用方给定的 blockhash，例如离线签名
+func FixedBlockhash(hash string) BlockhashSource {
+	return func(context.Context, *client.Client) (Blockhash, error) {
+		return Blockhash{Hash: hash}, nil
+	}
+}
+
+// DurableNonce 使用 nonce 账户中保存的 nonce 作为 blockhash，并在交易开头推进 nonce；
+// authority 必须是交易签名者
+func DurableNonce(nonceAccount, authority common.PublicKey) BlockhashSource {
+	return func(ctx context.Context, c *client.Client) (Blockhash, error) {
+		nonce, err := c.GetNonceFromNonceAccount(ctx, nonceAccount.ToBase58())
+		if err != nil {
+			return Blockhash{}, fmt.Errorf("failed to get nonce from %s: %w", nonceAccount.ToBase58(), err)
+		}
+		return Blockhash{
+			Hash: nonce,
+			Prepend: []types.Instruction{sysprog.AdvanceNonceAccount(sysprog.AdvanceNonceAccountParam{
+				Nonce: nonceAccount,
+				Auth:  authority,
+			})},
+		}, nil
+	}
+}
+
+// Builder 以链式调用组装交易：指令、手续费支付者、额外签名者、计算预算、
+// blockhash 来源与地址查找表，并负责模拟、发送和确认。
+// 程序错误统一经 anchor.DecodeError 解码为 *anchor.ProgramError。
+//
+//	sig, err := txbuilder.New(c).FeePayer(payer).Add(ix).SendAndConfirm(ctx)
+type Builder struct {
+	c          *client.Client
+	feePayer   *types.Account
+	signers    []types.Account
+	ixs        []types.Instruction
+	tables     []types.AddressLookupTableAccount
+	unitLimit  uint32
+	unitPrice  uint64
+	source     BlockhashSource
+	commitment rpc.Commitment
+	interval   time.Duration
+
+	// 最近一次 Build 的结果，用于错误解码与过期判断
+	blockhash Blockhash
+	compiled  []types.Instruction
+}
+
+// New 创建 Builder；默认使用 confirmed 级别的最新 blockhash 并等待 confirmed 确认
+func New(c *client.Client) *Builder {
+	return &Builder{
+		c:          c,
+		source:     LatestBlockhash(rpc.CommitmentConfirmed),
+		commitment: rpc.CommitmentConfirmed,
+		interval:   500 * time.Millisecond,
+	}
+}
+
+// FeePayer 设置手续费支付者（同时签名）；未设置时使用第一个签名者
+func (b *Builder) FeePayer(acc types.Account) *Builder {
+	b.feePayer = &acc
+	return b
+}
+
+// Signers 追加额外签名者，例如新建账户的密钥
+func (b *Builder) Signers(accs ...types.Account) *Builder {
+	b.signers = append(b.signers, accs...)
+	return b
+}
+
+// Add 追加指令
+func (b *Builder) Add(ixs ...types.Instruction) *Builder {
+	b.ixs = append(b.ixs, ixs...)
+	return b
+}
+
+// LookupTables 设置地址查找表；非空时发送 v0 交易
+func (b *Builder) LookupTables(tables ...types.AddressLookupTableAccount) *Builder {
+	b.tables = append(b.tables, tables...)
+	return b
+}
+
+// ComputeUnitLimit 设置计算单元上限（0 表示使用运行时默认值）
+func (b *Builder) ComputeUnitLimit(units uint32) *Builder {
+	b.unitLimit = units
+	return b
+}
+
+// ComputeUnitPrice 设置每个计算单元的优先费（micro-lamports）
+func (b *Builder) ComputeUnitPrice(microLamports uint64) *Builder {
+	b.unitPrice = microLamports
+	return b
+}
+
+// BlockhashFrom 设置 blockhash 来源，见 LatestBlockhash / FixedBlockhash / DurableNonce
+func (b *Builder) BlockhashFrom(src BlockhashSource) *Builder {
+	b.source = src
+	return b
+}
+
+// Commitment 设置 SendAndConfirm 等待的确认级别（confirmed 或 finalized）
+func (b *Builder) Commitment(commitment rpc.Commitment) *Builder {
+	b.commitment = commitment
+	return b
+}
+
+// PollInterval 设置确认轮询间隔
+func (b *Builder) PollInterval(d time.Duration) *Builder {
+	b.interval = d
+	return b
+}
+
+// RecentBlockhash 返回最近一次构造交易时使用的 blockhash
+func (b *Builder) RecentBlockhash() string {
+	return b.blockhash.Hash
+}
+
+// Build 获取 blockhash 并构造已签名交易。
+// 指令顺序：nonce 推进、计算预算、用户指令。
+func (b *Builder) Build(ctx context.Context) (types.Transaction, error) {
+	signers := b.allSigners()
+	if len(signers) == 0 {
+		return types.Transaction{}, errors.New("txbuilder: no fee payer or signers")
+	}
+	if len(b.ixs) == 0 {
+		return types.Transaction{}, errors.New("txbuilder: no instructions")
+	}
+	bh, err := b.source(ctx, b.c)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	ixs := append([]types.Instruction{}, bh.Prepend...)
+	if b.unitLimit > 0 {
+		ixs = append(ixs, SetComputeUnitLimit(b.unitLimit))
+	}
+	if b.unitPrice > 0 {
+		ixs = append(ixs, SetComputeUnitPrice(b.unitPrice))
+	}
+	ixs = append(ixs, b.ixs...)
+
+	tx, err := types.NewTransaction(types.NewTransactionParam{
+		Message: NewMessage(signers[0].PublicKey, bh.Hash, ixs, b.tables...),
+		Signers: signers,
+	})
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("failed to build transaction: %w", err)
+	}
+	b.blockhash, b.compiled = bh, ixs
+	return tx, nil
+}
+
+// Simulate 模拟交易，返回日志与消耗的计算单元；失败时错误中带有解码后的程序错误
+func (b *Builder) Simulate(ctx context.Context) (client.SimulateTransaction, error) {
+	tx, err := b.Build(ctx)
+	if err != nil {
+		return client.SimulateTransaction{}, err
+	}
+	sim, err := b.c.SimulateTransaction(ctx, tx)
+	if err != nil {
+		return sim, fmt.Errorf("failed to simulate transaction: %w", anchor.DecodeError(err, b.compiled...))
+	}
+	if sim.Err != nil {
+		if pe := anchor.DecodeTransactionError(sim.Err, sim.Logs, b.compiled...); pe != nil {
+			return sim, pe
+		}
+		return sim, fmt.Errorf("simulation failed: %v", sim.Err)
+	}
+	return sim, nil
+}
+
+// Send 构造并发送交易，不等待确认
+func (b *Builder) Send(ctx context.Context) (string, error) {
+	tx, err := b.Build(ctx)
+	if err != nil {
+		return "", err
+	}
+	sig, err := b.c.SendTransaction(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", anchor.DecodeError(err, b.compiled...))
+	}
+	return sig, nil
+}
+
+// SendAndConfirm 发送交易并轮询直到达到设置的确认级别
+func (b *Builder) SendAndConfirm(ctx context.Context) (string, error) {
+	sig, err := b.Send(ctx)
+	if err != nil {
+		return "", err
+	}
+	return sig, b.Confirm(ctx, sig)
+}
+
+// Confirm 轮询签名状态直到确认、交易失败、blockhash 过期或 ctx 结束
+func (b *Builder) Confirm(ctx context.Context, sig string) error {
+	for {
+		status, err := b.c.GetSignatureStatus(ctx, sig)
+		if err != nil {
+			return fmt.Errorf("failed to get signature status: %w", err)
+		}
+		if status != nil {
+			if status.Err != nil {
+				if pe := anchor.DecodeTransactionError(status.Err, nil, b.compiled...); pe != nil {
+					return pe
+				}
+				return fmt.Errorf("transaction %s failed: %v", sig, status.Err)
+			}
+			if reached(status.ConfirmationStatus, b.commitment) {
+				return nil
+			}
+		} else if b.blockhash.LastValidBlockHeight > 0 {
+			height, err := b.c.RpcClient.GetBlockHeightWithConfig(ctx, rpc.GetBlockHeightConfig{Commitment: rpc.CommitmentConfirmed})
+			if err == nil && height.Error == nil && height.Result > b.blockhash.LastValidBlockHeight {
+				return fmt.Errorf("%w: %s", ErrBlockhashExpired, sig)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for confirmation of %s: %w", sig, ctx.Err())
+		case <-time.After(b.interval):
+		}
+	}
+}
+
+// SetComputeUnitLimit 构造 ComputeBudget 程序的 SetComputeUnitLimit 指令（tag 2 + u32 LE）
+func SetComputeUnitLimit(units uint32) types.Instruction {
+	data := make([]byte, 5)
+	data[0] = 2
+	binary.LittleEndian.PutUint32(data[1:], units)
+	return types.Instruction{ProgramID: common.ComputeBudgetProgramID, Data: data}
+}
+
+// SetComputeUnitPrice 构造 ComputeBudget 程序的 SetComputeUnitPrice 指令（tag 3 + u64 LE）
+func SetComputeUnitPrice(microLamports uint64) types.Instruction {
+	data := make([]byte, 9)
+	data[0] = 3
+	binary.LittleEndian.PutUint64(data[1:], microLamports)
+	return types.Instruction{ProgramID: common.ComputeBudgetProgramID, Data: data}
+}
+
+// allSigners 返回去重后的签名者，手续费支付者在首位
+func (b *Builder) allSigners() []types.Account {
+	var out []types.Account
+	seen := map[common.PublicKey]bool{}
+	add := func(acc types.Account) {
+		if !seen[acc.PublicKey] {
+			seen[acc.PublicKey] = true
+			out = append(out, acc)
+		}
+	}
+	if b.feePayer != nil {
+		add(*b.feePayer)
+	}
+	for _, acc := range b.signers {
+		add(acc)
+	}
+	return out
+}
+
+func reached(status *rpc.Commitment, want rpc.Commitment) bool {
+	if status == nil {
+		return false
+	}
+	switch want {
+	case rpc.CommitmentFinalized:
+		return *status == rpc.CommitmentFinalized
+	case rpc.CommitmentProcessed:
+		return true
+	default:
+		return *status != rpc.CommitmentProcessed
+	}
+}
@@ -0,0 +1,72 @@
+package txbuilder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	alt "github.com/blocto/solana-go-sdk/program/address_lookup_table"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// MaxExtendAddresses 是单笔 extend 交易可以追加的地址数（受 1232 字节交易大小限制）
+const MaxExtendAddresses = 20
+
+// FetchLookupTable 读取并解析地址查找表账户
+func FetchLookupTable(ctx context.Context, c *client.Client, table common.PublicKey) (alt.AddressLookupTable, error) {
+	info, err := c.GetAccountInfo(ctx, table.ToBase58())
+	if err != nil {
+		return alt.AddressLookupTable{}, fmt.Errorf("failed to fetch lookup table %s: %w", table.ToBase58(), err)
+	}
+	if len(info.Data) == 0 {
+		return alt.AddressLookupTable{}, fmt.Errorf("lookup table %s not found", table.ToBase58())
+	}
+	state, err := alt.DeserializeLookupTable(info.Data, info.Owner)
+	if err != nil {
+		return alt.AddressLookupTable{}, fmt.Errorf("failed to decode lookup table %s: %w", table.ToBase58(), err)
+	}
+	return state, nil
+}
+
+// FetchLookupTableAccounts 读取若干查找表，转换为构造 v0 消息所需的形式
+func FetchLookupTableAccounts(ctx context.Context, c *client.Client, tables ...common.PublicKey) ([]types.AddressLookupTableAccount, error) {
+	out := make([]types.AddressLookupTableAccount, 0, len(tables))
+	for _, table := range tables {
+		state, err := FetchLookupTable(ctx, c, table)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, types.AddressLookupTableAccount{Key: table, Addresses: state.Addresses})
+	}
+	return out, nil
+}
+
+// NewLookupTableInstruction 构造 create_lookup_table 指令。
+// recentSlot 必须是近期（仍在 SlotHashes 中）的 slot，返回查找表地址。
+func NewLookupTableInstruction(authority, payer common.PublicKey, recentSlot uint64) (types.Instruction, common.PublicKey) {
+	table, bump := alt.DeriveLookupTableAddress(authority, recentSlot)
+	return alt.CreateLookupTable(alt.CreateLookupTableParams{
+		LookupTable: table,
+		Authority:   authority,
+		Payer:       payer,
+		RecentSlot:  recentSlot,
+		BumpSeed:    bump,
+	}), table
+}
+
+// ExtendLookupTableInstructions 把 addresses 按 MaxExtendAddresses 分批，
+// 每批一条 extend_lookup_table 指令（各自需要单独一笔交易）。
+func ExtendLookupTableInstructions(table, authority, payer common.PublicKey, addresses []common.PublicKey) []types.Instruction {
+	var ixs []types.Instruction
+	for start := 0; start < len(addresses); start += MaxExtendAddresses {
+		end := min(start+MaxExtendAddresses, len(addresses))
+		ixs = append(ixs, alt.ExtendLookupTable(alt.ExtendLookupTableParams{
+			LookupTable: table,
+			Authority:   authority,
+			Payer:       &payer,
+			Addresses:   addresses[start:end],
+		}))
+	}
+	return ixs
+}
@@ -0,0 +1,67 @@
+// Package txbuilder 是各 Go 客户端共用的交易构造工具。
+// Builder 以链式调用组装指令、签名者、计算预算与 blockhash 来源，并负责模拟、发送与确认；
+// 传入地址查找表时生成 v0 消息，否则生成 legacy 消息。
+// PackInstructions 按交易大小上限拆分批量指令。
+package txbuilder
+
+import (
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// MaxTransactionSize 是序列化后交易的大小上限（IPv6 MTU 1280 - 48 字节头）
+const MaxTransactionSize = 1232
+
+// NewMessage 组装消息：tables 非空时为 v0 消息，表中出现的非签名账户以索引引用
+func NewMessage(feePayer common.PublicKey, recentBlockhash string, ixs []types.Instruction, tables ...types.AddressLookupTableAccount) types.Message {
+	return types.NewMessage(types.NewMessageParam{
+		FeePayer:                   feePayer,
+		RecentBlockhash:            recentBlockhash,
+		Instructions:               ixs,
+		AddressLookupTableAccounts: tables,
+	})
+}
+
+// TransactionSize 返回消息签名后的序列化大小
+func TransactionSize(msg types.Message) (int, error) {
+	b, err := msg.Serialize()
+	if err != nil {
+		return 0, err
+	}
+	n := int(msg.Header.NumRequireSignatures)
+	// 签名数量是 compact-u16，签名数不超过 127 时占 1 字节
+	return len(b) + 1 + 64*n, nil
+}
+
+// PackInstructions 贪心地把指令分成若干批，使每批组成的交易不超过 MaxTransactionSize。
+// 使用查找表时单笔交易能容纳更多指令。
+func PackInstructions(feePayer common.PublicKey, ixs []types.Instruction, tables ...types.AddressLookupTableAccount) ([][]types.Instruction, error) {
+	// blockhash 只影响内容不影响长度，用占位值估算
+	placeholder := common.PublicKey{}.ToBase58()
+	var batches [][]types.Instruction
+	var cur []types.Instruction
+	for i := 0; i < len(ixs); {
+		next := append(append([]types.Instruction{}, cur...), ixs[i])
+		size, err := TransactionSize(NewMessage(feePayer, placeholder, next, tables...))
+		if err != nil {
+			return nil, err
+		}
+		if size <= MaxTransactionSize {
+			cur = next
+			i++
+			continue
+		}
+		if len(cur) == 0 {
+			return nil, fmt.Errorf("instruction %d alone exceeds the %d byte transaction limit (%d bytes)", i, MaxTransactionSize, size)
+		}
+		// 当前批已满，用同一条指令开启下一批
+		batches = append(batches, cur)
+		cur = nil
+	}
+	if len(cur) > 0 {
+		batches = append(batches, cur)
+	}
+	return batches, nil
+}
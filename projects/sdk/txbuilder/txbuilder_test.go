@@ -0,0 +1,171 @@
+package txbuilder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/sysprog"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// transfers 返回 n 笔 payer 到不同收款地址的转账，以及收款地址列表
+func transfers(payer common.PublicKey, n int) ([]types.Instruction, []common.PublicKey) {
+	ixs := make([]types.Instruction, n)
+	to := make([]common.PublicKey, n)
+	for i := range ixs {
+		to[i] = types.NewAccount().PublicKey
+		ixs[i] = sysprog.Transfer(sysprog.TransferParam{From: payer, To: to[i], Amount: 1})
+	}
+	return ixs, to
+}
+
+// dataInstruction 返回只携带 n 字节数据、不引用账户的指令
+func dataInstruction(n int) types.Instruction {
+	return types.Instruction{ProgramID: common.MemoProgramID, Data: make([]byte, n)}
+}
+
+func TestTransactionSize(t *testing.T) {
+	payer, other := types.NewAccount(), types.NewAccount()
+	ixs, to := transfers(payer.PublicKey, 3)
+	// 第二个签名者覆盖签名数量的计算
+	ixs = append(ixs, sysprog.Transfer(sysprog.TransferParam{From: other.PublicKey, To: payer.PublicKey, Amount: 1}))
+	table := types.AddressLookupTableAccount{Key: types.NewAccount().PublicKey, Addresses: to}
+	blockhash := common.PublicKey{}.ToBase58()
+
+	for _, tt := range []struct {
+		name   string
+		tables []types.AddressLookupTableAccount
+	}{
+		{"legacy", nil},
+		{"v0 with lookup table", []types.AddressLookupTableAccount{table}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := NewMessage(payer.PublicKey, blockhash, ixs, tt.tables...)
+			size, err := TransactionSize(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tx, err := types.NewTransaction(types.NewTransactionParam{Message: msg, Signers: []types.Account{payer, other}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw, err := tx.Serialize()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size != len(raw) {
+				t.Fatalf("TransactionSize = %d, signed transaction is %d bytes", size, len(raw))
+			}
+		})
+	}
+}
+
+func TestPackInstructionsAtLimit(t *testing.T) {
+	payer := types.NewAccount().PublicKey
+	placeholder := common.PublicKey{}.ToBase58()
+	// 找到恰好组成 MaxTransactionSize 字节交易的数据长度
+	n := 0
+	for {
+		size, err := TransactionSize(NewMessage(payer, placeholder, []types.Instruction{dataInstruction(n + 1)}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size > MaxTransactionSize {
+			break
+		}
+		n++
+	}
+	size, _ := TransactionSize(NewMessage(payer, placeholder, []types.Instruction{dataInstruction(n)}))
+	if size != MaxTransactionSize {
+		t.Fatalf("no data length fills the transaction exactly (closest %d bytes)", size)
+	}
+
+	batches, err := PackInstructions(payer, []types.Instruction{dataInstruction(n)})
+	if err != nil || len(batches) != 1 {
+		t.Fatalf("a %d byte transaction was not packed alone: %d batches, %v", MaxTransactionSize, len(batches), err)
+	}
+	_, err = PackInstructions(payer, []types.Instruction{dataInstruction(n + 1)})
+	if err == nil || !strings.Contains(err.Error(), "alone exceeds") {
+		t.Fatalf("a %d byte instruction was accepted: %v", MaxTransactionSize+1, err)
+	}
+}
+
+func TestPackInstructions(t *testing.T) {
+	payer := types.NewAccount().PublicKey
+	placeholder := common.PublicKey{}.ToBase58()
+	ixs, to := transfers(payer, 80)
+	table := types.AddressLookupTableAccount{Key: types.NewAccount().PublicKey, Addresses: to}
+
+	// perTx 是一笔交易能容纳的最多转账数
+	perTx := func(tables ...types.AddressLookupTableAccount) int {
+		for n := 1; n <= len(ixs); n++ {
+			size, err := TransactionSize(NewMessage(payer, placeholder, ixs[:n], tables...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size > MaxTransactionSize {
+				return n - 1
+			}
+		}
+		t.Fatal("every transfer fits in one transaction")
+		return 0
+	}
+
+	for _, tt := range []struct {
+		name   string
+		tables []types.AddressLookupTableAccount
+	}{
+		{"legacy", nil},
+		{"v0 with lookup table", []types.AddressLookupTableAccount{table}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			limit := perTx(tt.tables...)
+			full, err := PackInstructions(payer, ixs[:limit], tt.tables...)
+			if err != nil || len(full) != 1 {
+				t.Fatalf("%d transfers up to the limit: %d batches, %v", limit, len(full), err)
+			}
+			over, err := PackInstructions(payer, ixs[:limit+1], tt.tables...)
+			if err != nil || len(over) != 2 || len(over[0]) != limit || len(over[1]) != 1 {
+				t.Fatalf("%d transfers just past the limit: %v batches, %v", limit+1, batchSizes(over), err)
+			}
+
+			batches, err := PackInstructions(payer, ixs, tt.tables...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next := 0
+			for i, batch := range batches {
+				for j, ix := range batch {
+					if ix.Accounts[1].PubKey != to[next+j] {
+						t.Fatalf("batch %d reorders instructions", i)
+					}
+				}
+				next += len(batch)
+				size, err := TransactionSize(NewMessage(payer, placeholder, batch, tt.tables...))
+				if err != nil || size > MaxTransactionSize {
+					t.Fatalf("batch %d is %d bytes: %v", i, size, err)
+				}
+				// 贪心打包：除最后一批外，再加一条指令就会超限
+				if i < len(batches)-1 && len(batch) != limit {
+					t.Fatalf("batch %d holds %d transfers, want %d", i, len(batch), limit)
+				}
+			}
+			if next != len(ixs) {
+				t.Fatalf("packed %d of %d instructions", next, len(ixs))
+			}
+		})
+	}
+
+	if legacy, v0 := perTx(), perTx(table); v0 <= legacy {
+		t.Fatalf("a lookup table fits %d transfers per transaction, legacy %d", v0, legacy)
+	}
+}
+
+func batchSizes(batches [][]types.Instruction) []int {
+	out := make([]int, len(batches))
+	for i, b := range batches {
+		out[i] = len(b)
+	}
+	return out
+}
@@ -0,0 +1,351 @@
+// Package ws 是 Solana PubSub（websocket JSON-RPC）的最小客户端，
+// blocto/solana-go-sdk 只提供 HTTP RPC，订阅类接口在这里补齐。
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	pingInterval = 20 * time.Second
+	readTimeout  = 60 * time.Second
+)
+
+// ErrClosed 表示连接已关闭
+var ErrClosed = errors.New("ws: connection closed")
+
+// EndpointFor 由 HTTP RPC 地址推出 websocket 地址：http→ws、https→wss，
+// 本地 validator 的 8899 端口对应 8900。
+func EndpointFor(httpURL string) string {
+	u, err := url.Parse(strings.TrimSpace(httpURL))
+	if err != nil {
+		return httpURL
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	}
+	if port := u.Port(); port != "" {
+		if p, err := strconv.Atoi(port); err == nil && p == 8899 {
+			u.Host = u.Hostname() + ":8900"
+		}
+	}
+	return u.String()
+}
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params,omitempty"`
+}
+
+// pendingCall 是等待响应的请求；sub 非空时表示这是一次订阅，
+// 读循环在收到订阅 id 的同时注册它，避免漏掉紧随响应到达的通知。
+type pendingCall struct {
+	ch  chan message
+	sub *Subscription
+}
+
+type message struct {
+	ID     *uint64           `json:"id"`
+	Result json.RawMessage   `json:"result"`
+	Error  *rpc.JsonRpcError `json:"error"`
+	Method string            `json:"method"`
+	Params *struct {
+		Result       json.RawMessage `json:"result"`
+		Subscription uint64          `json:"subscription"`
+	} `json:"params"`
+}
+
+// Client 是一条 websocket 连接，可以承载多个订阅
+type Client struct {
+	conn *websocket.Conn
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]*pendingCall
+	subs    map[uint64]*Subscription
+	err     error
+	done    chan struct{}
+}
+
+// Dial 建立连接并启动读循环
+func Dial(ctx context.Context, endpoint string) (*Client, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ws: dial %s: %w", endpoint, err)
+	}
+	c := &Client{
+		conn:    conn,
+		pending: map[uint64]*pendingCall{},
+		subs:    map[uint64]*Subscription{},
+		done:    make(chan struct{}),
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(readTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(readTimeout))
+	})
+	go c.readLoop()
+	go c.pingLoop()
+	return c, nil
+}
+
+// Done 在连接断开后关闭，Err 返回断开原因
+func (c *Client) Done() <-chan struct{} { return c.done }
+
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Close 关闭连接，所有订阅的通道随之关闭
+func (c *Client) Close() error {
+	err := c.conn.Close()
+	c.shutdown(ErrClosed)
+	return err
+}
+
+func (c *Client) shutdown(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	for _, pc := range c.pending {
+		close(pc.ch)
+	}
+	for _, s := range c.subs {
+		close(s.c)
+	}
+	c.pending = map[uint64]*pendingCall{}
+	c.subs = map[uint64]*Subscription{}
+	close(c.done)
+}
+
+func (c *Client) readLoop() {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			_ = c.conn.Close()
+			c.shutdown(err)
+			return
+		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(readTimeout))
+		var msg message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		c.mu.Lock()
+		if msg.ID != nil {
+			if pc, ok := c.pending[*msg.ID]; ok {
+				delete(c.pending, *msg.ID)
+				if pc.sub != nil && msg.Error == nil {
+					if err := json.Unmarshal(msg.Result, &pc.sub.ID); err == nil {
+						c.subs[pc.sub.ID] = pc.sub
+					}
+				}
+				pc.ch <- msg
+			}
+		} else if msg.Params != nil {
+			if s, ok := c.subs[msg.Params.Subscription]; ok {
+				select {
+				case s.c <- msg.Params.Result:
+				default:
+					// 消费者跟不上时断开连接，由调用方重连补齐，而不是悄悄丢通知
+					c.mu.Unlock()
+					_ = c.conn.Close()
+					c.shutdown(fmt.Errorf("ws: subscription %d buffer overflow", s.ID))
+					return
+				}
+			}
+		}
+		c.mu.Unlock()
+	}
+}
+
+func (c *Client) pingLoop() {
+	t := time.NewTicker(pingInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-t.C:
+			c.writeMu.Lock()
+			err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second))
+			c.writeMu.Unlock()
+			if err != nil {
+				_ = c.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// call 发送一次请求并等待对应 id 的响应
+func (c *Client) call(ctx context.Context, method string, params []any, out any) error {
+	return c.do(ctx, method, params, out, nil)
+}
+
+func (c *Client) do(ctx context.Context, method string, params []any, out any, sub *Subscription) error {
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return err
+	}
+	c.nextID++
+	id := c.nextID
+	ch := make(chan message, 1)
+	c.pending[id] = &pendingCall{ch: ch, sub: sub}
+	c.mu.Unlock()
+
+	c.writeMu.Lock()
+	err := c.conn.WriteJSON(request{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	c.writeMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("ws: %s: %w", method, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return ctx.Err()
+	case msg, ok := <-ch:
+		if !ok {
+			return fmt.Errorf("ws: %s: %w", method, c.Err())
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("ws: %s: %w", method, msg.Error)
+		}
+		if out != nil {
+			return json.Unmarshal(msg.Result, out)
+		}
+		return nil
+	}
+}
+
+// Subscription 是一个订阅，通知的 result 原样（JSON）从 C 读出；
+// 连接断开时 C 被关闭。
+type Subscription struct {
+	ID uint64
+
+	client      *Client
+	unsubscribe string
+	c           chan json.RawMessage
+}
+
+func (s *Subscription) C() <-chan json.RawMessage { return s.c }
+
+// Unsubscribe 取消订阅
+func (s *Subscription) Unsubscribe(ctx context.Context) error {
+	s.client.mu.Lock()
+	if _, ok := s.client.subs[s.ID]; ok {
+		delete(s.client.subs, s.ID)
+		close(s.c)
+	}
+	s.client.mu.Unlock()
+	return s.client.call(ctx, s.unsubscribe, []any{s.ID}, nil)
+}
+
+// Subscribe 发送任意 xxxSubscribe 请求，unsubscribe 为对应的取消方法名
+func (c *Client) Subscribe(ctx context.Context, method, unsubscribe string, params ...any) (*Subscription, error) {
+	s := &Subscription{client: c, unsubscribe: unsubscribe, c: make(chan json.RawMessage, 1024)}
+	if err := c.do(ctx, method, params, nil, s); err != nil {
+		c.mu.Lock()
+		if c.subs[s.ID] == s {
+			delete(c.subs, s.ID)
+		}
+		c.mu.Unlock()
+		return nil, err
+	}
+	return s, nil
+}
+
+// NotificationContext 是通知里的 context 字段
+type NotificationContext struct {
+	Slot uint64 `json:"slot"`
+}
+
+// LogsNotification 是 logsSubscribe 的一条通知
+type LogsNotification struct {
+	Context NotificationContext `json:"context"`
+	Value   struct {
+		Signature string   `json:"signature"`
+		Err       any      `json:"err"`
+		Logs      []string `json:"logs"`
+	} `json:"value"`
+}
+
+// LogsSubscribe 订阅提及 mentions 地址的交易日志
+func (c *Client) LogsSubscribe(ctx context.Context, mentions common.PublicKey, commitment rpc.Commitment) (*Subscription, error) {
+	return c.Subscribe(ctx, "logsSubscribe", "logsUnsubscribe",
+		map[string]any{"mentions": []string{mentions.ToBase58()}},
+		map[string]any{"commitment": commitment},
+	)
+}
+
+// AccountInfo 是订阅通知里的账户内容（encoding = base64 时 Data 为 ["<base64>", "base64"]）
+type AccountInfo struct {
+	Lamports   uint64 `json:"lamports"`
+	Owner      string `json:"owner"`
+	Data       any    `json:"data"`
+	Executable bool   `json:"executable"`
+	RentEpoch  uint64 `json:"rentEpoch"`
+}
+
+// ProgramNotification 是 programSubscribe 的一条通知
+type ProgramNotification struct {
+	Context NotificationContext `json:"context"`
+	Value   struct {
+		Pubkey  string      `json:"pubkey"`
+		Account AccountInfo `json:"account"`
+	} `json:"value"`
+}
+
+// ProgramSubscribe 订阅程序所属账户的变更，filters 与 getProgramAccounts 相同
+func (c *Client) ProgramSubscribe(ctx context.Context, programID common.PublicKey, commitment rpc.Commitment, filters ...rpc.GetProgramAccountsConfigFilter) (*Subscription, error) {
+	cfg := map[string]any{
+		"encoding":   rpc.AccountEncodingBase64,
+		"commitment": commitment,
+	}
+	if len(filters) > 0 {
+		cfg["filters"] = filters
+	}
+	return c.Subscribe(ctx, "programSubscribe", "programUnsubscribe", programID.ToBase58(), cfg)
+}
+
+// AccountNotification 是 accountSubscribe 的一条通知
+type AccountNotification struct {
+	Context NotificationContext `json:"context"`
+	Value   *AccountInfo        `json:"value"`
+}
+
+// AccountSubscribe 订阅单个账户的变更
+func (c *Client) AccountSubscribe(ctx context.Context, account common.PublicKey, commitment rpc.Commitment) (*Subscription, error) {
+	return c.Subscribe(ctx, "accountSubscribe", "accountUnsubscribe", account.ToBase58(), map[string]any{
+		"encoding":   rpc.AccountEncodingBase64,
+		"commitment": commitment,
+	})
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/txbuilder"
+)
+
+// runALTCreate creates an address lookup table owned by the signer and
+// optionally fills it with addresses.
+func runALTCreate(fromPrivBase58, fromFilePath string, addresses []common.PublicKey, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	authority, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+
+	// The derivation slot must still be in the SlotHashes sysvar; a finalized slot always is.
+	slot, err := c.GetSlotWithConfig(ctx, client.GetSlotConfig{Commitment: rpc.CommitmentFinalized})
+	if err != nil {
+		return fmt.Errorf("failed to get slot: %w", err)
+	}
+	createIx, table := txbuilder.NewLookupTableInstruction(authority.PublicKey, authority.PublicKey, slot)
+	extendIxs := txbuilder.ExtendLookupTableInstructions(table, authority.PublicKey, authority.PublicKey, addresses)
+
+	// The first extend batch fits in the create transaction.
+	first := []types.Instruction{createIx}
+	if len(extendIxs) > 0 {
+		first = append(first, extendIxs[0])
+		extendIxs = extendIxs[1:]
+	}
+	txhash, err := sendAndConfirm(ctx, c, authority, first)
+	if err != nil {
+		return err
+	}
+	txhashes := []string{txhash}
+	for _, ix := range extendIxs {
+		txhash, err := sendAndConfirm(ctx, c, authority, []types.Instruction{ix})
+		if err != nil {
+			return err
+		}
+		txhashes = append(txhashes, txhash)
+	}
+
+	out := map[string]any{
+		"cluster":    cluster,
+		"table":      table.ToBase58(),
+		"authority":  authority.PublicKey.ToBase58(),
+		"recentSlot": slot,
+		"addresses":  len(addresses),
+		"txhashes":   txhashes,
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// runALTExtend appends addresses to an existing lookup table, skipping ones already in it.
+func runALTExtend(fromPrivBase58, fromFilePath, tableBase58 string, addresses []common.PublicKey, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	authority, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	table := common.PublicKeyFromString(tableBase58)
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	state, err := txbuilder.FetchLookupTable(ctx, c, table)
+	if err != nil {
+		return err
+	}
+	if state.Authority == nil || *state.Authority != authority.PublicKey {
+		return fmt.Errorf("signer %s is not the authority of lookup table %s", authority.PublicKey.ToBase58(), tableBase58)
+	}
+	existing := make(map[common.PublicKey]bool, len(state.Addresses))
+	for _, a := range state.Addresses {
+		existing[a] = true
+	}
+	var missing []common.PublicKey
+	for _, a := range addresses {
+		if !existing[a] {
+			existing[a] = true
+			missing = append(missing, a)
+		}
+	}
+	if len(state.Addresses)+len(missing) > 256 {
+		return fmt.Errorf("lookup table would hold %d addresses, the limit is 256", len(state.Addresses)+len(missing))
+	}
+
+	var txhashes []string
+	for _, ix := range txbuilder.ExtendLookupTableInstructions(table, authority.PublicKey, authority.PublicKey, missing) {
+		txhash, err := sendAndConfirm(ctx, c, authority, []types.Instruction{ix})
+		if err != nil {
+			return err
+		}
+		txhashes = append(txhashes, txhash)
+	}
+
+	out := map[string]any{
+		"cluster":  cluster,
+		"table":    tableBase58,
+		"added":    len(missing),
+		"skipped":  len(addresses) - len(missing),
+		"total":    len(state.Addresses) + len(missing),
+		"txhashes": txhashes,
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func runALTShow(tableBase58, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	state, err := txbuilder.FetchLookupTable(ctx, c, common.PublicKeyFromString(tableBase58))
+	if err != nil {
+		return err
+	}
+	addrs := make([]string, len(state.Addresses))
+	for i, a := range state.Addresses {
+		addrs[i] = a.ToBase58()
+	}
+	out := map[string]any{
+		"cluster":          cluster,
+		"table":            tableBase58,
+		"active":           state.DeactivationSlot == math.MaxUint64,
+		"lastExtendedSlot": state.LastExtendedSlot,
+		"addresses":        addrs,
+	}
+	if state.Authority != nil {
+		out["authority"] = state.Authority.ToBase58()
+	} else {
+		out["authority"] = nil // frozen
+	}
+	if state.DeactivationSlot != math.MaxUint64 {
+		out["deactivationSlot"] = state.DeactivationSlot
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// fetchLookupTables loads the comma separated --lookup-table addresses (may be empty).
+func fetchLookupTables(ctx context.Context, c *client.Client, list string) ([]types.AddressLookupTableAccount, error) {
+	var keys []common.PublicKey
+	for _, s := range strings.Split(list, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		if !isValidBase58Pubkey(s) {
+			return nil, fmt.Errorf("invalid lookup table address %q", s)
+		}
+		keys = append(keys, common.PublicKeyFromString(s))
+	}
+	return txbuilder.FetchLookupTableAccounts(ctx, c, keys...)
+}
+
+// parseAddresses merges a comma separated list with an optional file holding one
+// address per line (the first CSV column is used, so a recipients file works too).
+func parseAddresses(list, filePath string) ([]common.PublicKey, error) {
+	var raw []string
+	raw = append(raw, strings.Split(list, ",")...)
+	if filePath != "" {
+		f, err := os.Open(filePath)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			raw = append(raw, strings.TrimSpace(strings.Split(line, ",")[0]))
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+	}
+	var out []common.PublicKey
+	for _, s := range raw {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		if !isValidBase58Pubkey(s) {
+			return nil, fmt.Errorf("invalid address %q", s)
+		}
+		out = append(out, common.PublicKeyFromString(s))
+	}
+	return out, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/sysprog"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/txbuilder"
+)
+
+type payout struct {
+	To       common.PublicKey
+	Lamports uint64
+}
+
+// runBatchTransfer pays every recipient in the CSV file, packing as many transfers
+// per transaction as fit. Lookup tables holding the recipients shrink each transfer
+// from a 32-byte key to a 1-byte index, so far more fit per (v0) transaction.
+func runBatchTransfer(fromPrivBase58, fromFilePath, recipientsPath, lookupTables string, dryRun bool, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	from, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	payouts, err := readPayouts(recipientsPath)
+	if err != nil {
+		return err
+	}
+	if len(payouts) == 0 {
+		return fmt.Errorf("no recipients in %s", recipientsPath)
+	}
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	tables, err := fetchLookupTables(ctx, c, lookupTables)
+	if err != nil {
+		return err
+	}
+
+	ixs := make([]types.Instruction, len(payouts))
+	var total uint64
+	for i, p := range payouts {
+		ixs[i] = sysprog.Transfer(sysprog.TransferParam{From: from.PublicKey, To: p.To, Amount: p.Lamports})
+		total += p.Lamports
+	}
+	batches, err := txbuilder.PackInstructions(from.PublicKey, ixs, tables...)
+	if err != nil {
+		return err
+	}
+
+	type batchResult struct {
+		TxHash    string `json:"txhash,omitempty"`
+		Transfers int    `json:"transfers"`
+		Lamports  uint64 `json:"lamports"`
+	}
+	results := make([]batchResult, 0, len(batches))
+	next := 0
+	for _, batch := range batches {
+		r := batchResult{Transfers: len(batch)}
+		for _, p := range payouts[next : next+len(batch)] {
+			r.Lamports += p.Lamports
+		}
+		next += len(batch)
+		if !dryRun {
+			r.TxHash, err = sendAndConfirm(ctx, c, from, batch, tables...)
+			if err != nil {
+				return fmt.Errorf("batch %d (after %d confirmed transactions): %w", len(results), len(results), err)
+			}
+		}
+		results = append(results, r)
+	}
+
+	tableKeys := make([]string, len(tables))
+	for i, t := range tables {
+		tableKeys[i] = t.Key.ToBase58()
+	}
+	out := map[string]any{
+		"cluster":      cluster,
+		"from":         from.PublicKey.ToBase58(),
+		"recipients":   len(payouts),
+		"lamports":     total,
+		"lookupTables": tableKeys,
+		"dryRun":       dryRun,
+		"transactions": results,
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// readPayouts reads "address,lamports" lines; blank lines and # comments are skipped.
+func readPayouts(path string) ([]payout, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out []payout
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.Split(line, ",")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected address,lamports", path, n)
+		}
+		addr := strings.TrimSpace(parts[0])
+		if !isValidBase58Pubkey(addr) {
+			return nil, fmt.Errorf("%s:%d: invalid address %q", path, n, addr)
+		}
+		lamports, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil || lamports == 0 {
+			return nil, fmt.Errorf("%s:%d: invalid lamports %q", path, n, parts[1])
+		}
+		out = append(out, payout{To: common.PublicKeyFromString(addr), Lamports: lamports})
+	}
+	return out, sc.Err()
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/anchor"
+	"sdk/programs/chain"
+	"sdk/txbuilder"
+)
+
+// runWalletCreate calls the chain program's create_wallet, which creates the
+// ["wallet", payer] PDA as a system account funded with initialLamports.
+// Lookup tables, if given, turn the transaction into a v0 message.
+func runWalletCreate(fromPrivBase58, fromFilePath, seed string, initialLamports uint64, lookupTables, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	payer, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	wallet, bump, err := chain.FindWalletAddress(payer.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to derive wallet PDA: %w", err)
+	}
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	tables, err := fetchLookupTables(ctx, c, lookupTables)
+	if err != nil {
+		return err
+	}
+
+	// A zero-space system account still has to be rent exempt.
+	minRent, err := c.GetMinimumBalanceForRentExemption(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get rent-exempt minimum: %w", err)
+	}
+	if initialLamports == 0 {
+		initialLamports = minRent
+	}
+	if initialLamports < minRent {
+		return fmt.Errorf("--lamports %d is below the rent-exempt minimum %d", initialLamports, minRent)
+	}
+
+	ix := chain.NewCreateWalletInstruction(
+		chain.CreateWalletAccounts{
+			Payer:  payer.PublicKey,
+			Wallet: wallet,
+		},
+		chain.CreateWalletArgs{
+			Seed:            seed,
+			InitialLamports: initialLamports,
+		},
+	)
+	txhash, err := sendAndConfirm(ctx, c, payer, []types.Instruction{ix}, tables...)
+	if err != nil {
+		return err
+	}
+	balance, err := c.GetBalance(ctx, wallet.ToBase58())
+	if err != nil {
+		return fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+
+	out := map[string]any{
+		"cluster":  cluster,
+		"txhash":   txhash,
+		"payer":    payer.PublicKey.ToBase58(),
+		"wallet":   wallet.ToBase58(),
+		"bump":     bump,
+		"lamports": balance,
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// runWalletBalance calls get_balance and decodes the BalanceEvent that the
+// program writes to the "Program data:" log.
+func runWalletBalance(fromPrivBase58, fromFilePath, walletBase58 string, simulate bool, lookupTables, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	payer, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	var wallet common.PublicKey
+	if walletBase58 != "" {
+		wallet = common.PublicKeyFromString(walletBase58)
+	} else {
+		wallet, _, err = chain.FindWalletAddress(payer.PublicKey)
+		if err != nil {
+			return fmt.Errorf("failed to derive wallet PDA: %w", err)
+		}
+	}
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	tables, err := fetchLookupTables(ctx, c, lookupTables)
+	if err != nil {
+		return err
+	}
+	ix := chain.NewGetBalanceInstruction(chain.GetBalanceAccounts{Wallet: wallet}, chain.GetBalanceArgs{})
+
+	var txhash string
+	var logs []string
+	if simulate {
+		logs, err = simulateLogs(ctx, c, payer, []types.Instruction{ix}, tables...)
+	} else {
+		txhash, err = sendAndConfirm(ctx, c, payer, []types.Instruction{ix}, tables...)
+		if err == nil {
+			logs, err = transactionLogs(ctx, c, txhash)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	var event *chain.BalanceEvent
+	for _, data := range anchor.ProgramEvents(logs, chain.ProgramID) {
+		name, v, err := chain.DecodeEvent(data)
+		if err != nil {
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+		if name == "BalanceEvent" {
+			event = v.(*chain.BalanceEvent)
+		}
+	}
+	if event == nil {
+		return errors.New("no BalanceEvent found in transaction logs")
+	}
+
+	out := map[string]any{
+		"cluster":   cluster,
+		"simulated": simulate,
+		"event":     "BalanceEvent",
+		"wallet":    event.Wallet.ToBase58(),
+		"lamports":  event.Lamports,
+	}
+	if txhash != "" {
+		out["txhash"] = txhash
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// sendAndConfirm sends the transaction and waits until it is confirmed.
+// With lookup tables the transaction is sent as a v0 message.
+func sendAndConfirm(ctx context.Context, c *client.Client, signer types.Account, ixs []types.Instruction, tables ...types.AddressLookupTableAccount) (string, error) {
+	return txbuilder.New(c).FeePayer(signer).Add(ixs...).LookupTables(tables...).SendAndConfirm(ctx)
+}
+
+func simulateLogs(ctx context.Context, c *client.Client, signer types.Account, ixs []types.Instruction, tables ...types.AddressLookupTableAccount) ([]string, error) {
+	sim, err := txbuilder.New(c).FeePayer(signer).Add(ixs...).LookupTables(tables...).Simulate(ctx)
+	return sim.Logs, err
+}
+
+func transactionLogs(ctx context.Context, c *client.Client, txhash string) ([]string, error) {
+	tx, err := c.GetTransactionWithConfig(ctx, txhash, client.GetTransactionConfig{Commitment: rpc.CommitmentConfirmed})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if tx == nil || tx.Meta == nil {
+		return nil, fmt.Errorf("transaction %s not found", txhash)
+	}
+	return tx.Meta.LogMessages, nil
+}
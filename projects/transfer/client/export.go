@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+)
+
+// ledgerRow is one balance change of the exported address in one transaction.
+// Amounts are UI decimal strings so they survive CSV and JSON unchanged.
+type ledgerRow struct {
+	Time         string `json:"time"` // RFC 3339, UTC
+	Slot         uint64 `json:"slot"`
+	Signature    string `json:"signature"`
+	Asset        string `json:"asset"` // SOL or the SPL mint
+	Account      string `json:"account"`
+	Amount       string `json:"amount"`  // signed change excluding the fee
+	Fee          string `json:"fee"`     // SOL paid as fee payer
+	Balance      string `json:"balance"` // running balance after the transaction
+	Counterparty string `json:"counterparty"`
+	Failed       bool   `json:"failed"`
+
+	sort     int    // SOL rows first within a transaction
+	index    int    // position of the transaction in the getSignaturesForAddress results
+	pre      uint64 // balance before the transaction, raw units
+	change   int64  // net change including the fee, raw units
+	decimals uint8
+}
+
+var ledgerHeader = []string{"time", "slot", "signature", "asset", "account", "amount", "fee", "balance", "counterparty", "failed"}
+
+// runExport writes every balance change of address and its SPL token accounts
+// between from (inclusive) and to (exclusive). Rows are ordered by slot, and
+// within a slot in the order getSignaturesForAddress lists the transactions,
+// so repeated exports of the same range are byte-identical. The
+// running balance of each account starts at its balance before its first
+// transaction in the range and adds up the net changes from there.
+func runExport(addressBase58 string, from, to time.Time, format string, w io.Writer, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	owner := common.PublicKeyFromString(addressBase58)
+
+	// Incoming SPL transfers only touch the token account, so walk those too.
+	// Token accounts closed before the export runs cannot be found this way.
+	accounts := []common.PublicKey{owner}
+	tokens, err := c.GetTokenAccountsByOwnerByProgram(ctx, addressBase58, common.TokenProgramID.ToBase58())
+	if err != nil {
+		return fmt.Errorf("failed to get token accounts: %w", err)
+	}
+	for _, t := range tokens {
+		accounts = append(accounts, t.PublicKey)
+	}
+
+	var sigs []string
+	seen := map[string]bool{}
+	for _, a := range accounts {
+		if sigs, err = collectSignatures(ctx, c, a, from, to, sigs, seen); err != nil {
+			return err
+		}
+	}
+
+	rows := []ledgerRow{}
+	for i, sig := range sigs {
+		tx, err := c.GetTransactionWithConfig(ctx, sig, client.GetTransactionConfig{Commitment: rpc.CommitmentConfirmed})
+		if err != nil {
+			return fmt.Errorf("failed to get transaction %s: %w", sig, err)
+		}
+		if tx == nil || tx.Meta == nil {
+			continue
+		}
+		for _, r := range ledgerRows(tx, sig, owner) {
+			r.index = i
+			rows = append(rows, r)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.Slot != b.Slot {
+			return a.Slot < b.Slot
+		}
+		// Signatures come newest first, so a higher index is earlier in the slot.
+		if a.index != b.index {
+			return a.index > b.index
+		}
+		if a.sort != b.sort {
+			return a.sort < b.sort
+		}
+		if a.Asset != b.Asset {
+			return a.Asset < b.Asset
+		}
+		return a.Account < b.Account
+	})
+	running := map[string]int64{}
+	for i := range rows {
+		r := &rows[i]
+		key := r.Asset + "/" + r.Account
+		bal, ok := running[key]
+		if !ok {
+			bal = int64(r.pre)
+		}
+		bal += r.change
+		running[key] = bal
+		r.Balance = formatSignedAmount(bal, r.decimals)
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write(ledgerHeader)
+		for _, r := range rows {
+			_ = cw.Write([]string{r.Time, strconv.FormatUint(r.Slot, 10), r.Signature, r.Asset, r.Account,
+				r.Amount, r.Fee, r.Balance, r.Counterparty, strconv.FormatBool(r.Failed)})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return fmt.Errorf("unknown format %q (csv, json)", format)
+}
+
+// collectSignatures appends the signatures of account whose block time falls
+// in [from, to) and that are not in seen yet, in the newest-first order of
+// getSignaturesForAddress, paging backwards until it passes from.
+func collectSignatures(ctx context.Context, c *client.Client, account common.PublicKey, from, to time.Time, out []string, seen map[string]bool) ([]string, error) {
+	before := ""
+	for {
+		page, err := c.GetSignaturesForAddressWithConfig(ctx, account.ToBase58(), client.GetSignaturesForAddressConfig{
+			Before:     before,
+			Commitment: rpc.CommitmentConfirmed,
+		})
+		if err != nil {
+			return out, fmt.Errorf("failed to get signatures of %s: %w", account.ToBase58(), err)
+		}
+		if len(page) == 0 {
+			return out, nil
+		}
+		for _, s := range page {
+			if s.BlockTime == nil {
+				continue
+			}
+			t := time.Unix(*s.BlockTime, 0)
+			if !t.Before(from) && t.Before(to) && !seen[s.Signature] {
+				seen[s.Signature] = true
+				out = append(out, s.Signature)
+			}
+			if t.Before(from) {
+				return out, nil
+			}
+		}
+		before = page[len(page)-1].Signature
+	}
+}
+
+// ledgerRows returns the SOL row (if the owner's balance changed or it paid
+// the fee) and one row per owned token account whose balance changed.
+func ledgerRows(tx *client.Transaction, sig string, owner common.PublicKey) []ledgerRow {
+	var when string
+	if tx.BlockTime != nil {
+		when = time.Unix(*tx.BlockTime, 0).UTC().Format(time.RFC3339)
+	}
+	base := ledgerRow{Time: when, Slot: tx.Slot, Signature: sig, Failed: tx.Meta.Err != nil}
+	var rows []ledgerRow
+
+	for i, k := range tx.AccountKeys {
+		if k != owner || i >= len(tx.Meta.PreBalances) || i >= len(tx.Meta.PostBalances) {
+			continue
+		}
+		var fee uint64
+		if i == 0 { // the fee payer is always the first account
+			fee = tx.Meta.Fee
+		}
+		change := tx.Meta.PostBalances[i] - tx.Meta.PreBalances[i]
+		amount := change + int64(fee)
+		if amount == 0 && fee == 0 {
+			break
+		}
+		r := base
+		r.Asset, r.Account = "SOL", owner.ToBase58()
+		r.Amount = formatSignedAmount(amount, 9)
+		r.Fee = formatTokenAmount(fee, 9)
+		r.pre, r.change, r.decimals = uint64(tx.Meta.PreBalances[i]), change, 9
+		r.Counterparty = solCounterparty(tx, i, amount)
+		rows = append(rows, r)
+		break
+	}
+
+	type tokenChange struct {
+		mint, owner string
+		decimals    uint8
+		pre, post   uint64
+	}
+	changes := map[uint64]*tokenChange{}
+	get := func(b rpc.TransactionMetaTokenBalance) *tokenChange {
+		tc := changes[b.AccountIndex]
+		if tc == nil {
+			tc = &tokenChange{mint: b.Mint, owner: b.Owner, decimals: b.UITokenAmount.Decimals}
+			changes[b.AccountIndex] = tc
+		}
+		return tc
+	}
+	for _, b := range tx.Meta.PreTokenBalances {
+		get(b).pre, _ = strconv.ParseUint(b.UITokenAmount.Amount, 10, 64)
+	}
+	for _, b := range tx.Meta.PostTokenBalances {
+		get(b).post, _ = strconv.ParseUint(b.UITokenAmount.Amount, 10, 64)
+	}
+	// Account index order keeps the counterparty choice stable between runs.
+	indices := make([]uint64, 0, len(changes))
+	for idx := range changes {
+		indices = append(indices, idx)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	for _, idx := range indices {
+		tc := changes[idx]
+		if tc.owner != owner.ToBase58() || tc.pre == tc.post || int(idx) >= len(tx.AccountKeys) {
+			continue
+		}
+		delta := int64(tc.post) - int64(tc.pre)
+		r := base
+		r.sort = 1
+		r.Asset, r.Account = tc.mint, tx.AccountKeys[idx].ToBase58()
+		r.Amount = formatSignedAmount(delta, tc.decimals)
+		r.Fee = "0"
+		r.pre, r.change, r.decimals = tc.pre, delta, tc.decimals
+		// The counterparty is the owner of the first token account that moved the other way.
+		for _, other := range indices {
+			if oc := changes[other]; other != idx && oc.mint == tc.mint && int64(oc.post)-int64(oc.pre) == -delta {
+				r.Counterparty = oc.owner
+				break
+			}
+		}
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+// solCounterparty returns the account whose balance moved by exactly the
+// opposite amount, which identifies the other side of a plain transfer.
+func solCounterparty(tx *client.Transaction, self int, amount int64) string {
+	if amount == 0 {
+		return ""
+	}
+	for i := range tx.AccountKeys {
+		if i == self || i >= len(tx.Meta.PreBalances) || i >= len(tx.Meta.PostBalances) {
+			continue
+		}
+		change := tx.Meta.PostBalances[i] - tx.Meta.PreBalances[i]
+		if i == 0 {
+			change += int64(tx.Meta.Fee)
+		}
+		if change == -amount {
+			return tx.AccountKeys[i].ToBase58()
+		}
+	}
+	return ""
+}
+
+func formatSignedAmount(v int64, decimals uint8) string {
+	if v < 0 {
+		return "-" + formatTokenAmount(uint64(-v), decimals)
+	}
+	return formatTokenAmount(uint64(v), decimals)
+}
+
+// parseExportDate parses YYYY-MM-DD as midnight UTC.
+func parseExportDate(s string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, want YYYY-MM-DD", s)
+	}
+	return t, nil
+}
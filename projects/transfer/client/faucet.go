@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/anchor"
+	"sdk/txbuilder"
+)
+
+// faucetConfig holds the `faucet serve` limits.
+type faucetConfig struct {
+	Listen       string
+	MaxLamports  uint64        // largest single request
+	PerAddress   int           // requests per address per Window
+	PerIP        int           // requests per client IP per Window
+	Window       time.Duration // rate-limit window
+	DailyCap     uint64        // lamports paid out per UTC day
+	TrustProxy   bool          // take the client IP from X-Forwarded-For
+	AuditLogPath string
+}
+
+// faucetAudit is one line of the JSON audit log.
+type faucetAudit struct {
+	Time     time.Time `json:"time"`
+	IP       string    `json:"ip"`
+	Address  string    `json:"address,omitempty"`
+	Lamports uint64    `json:"lamports,omitempty"`
+	Status   string    `json:"status"` // sent | unconfirmed | rejected | failed
+	Reason   string    `json:"reason,omitempty"`
+	Txhash   string    `json:"txhash,omitempty"`
+}
+
+// faucet pays out SOL from a treasury keypair through the same instruction
+// assembly as `transfer`, enforcing per-address, per-IP and daily limits.
+type faucet struct {
+	cfg      faucetConfig
+	cluster  string
+	c        *client.Client
+	treasury types.Account
+
+	mu        sync.Mutex
+	byAddress map[string][]time.Time
+	byIP      map[string][]time.Time
+	lastPrune time.Time
+	day       string // UTC date the daily total belongs to
+	paidToday uint64
+
+	auditMu sync.Mutex
+	audit   *json.Encoder
+}
+
+// errRateLimited marks limit rejections, reported as HTTP 429.
+var errRateLimited = errors.New("rate limited")
+
+// reserve checks the limits for one request and records it. The returned
+// release gives back the address slot and the daily amount when the transfer
+// is not sent; the request still counts against the client IP.
+func (f *faucet) reserve(now time.Time, address, ip string, lamports uint64) (release func(), err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if day := now.UTC().Format("2006-01-02"); day != f.day {
+		f.day, f.paidToday = day, 0
+	}
+	if now.Sub(f.lastPrune) >= f.cfg.Window {
+		f.prune(now)
+	}
+	if f.paidToday+lamports > f.cfg.DailyCap {
+		return nil, fmt.Errorf("%w: daily cap of %d lamports reached", errRateLimited, f.cfg.DailyCap)
+	}
+	addrHits := recentHits(f.byAddress[address], now, f.cfg.Window)
+	if len(addrHits) >= f.cfg.PerAddress {
+		return nil, fmt.Errorf("%w: %s already received %d airdrops in %s", errRateLimited, address, len(addrHits), f.cfg.Window)
+	}
+	ipHits := recentHits(f.byIP[ip], now, f.cfg.Window)
+	if len(ipHits) >= f.cfg.PerIP {
+		return nil, fmt.Errorf("%w: %s made %d requests in %s", errRateLimited, ip, len(ipHits), f.cfg.Window)
+	}
+	f.byAddress[address] = append(addrHits, now)
+	f.byIP[ip] = append(ipHits, now)
+	f.paidToday += lamports
+	day := f.day
+
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.byAddress[address] = dropHit(f.byAddress[address], now)
+		if f.day == day {
+			f.paidToday -= lamports
+		}
+	}, nil
+}
+
+// prune forgets addresses and IPs with no request inside the window, so the
+// maps only hold recent clients. Callers hold f.mu.
+func (f *faucet) prune(now time.Time) {
+	for _, m := range []map[string][]time.Time{f.byAddress, f.byIP} {
+		for k, hits := range m {
+			if hits = recentHits(hits, now, f.cfg.Window); len(hits) == 0 {
+				delete(m, k)
+			} else {
+				m[k] = hits
+			}
+		}
+	}
+	f.lastPrune = now
+}
+
+// restore replays the audit log so a restart keeps today's payout total and
+// the requests still inside the rate-limit window. Sent requests count against
+// the address and the IP, failed ones only against the IP, as in reserve.
+// Unconfirmed transfers may have landed, so they count as sent.
+func (f *faucet) restore(path string, now time.Time) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	f.day = now.UTC().Format("2006-01-02")
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var a faucetAudit
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil {
+			continue // a line cut short by a crash
+		}
+		paid := a.Status == "sent" || a.Status == "unconfirmed"
+		if paid && a.Time.UTC().Format("2006-01-02") == f.day {
+			f.paidToday += a.Lamports
+		}
+		if now.Sub(a.Time) >= f.cfg.Window {
+			continue
+		}
+		switch {
+		case paid:
+			f.byAddress[a.Address] = append(f.byAddress[a.Address], a.Time)
+			f.byIP[a.IP] = append(f.byIP[a.IP], a.Time)
+		case a.Status == "failed":
+			f.byIP[a.IP] = append(f.byIP[a.IP], a.Time)
+		}
+	}
+	f.lastPrune = now
+	return scanner.Err()
+}
+
+// recentHits drops timestamps older than window.
+func recentHits(hits []time.Time, now time.Time, window time.Duration) []time.Time {
+	kept := hits[:0]
+	for _, t := range hits {
+		if now.Sub(t) < window {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+func dropHit(hits []time.Time, t time.Time) []time.Time {
+	for i, h := range hits {
+		if h.Equal(t) {
+			return append(hits[:i], hits[i+1:]...)
+		}
+	}
+	return hits
+}
+
+func (f *faucet) logAudit(a faucetAudit) {
+	f.auditMu.Lock()
+	defer f.auditMu.Unlock()
+	if err := f.audit.Encode(a); err != nil {
+		log.Printf("faucet: failed to write audit log: %v", err)
+	}
+}
+
+// clientIP returns the request's client address, honoring X-Forwarded-For
+// only when the faucet runs behind a trusted proxy.
+func (f *faucet) clientIP(r *http.Request) string {
+	if f.cfg.TrustProxy {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			return strings.TrimSpace(strings.Split(fwd, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// handleAirdrop serves POST /airdrop with {"address": "...", "lamports": n}.
+// lamports defaults to the per-request maximum.
+func (f *faucet) handleAirdrop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"error": "use POST"})
+		return
+	}
+	entry := faucetAudit{Time: time.Now().UTC(), IP: f.clientIP(r), Status: "rejected"}
+	fail := func(status int, err error) {
+		entry.Reason = err.Error()
+		f.logAudit(entry)
+		writeJSON(w, status, map[string]any{"error": err.Error()})
+	}
+
+	var body struct {
+		Address  string `json:"address"`
+		Lamports uint64 `json:"lamports"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&body); err != nil {
+		fail(http.StatusBadRequest, fmt.Errorf("invalid JSON body: %w", err))
+		return
+	}
+	entry.Address = strings.TrimSpace(body.Address)
+	if !isValidBase58Pubkey(entry.Address) {
+		fail(http.StatusBadRequest, errors.New("invalid address"))
+		return
+	}
+	if body.Lamports == 0 {
+		body.Lamports = f.cfg.MaxLamports
+	}
+	entry.Lamports = body.Lamports
+	if body.Lamports > f.cfg.MaxLamports {
+		fail(http.StatusBadRequest, fmt.Errorf("at most %d lamports per request", f.cfg.MaxLamports))
+		return
+	}
+
+	release, err := f.reserve(entry.Time, entry.Address, entry.IP, body.Lamports)
+	if err != nil {
+		fail(http.StatusTooManyRequests, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 25*time.Second)
+	defer cancel()
+	to := common.PublicKeyFromString(entry.Address)
+	tx := txbuilder.New(f.c).FeePayer(f.treasury).
+		Add(transferInstructions(f.treasury.PublicKey, to, body.Lamports, nil, "")...)
+	txhash, err := tx.Send(ctx)
+	if err != nil {
+		release()
+		entry.Status = "failed"
+		fail(http.StatusBadGateway, fmt.Errorf("transfer failed: %w", err))
+		return
+	}
+	entry.Txhash = txhash
+	if err := tx.Confirm(ctx, txhash); err != nil {
+		// A transfer that failed on chain or whose blockhash expired paid
+		// nothing. Any other error leaves the outcome open, so the request
+		// keeps its address slot and daily amount.
+		var pe *anchor.ProgramError
+		if errors.Is(err, txbuilder.ErrBlockhashExpired) || errors.As(err, &pe) {
+			release()
+			entry.Status = "failed"
+			fail(http.StatusBadGateway, fmt.Errorf("transfer %s failed: %w", txhash, err))
+			return
+		}
+		entry.Status = "unconfirmed"
+		fail(http.StatusGatewayTimeout, fmt.Errorf("transfer %s was not confirmed: %w", txhash, err))
+		return
+	}
+
+	entry.Status = "sent"
+	f.logAudit(entry)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"cluster":  f.cluster,
+		"txhash":   txhash,
+		"to":       entry.Address,
+		"lamports": body.Lamports,
+	})
+}
+
+// handleStatus serves GET /status: treasury balance and what is left of today's cap.
+func (f *faucet) handleStatus(w http.ResponseWriter, r *http.Request) {
+	bal, err := f.c.GetBalance(r.Context(), f.treasury.PublicKey.ToBase58())
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]any{"error": fmt.Sprintf("failed to get balance: %v", err)})
+		return
+	}
+	f.mu.Lock()
+	paid := f.paidToday
+	if f.day != time.Now().UTC().Format("2006-01-02") {
+		paid = 0
+	}
+	f.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"cluster":          f.cluster,
+		"treasury":         f.treasury.PublicKey.ToBase58(),
+		"treasuryLamports": bal,
+		"maxLamports":      f.cfg.MaxLamports,
+		"dailyCap":         f.cfg.DailyCap,
+		"paidToday":        paid,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// runFaucetServe serves the faucet until interrupted.
+func runFaucetServe(keypairPath string, cfg faucetConfig, cluster, rpcOverride string) error {
+	treasury, err := loadAccountFromFile(keypairPath)
+	if err != nil {
+		return fmt.Errorf("failed to load treasury keypair: %w", err)
+	}
+	auditFile, err := os.OpenFile(cfg.AuditLogPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer auditFile.Close()
+
+	f := &faucet{
+		cfg:       cfg,
+		cluster:   cluster,
+		c:         client.NewClient(resolveEndpoint(cluster, rpcOverride)),
+		treasury:  treasury,
+		byAddress: map[string][]time.Time{},
+		byIP:      map[string][]time.Time{},
+		audit:     json.NewEncoder(auditFile),
+	}
+	if err := f.restore(cfg.AuditLogPath, time.Now()); err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/airdrop", f.handleAirdrop)
+	mux.HandleFunc("/status", f.handleStatus)
+	srv := &http.Server{Addr: cfg.Listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+	log.Printf("faucet: treasury %s on %s, %d lamports paid today, listening on %s", treasury.PublicKey.ToBase58(), cluster, f.paidToday, cfg.Listen)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
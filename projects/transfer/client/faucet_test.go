@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/banksim"
+)
+
+// testFaucetConfig allows two airdrops per address, three requests per IP and
+// five full airdrops a day.
+func testFaucetConfig() faucetConfig {
+	return faucetConfig{
+		MaxLamports: 100_000_000,
+		PerAddress:  2,
+		PerIP:       3,
+		Window:      time.Hour,
+		DailyCap:    500_000_000,
+	}
+}
+
+// newTestFaucet returns a faucet paying from a funded treasury through c,
+// writing its audit log to a temporary file.
+func newTestFaucet(t *testing.T, bank *banksim.Bank, c *client.Client, cfg faucetConfig) *faucet {
+	t.Helper()
+	treasury := types.NewAccount()
+	bank.Fund(treasury.PublicKey, 10_000_000_000)
+	cfg.AuditLogPath = filepath.Join(t.TempDir(), "audit.jsonl")
+	file, err := os.Create(cfg.AuditLogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return &faucet{
+		cfg:       cfg,
+		cluster:   "local",
+		c:         c,
+		treasury:  treasury,
+		byAddress: map[string][]time.Time{},
+		byIP:      map[string][]time.Time{},
+		audit:     json.NewEncoder(file),
+	}
+}
+
+// airdrop posts body to the faucet from ip and returns the status code and
+// the decoded response.
+func airdrop(t *testing.T, f *faucet, ip string, body any) (int, map[string]any) {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/airdrop", bytes.NewReader(data))
+	req.RemoteAddr = ip + ":40000"
+	rec := httptest.NewRecorder()
+	f.handleAirdrop(rec, req)
+	var out map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&out); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	return rec.Code, out
+}
+
+func readAudit(t *testing.T, path string) []faucetAudit {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var out []faucetAudit
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var a faucetAudit
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func TestFaucetAirdrop(t *testing.T) {
+	bank, _, c := newTestBank(t)
+	f := newTestFaucet(t, bank, c, testFaucetConfig())
+	to := types.NewAccount().PublicKey.ToBase58()
+
+	code, out := airdrop(t, f, "10.0.0.1", map[string]any{"address": to, "lamports": 40_000_000})
+	if code != http.StatusOK {
+		t.Fatalf("status %d: %v", code, out)
+	}
+	txhash, _ := out["txhash"].(string)
+	if _, txErr, ok := bank.SignatureStatus(txhash); !ok || txErr != nil {
+		t.Fatalf("status of %s: ok=%v err=%v", txhash, ok, txErr)
+	}
+	if got := bank.Balance(common.PublicKeyFromString(to)); got != 40_000_000 {
+		t.Fatalf("recipient has %d lamports, want 40000000", got)
+	}
+	audit := readAudit(t, f.cfg.AuditLogPath)
+	if len(audit) != 1 || audit[0].Status != "sent" || audit[0].Txhash != txhash || audit[0].Lamports != 40_000_000 || audit[0].IP != "10.0.0.1" {
+		t.Fatalf("audit log %+v", audit)
+	}
+
+	// lamports defaults to the per-request maximum
+	code, out = airdrop(t, f, "10.0.0.1", map[string]any{"address": to})
+	if code != http.StatusOK || out["lamports"] != float64(100_000_000) {
+		t.Fatalf("status %d: %v", code, out)
+	}
+	if f.paidToday != 140_000_000 {
+		t.Fatalf("paid today %d, want 140000000", f.paidToday)
+	}
+}
+
+func TestFaucetRejectsBadRequests(t *testing.T) {
+	bank, _, c := newTestBank(t)
+	f := newTestFaucet(t, bank, c, testFaucetConfig())
+	to := types.NewAccount().PublicKey.ToBase58()
+
+	for _, tt := range []struct {
+		name string
+		body any
+		want string
+	}{
+		{"invalid address", map[string]any{"address": "not-an-address"}, "invalid address"},
+		{"over the maximum", map[string]any{"address": to, "lamports": 100_000_001}, "at most 100000000 lamports"},
+		{"not JSON", "address", "invalid JSON body"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out := airdrop(t, f, "10.0.0.1", tt.body)
+			if msg, _ := out["error"].(string); code != http.StatusBadRequest || !strings.Contains(msg, tt.want) {
+				t.Fatalf("status %d: %v", code, out)
+			}
+		})
+	}
+	// Rejected requests are audited but do not count against any limit.
+	if len(f.byIP) != 0 || f.paidToday != 0 {
+		t.Fatalf("rejected requests were counted: %v, %d", f.byIP, f.paidToday)
+	}
+	for _, a := range readAudit(t, f.cfg.AuditLogPath) {
+		if a.Status != "rejected" || a.Reason == "" {
+			t.Fatalf("audit entry %+v", a)
+		}
+	}
+}
+
+func TestFaucetLimits(t *testing.T) {
+	bank, _, c := newTestBank(t)
+	to := func() string { return types.NewAccount().PublicKey.ToBase58() }
+	expectLimited := func(t *testing.T, code int, out map[string]any, want string) {
+		t.Helper()
+		if msg, _ := out["error"].(string); code != http.StatusTooManyRequests || !strings.Contains(msg, want) {
+			t.Fatalf("status %d: %v", code, out)
+		}
+	}
+
+	t.Run("per address", func(t *testing.T) {
+		f := newTestFaucet(t, bank, c, testFaucetConfig())
+		addr := to()
+		for i, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+			if code, out := airdrop(t, f, ip, map[string]any{"address": addr}); code != http.StatusOK {
+				t.Fatalf("request %d: status %d: %v", i, code, out)
+			}
+		}
+		code, out := airdrop(t, f, "10.0.0.3", map[string]any{"address": addr})
+		expectLimited(t, code, out, "already received 2 airdrops")
+	})
+
+	t.Run("per IP", func(t *testing.T) {
+		f := newTestFaucet(t, bank, c, testFaucetConfig())
+		for i := 0; i < 3; i++ {
+			if code, out := airdrop(t, f, "10.0.0.1", map[string]any{"address": to()}); code != http.StatusOK {
+				t.Fatalf("request %d: status %d: %v", i, code, out)
+			}
+		}
+		code, out := airdrop(t, f, "10.0.0.1", map[string]any{"address": to()})
+		expectLimited(t, code, out, "10.0.0.1 made 3 requests")
+		if code, out := airdrop(t, f, "10.0.0.2", map[string]any{"address": to()}); code != http.StatusOK {
+			t.Fatalf("another IP: status %d: %v", code, out)
+		}
+	})
+
+	t.Run("daily cap", func(t *testing.T) {
+		cfg := testFaucetConfig()
+		cfg.PerIP = 100
+		f := newTestFaucet(t, bank, c, cfg)
+		for i := 0; i < 5; i++ {
+			if code, out := airdrop(t, f, "10.0.0.1", map[string]any{"address": to()}); code != http.StatusOK {
+				t.Fatalf("request %d: status %d: %v", i, code, out)
+			}
+		}
+		code, out := airdrop(t, f, "10.0.0.1", map[string]any{"address": to(), "lamports": 1})
+		expectLimited(t, code, out, "daily cap of 500000000 lamports")
+
+		// A new UTC day starts from zero.
+		tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+		release, err := f.reserve(tomorrow, to(), "10.0.0.1", cfg.MaxLamports)
+		if err != nil {
+			t.Fatalf("reserve on the next day: %v", err)
+		}
+		release()
+		if f.paidToday != 0 {
+			t.Fatalf("released reservation left %d lamports on the daily total", f.paidToday)
+		}
+	})
+}
+
+func TestFaucetFailedTransferReleasesLimits(t *testing.T) {
+	bank, _, c := newTestBank(t)
+	f := newTestFaucet(t, bank, c, testFaucetConfig())
+	// Drain the treasury so the transfer fails preflight.
+	bank.SetAccount(f.treasury.PublicKey, banksim.Account{})
+	addr := types.NewAccount().PublicKey.ToBase58()
+
+	code, out := airdrop(t, f, "10.0.0.1", map[string]any{"address": addr})
+	if code != http.StatusBadGateway {
+		t.Fatalf("status %d: %v", code, out)
+	}
+	if f.paidToday != 0 || len(f.byAddress[addr]) != 0 || len(f.byIP["10.0.0.1"]) != 1 {
+		t.Fatalf("paid %d, address hits %d, IP hits %d", f.paidToday, len(f.byAddress[addr]), len(f.byIP["10.0.0.1"]))
+	}
+	if audit := readAudit(t, f.cfg.AuditLogPath); len(audit) != 1 || audit[0].Status != "failed" {
+		t.Fatalf("audit log %+v", audit)
+	}
+}
+
+// hideStatuses forwards RPC calls to h but reports every signature as unknown,
+// so transfers are sent and never confirmed.
+func hideStatuses(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			ID     any    `json:"id"`
+			Method string `json:"method"`
+		}
+		_ = json.Unmarshal(body, &req)
+		if req.Method == "getSignatureStatuses" {
+			writeJSON(w, http.StatusOK, map[string]any{
+				"jsonrpc": "2.0", "id": req.ID,
+				"result": map[string]any{"context": map[string]any{"slot": 0}, "value": []any{nil}},
+			})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		h.ServeHTTP(w, r)
+	})
+}
+
+func TestFaucetUnconfirmedTransferKeepsLimits(t *testing.T) {
+	bank := banksim.New(banksim.Config{})
+	srv := httptest.NewServer(hideStatuses(bank.Handler()))
+	t.Cleanup(srv.Close)
+	f := newTestFaucet(t, bank, client.NewClient(srv.URL), testFaucetConfig())
+	addr := types.NewAccount().PublicKey.ToBase58()
+
+	data, _ := json.Marshal(map[string]any{"address": addr})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodPost, "/airdrop", bytes.NewReader(data)).WithContext(ctx)
+	req.RemoteAddr = "10.0.0.1:40000"
+	rec := httptest.NewRecorder()
+	f.handleAirdrop(rec, req)
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	// The transfer may have landed (here it did), so it still counts.
+	if f.paidToday != 100_000_000 || len(f.byAddress[addr]) != 1 {
+		t.Fatalf("paid %d, address hits %d", f.paidToday, len(f.byAddress[addr]))
+	}
+	audit := readAudit(t, f.cfg.AuditLogPath)
+	if len(audit) != 1 || audit[0].Status != "unconfirmed" || audit[0].Txhash == "" {
+		t.Fatalf("audit log %+v", audit)
+	}
+	if _, _, ok := bank.SignatureStatus(audit[0].Txhash); !ok {
+		t.Fatal("the unconfirmed transfer was not sent")
+	}
+}
+
+func TestFaucetPrune(t *testing.T) {
+	f := &faucet{cfg: testFaucetConfig(), byAddress: map[string][]time.Time{}, byIP: map[string][]time.Time{}}
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i, addr := range []string{"a", "b", "c"} {
+		if _, err := f.reserve(start.Add(time.Duration(i)*time.Minute), addr, "10.0.0."+addr, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// One window after the second request only the third is still recent.
+	if _, err := f.reserve(start.Add(time.Hour+time.Minute), "d", "10.0.0.d", 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.byAddress) != 2 || f.byAddress["c"] == nil || f.byAddress["d"] == nil || len(f.byIP) != 2 {
+		t.Fatalf("after pruning: addresses %v, IPs %v", f.byAddress, f.byIP)
+	}
+}
+
+func TestFaucetRestore(t *testing.T) {
+	now := time.Now().UTC()
+	// Keep every entry on today's UTC date.
+	if now.Hour() < 3 {
+		now = now.Add(3 * time.Hour)
+	}
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, a := range []faucetAudit{
+		{Time: now.Add(-10 * time.Minute), IP: "10.0.0.1", Address: "alice", Lamports: 100, Status: "sent"},
+		{Time: now.Add(-20 * time.Minute), IP: "10.0.0.1", Address: "bob", Lamports: 200, Status: "unconfirmed"},
+		{Time: now.Add(-30 * time.Minute), IP: "10.0.0.2", Address: "carol", Lamports: 400, Status: "failed"},
+		{Time: now.Add(-40 * time.Minute), IP: "10.0.0.3", Address: "dave", Lamports: 800, Status: "rejected"},
+		// Earlier today but outside the window: counts toward the daily total only.
+		{Time: now.Add(-2 * time.Hour), IP: "10.0.0.4", Address: "erin", Lamports: 1600, Status: "sent"},
+		// Yesterday: ignored.
+		{Time: now.AddDate(0, 0, -1), IP: "10.0.0.5", Address: "frank", Lamports: 3200, Status: "sent"},
+	} {
+		if err := enc.Encode(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf.WriteString(`{"time": "2026-`) // cut short by a crash
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &faucet{cfg: testFaucetConfig(), byAddress: map[string][]time.Time{}, byIP: map[string][]time.Time{}}
+	if err := f.restore(path, now); err != nil {
+		t.Fatal(err)
+	}
+	if f.paidToday != 100+200+1600 {
+		t.Fatalf("paid today %d, want %d", f.paidToday, 100+200+1600)
+	}
+	if len(f.byAddress) != 2 || len(f.byAddress["alice"]) != 1 || len(f.byAddress["bob"]) != 1 {
+		t.Fatalf("address hits %v", f.byAddress)
+	}
+	if len(f.byIP) != 2 || len(f.byIP["10.0.0.1"]) != 2 || len(f.byIP["10.0.0.2"]) != 1 {
+		t.Fatalf("IP hits %v", f.byIP)
+	}
+
+	missing := &faucet{cfg: testFaucetConfig(), byAddress: map[string][]time.Time{}, byIP: map[string][]time.Time{}}
+	if err := missing.restore(filepath.Join(t.TempDir(), "none.jsonl"), now); err != nil {
+		t.Fatalf("restore without an audit log: %v", err)
+	}
+}
@@ -5,6 +5,15 @@ go 1.23.4
 require (
 	github.com/blocto/solana-go-sdk v1.30.0
 	github.com/mr-tron/base58 v1.2.0
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 )
 
-require filippo.io/edwards25519 v1.0.0-rc.1 // indirect
+require (
+	filippo.io/edwards25519 v1.0.0-rc.1 // indirect
+	github.com/gorilla/websocket v1.5.3 // indirect
+	github.com/near/borsh-go v0.3.2-0.20220516180422-1ff87d108454 // indirect
+)
+
+require sdk v0.0.0
+
+replace sdk => ../../sdk
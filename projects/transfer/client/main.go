@@ -15,9 +15,13 @@ import (
 	"github.com/mr-tron/base58" 
 	"github.com/blocto/solana-go-sdk/client"
 	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/memo"
 	"github.com/blocto/solana-go-sdk/program/sysprog"
 	"github.com/blocto/solana-go-sdk/rpc"
 	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/anchor"
+	"sdk/txbuilder"
 )
 
 const lamportsPerSOL = 1_000_000_000
@@ -35,6 +39,7 @@ func main() {
 		addr := balanceCmd.String("address", "", "Account base58 address")
 		cluster := balanceCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
 		rpc := balanceCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		tokens := balanceCmd.Bool("tokens", false, "Also list SPL token balances")
 		_ = balanceCmd.Parse(os.Args[2:])
 		if *addr == "" {
 			log.Fatal("missing --address")
@@ -42,7 +47,7 @@ func main() {
 		if !isValidBase58Pubkey(*addr) {
 			log.Fatal("invalid --address base58")
 		}
-		if err := runBalance(*addr, normalizeCluster(*cluster), strings.TrimSpace(*rpc)); err != nil {
+		if err := runBalance(*addr, *tokens, normalizeCluster(*cluster), strings.TrimSpace(*rpc)); err != nil {
 			log.Fatalf("balance error: %v", err)
 		}
 	case "transfer":
@@ -53,6 +58,8 @@ func main() {
 		lamports := transferCmd.Uint64("lamports", 0, "Amount in lamports (1 SOL = 1e9)")
 		cluster := transferCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
 		rpc := transferCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		referenceAddr := transferCmd.String("reference", "", "Solana Pay reference key to attach to the transfer (base58)")
+		memoText := transferCmd.String("memo", "", "Memo program text to attach")
 		_ = transferCmd.Parse(os.Args[2:])
 		if (*fromPriv == "" && *fromFile == "") || *toAddr == "" || *lamports == 0 {
 			log.Fatal("missing required flags: --from or --fromFile, --to, --lamports")
@@ -60,8 +67,16 @@ func main() {
 		if !isValidBase58Pubkey(*toAddr) {
 			log.Fatal("invalid --to base58")
 		}
-		if err := runTransfer(*fromPriv, *fromFile, *toAddr, *lamports, normalizeCluster(*cluster), strings.TrimSpace(*rpc)); err != nil {
-			log.Fatalf("transfer error: %v", err)
+		var reference *common.PublicKey
+		if *referenceAddr != "" {
+			if !isValidBase58Pubkey(*referenceAddr) {
+				log.Fatal("invalid --reference base58")
+			}
+			pk := common.PublicKeyFromString(strings.TrimSpace(*referenceAddr))
+			reference = &pk
+		}
+		if err := runTransfer(*fromPriv, *fromFile, *toAddr, *lamports, reference, *memoText, normalizeCluster(*cluster), strings.TrimSpace(*rpc)); err != nil {
+			fatalTxError("transfer error", err)
 		}
 	case "airdrop":
 		airdropCmd := flag.NewFlagSet("airdrop", flag.ExitOnError)
@@ -79,50 +94,673 @@ func main() {
 		if err := runAirdrop(*toAddr, *lamports, normalizeCluster(*cluster), strings.TrimSpace(*rpc)); err != nil {
 			log.Fatalf("airdrop error: %v", err)
 		}
+	case "wallet":
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(1)
+		}
+		switch os.Args[2] {
+		case "create":
+			createCmd := flag.NewFlagSet("wallet create", flag.ExitOnError)
+			fromPriv := createCmd.String("from", "", "Payer private key (base58)")
+			fromFile := createCmd.String("fromFile", "", "Path to payer keypair JSON file (id.json)")
+			seed := createCmd.String("seed", "", "Seed string passed to create_wallet")
+			lamports := createCmd.Uint64("lamports", 0, "Initial lamports for the wallet PDA (default: rent-exempt minimum)")
+			tables := createCmd.String("lookup-table", "", "Comma separated lookup table addresses (sends a v0 transaction)")
+			cluster := createCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+			rpc := createCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+			_ = createCmd.Parse(os.Args[3:])
+			if *fromPriv == "" && *fromFile == "" {
+				log.Fatal("missing required flags: --from or --fromFile")
+			}
+			if err := runWalletCreate(*fromPriv, *fromFile, *seed, *lamports, *tables, normalizeCluster(*cluster), strings.TrimSpace(*rpc)); err != nil {
+				fatalTxError("wallet create error", err)
+			}
+		case "balance":
+			balanceCmd := flag.NewFlagSet("wallet balance", flag.ExitOnError)
+			fromPriv := balanceCmd.String("from", "", "Payer private key (base58)")
+			fromFile := balanceCmd.String("fromFile", "", "Path to payer keypair JSON file (id.json)")
+			wallet := balanceCmd.String("wallet", "", "System account to query (default: the payer's wallet PDA)")
+			simulate := balanceCmd.Bool("simulate", false, "Simulate get_balance instead of sending it (no fee)")
+			tables := balanceCmd.String("lookup-table", "", "Comma separated lookup table addresses (sends a v0 transaction)")
+			cluster := balanceCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+			rpc := balanceCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+			_ = balanceCmd.Parse(os.Args[3:])
+			if *fromPriv == "" && *fromFile == "" {
+				log.Fatal("missing required flags: --from or --fromFile")
+			}
+			if *wallet != "" && !isValidBase58Pubkey(*wallet) {
+				log.Fatal("invalid --wallet base58")
+			}
+			if err := runWalletBalance(*fromPriv, *fromFile, strings.TrimSpace(*wallet), *simulate, *tables, normalizeCluster(*cluster), strings.TrimSpace(*rpc)); err != nil {
+				fatalTxError("wallet balance error", err)
+			}
+		default:
+			printUsage()
+			os.Exit(1)
+		}
+	case "alt":
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(1)
+		}
+		altCmd := flag.NewFlagSet("alt "+os.Args[2], flag.ExitOnError)
+		fromPriv := altCmd.String("from", "", "Authority/payer private key (base58)")
+		fromFile := altCmd.String("fromFile", "", "Path to authority keypair JSON file (id.json)")
+		table := altCmd.String("table", "", "Lookup table address (base58)")
+		addrList := altCmd.String("addresses", "", "Comma separated addresses to add")
+		addrFile := altCmd.String("addresses-file", "", "File with one address per line (first CSV column)")
+		cluster := altCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+		rpc := altCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		_ = altCmd.Parse(os.Args[3:])
+		if os.Args[2] != "show" && *fromPriv == "" && *fromFile == "" {
+			log.Fatal("missing required flags: --from or --fromFile")
+		}
+		if os.Args[2] != "create" && !isValidBase58Pubkey(*table) {
+			log.Fatal("missing or invalid --table base58")
+		}
+		addresses, err := parseAddresses(*addrList, *addrFile)
+		if err != nil {
+			log.Fatalf("invalid addresses: %v", err)
+		}
+		switch os.Args[2] {
+		case "create":
+			err = runALTCreate(*fromPriv, *fromFile, addresses, normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+		case "extend":
+			if len(addresses) == 0 {
+				log.Fatal("missing required flags: --addresses or --addresses-file")
+			}
+			err = runALTExtend(*fromPriv, *fromFile, strings.TrimSpace(*table), addresses, normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+		case "show":
+			err = runALTShow(strings.TrimSpace(*table), normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+		default:
+			printUsage()
+			os.Exit(1)
+		}
+		if err != nil {
+			fatalTxError("alt "+os.Args[2]+" error", err)
+		}
+	case "token":
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(1)
+		}
+		tokenCmd := flag.NewFlagSet("token "+os.Args[2], flag.ExitOnError)
+		fromPriv := tokenCmd.String("from", "", "Signer private key (base58)")
+		fromFile := tokenCmd.String("fromFile", "", "Path to signer keypair JSON file (id.json)")
+		decimals := tokenCmd.Uint("decimals", 9, "Decimals of the new mint")
+		noFreeze := tokenCmd.Bool("no-freeze", false, "Create the mint without a freeze authority")
+		mint := tokenCmd.String("mint", "", "Mint address (base58)")
+		to := tokenCmd.String("to", "", "Owner wallet to mint to (default: the signer)")
+		account := tokenCmd.String("account", "", "Token account (default: the signer's associated token account)")
+		amount := tokenCmd.String("amount", "", "Amount in UI units, e.g. 1.5")
+		owner := tokenCmd.String("owner", "", "Wallet whose token accounts to list")
+		authType := tokenCmd.String("type", "mint", "Authority to change: mint|freeze|owner|close")
+		newAuth := tokenCmd.String("new-authority", "", "New authority (base58); omit to revoke")
+		cluster := tokenCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+		rpc := tokenCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		_ = tokenCmd.Parse(os.Args[3:])
+		if os.Args[2] != "accounts" && *fromPriv == "" && *fromFile == "" {
+			log.Fatal("missing required flags: --from or --fromFile")
+		}
+		for name, v := range map[string]string{"--mint": *mint, "--to": *to, "--account": *account, "--owner": *owner, "--new-authority": *newAuth} {
+			if v != "" && !isValidBase58Pubkey(v) {
+				log.Fatalf("invalid %s base58", name)
+			}
+		}
+		var err error
+		switch os.Args[2] {
+		case "create-mint":
+			if *decimals > 255 {
+				log.Fatal("--decimals must be at most 255")
+			}
+			err = runTokenCreateMint(*fromPriv, *fromFile, uint8(*decimals), *noFreeze, normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+		case "mint-to":
+			if *mint == "" || *amount == "" {
+				log.Fatal("missing required flags: --mint, --amount")
+			}
+			err = runTokenMintTo(*fromPriv, *fromFile, strings.TrimSpace(*mint), strings.TrimSpace(*to), *amount, normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+		case "burn":
+			if *mint == "" || *amount == "" {
+				log.Fatal("missing required flags: --mint, --amount")
+			}
+			err = runTokenBurn(*fromPriv, *fromFile, strings.TrimSpace(*mint), strings.TrimSpace(*account), *amount, normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+		case "accounts":
+			if *owner == "" {
+				log.Fatal("missing required flag: --owner")
+			}
+			err = runTokenAccounts(strings.TrimSpace(*owner), normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+		case "set-authority":
+			// Mint authorities live on the mint, owner/close authorities on the token account.
+			target := *mint
+			if *account != "" {
+				target = *account
+			}
+			if target == "" {
+				log.Fatal("missing required flags: --mint or --account")
+			}
+			err = runTokenSetAuthority(*fromPriv, *fromFile, strings.TrimSpace(target), strings.TrimSpace(*authType), strings.TrimSpace(*newAuth), normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+		default:
+			printUsage()
+			os.Exit(1)
+		}
+		if err != nil {
+			fatalTxError("token "+os.Args[2]+" error", err)
+		}
+	case "stake":
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(1)
+		}
+		stakeCmd := flag.NewFlagSet("stake "+os.Args[2], flag.ExitOnError)
+		fromPriv := stakeCmd.String("from", "", "Staker/withdrawer private key (base58)")
+		fromFile := stakeCmd.String("fromFile", "", "Path to staker keypair JSON file (id.json)")
+		amount := stakeCmd.String("amount", "", "SOL to delegate, e.g. 1.5 (the rent-exempt reserve is added)")
+		validator := stakeCmd.String("validator", "", "Vote account to delegate to (base58)")
+		seed := stakeCmd.String("seed", "", "Seed for the stake account address (default: stake:<unix time>)")
+		stakeAddr := stakeCmd.String("stake", "", "Stake account address (base58)")
+		toAddr := stakeCmd.String("to", "", "Withdraw recipient (default: the signer)")
+		lamports := stakeCmd.Uint64("lamports", 0, "Lamports to withdraw (default: the whole balance)")
+		epochs := stakeCmd.Uint64("epochs", 5, "Number of past epochs of rewards to show")
+		cluster := stakeCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+		rpc := stakeCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		_ = stakeCmd.Parse(os.Args[3:])
+		if os.Args[2] != "show" && *fromPriv == "" && *fromFile == "" {
+			log.Fatal("missing required flags: --from or --fromFile")
+		}
+		if os.Args[2] != "create" && !isValidBase58Pubkey(*stakeAddr) {
+			log.Fatal("missing or invalid --stake base58")
+		}
+		if *toAddr != "" && !isValidBase58Pubkey(*toAddr) {
+			log.Fatal("invalid --to base58")
+		}
+		var err error
+		switch os.Args[2] {
+		case "create":
+			if *amount == "" || !isValidBase58Pubkey(*validator) {
+				log.Fatal("missing required flags: --amount, --validator")
+			}
+			err = runStakeCreate(*fromPriv, *fromFile, *amount, strings.TrimSpace(*validator), *seed, normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+		case "deactivate":
+			err = runStakeDeactivate(*fromPriv, *fromFile, strings.TrimSpace(*stakeAddr), normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+		case "withdraw":
+			err = runStakeWithdraw(*fromPriv, *fromFile, strings.TrimSpace(*stakeAddr), strings.TrimSpace(*toAddr), *lamports, normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+		case "show":
+			err = runStakeShow(strings.TrimSpace(*stakeAddr), *epochs, normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+		default:
+			printUsage()
+			os.Exit(1)
+		}
+		if err != nil {
+			fatalTxError("stake "+os.Args[2]+" error", err)
+		}
+	case "sign-message":
+		signCmd := flag.NewFlagSet("sign-message", flag.ExitOnError)
+		fromPriv := signCmd.String("from", "", "Signer private key (base58)")
+		signerFile := signCmd.String("signer", "", "Path to signer keypair JSON file (id.json)")
+		message := signCmd.String("message", "", "Message text to sign")
+		messageFile := signCmd.String("message-file", "", "File whose contents are the message")
+		raw := signCmd.Bool("raw", false, "Sign the bytes directly instead of the off-chain message envelope")
+		_ = signCmd.Parse(os.Args[2:])
+		if *fromPriv == "" && *signerFile == "" {
+			log.Fatal("missing required flags: --from or --signer")
+		}
+		msg, err := readMessage(*message, *messageFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := runSignMessage(*fromPriv, *signerFile, msg, *raw); err != nil {
+			log.Fatalf("sign-message error: %v", err)
+		}
+	case "verify-message":
+		verifyCmd := flag.NewFlagSet("verify-message", flag.ExitOnError)
+		pubkey := verifyCmd.String("pubkey", "", "Signer public key (base58)")
+		signature := verifyCmd.String("signature", "", "Signature (base58)")
+		message := verifyCmd.String("message", "", "Message text that was signed")
+		messageFile := verifyCmd.String("message-file", "", "File whose contents are the message")
+		raw := verifyCmd.Bool("raw", false, "Verify a signature over the bytes directly instead of the off-chain envelope")
+		_ = verifyCmd.Parse(os.Args[2:])
+		if !isValidBase58Pubkey(*pubkey) || *signature == "" {
+			log.Fatal("missing required flags: --pubkey, --signature")
+		}
+		msg, err := readMessage(*message, *messageFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		valid, err := runVerifyMessage(strings.TrimSpace(*pubkey), strings.TrimSpace(*signature), msg, *raw)
+		if err != nil {
+			log.Fatalf("verify-message error: %v", err)
+		}
+		if !valid {
+			os.Exit(1)
+		}
+	case "pay":
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(1)
+		}
+		payCmd := flag.NewFlagSet("pay "+os.Args[2], flag.ExitOnError)
+		recipient := payCmd.String("recipient", "", "Recipient wallet (base58)")
+		amount := payCmd.String("amount", "", "Amount in SOL or token UI units, e.g. 0.25")
+		splToken := payCmd.String("spl-token", "", "SPL token mint (base58); default is SOL")
+		referenceAddr := payCmd.String("reference", "", "Reference key (base58); pay request generates one when omitted")
+		label := payCmd.String("label", "", "Merchant label shown by the wallet")
+		message := payCmd.String("message", "", "Message shown by the wallet")
+		memoText := payCmd.String("memo", "", "Memo the wallet attaches to the transfer")
+		qr := payCmd.Bool("qr", true, "Draw the URL as a QR code on stderr")
+		cluster := payCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+		rpc := payCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		_ = payCmd.Parse(os.Args[3:])
+		if !isValidBase58Pubkey(*recipient) {
+			log.Fatal("missing or invalid --recipient base58")
+		}
+		req := payRequest{
+			Recipient: common.PublicKeyFromString(strings.TrimSpace(*recipient)),
+			Amount:    strings.TrimSpace(*amount),
+			Label:     *label,
+			Message:   *message,
+			Memo:      *memoText,
+		}
+		if *splToken != "" {
+			if !isValidBase58Pubkey(*splToken) {
+				log.Fatal("invalid --spl-token base58")
+			}
+			mint := common.PublicKeyFromString(strings.TrimSpace(*splToken))
+			req.SPLToken = &mint
+		}
+		if *referenceAddr != "" {
+			if !isValidBase58Pubkey(*referenceAddr) {
+				log.Fatal("invalid --reference base58")
+			}
+			ref := common.PublicKeyFromString(strings.TrimSpace(*referenceAddr))
+			req.Reference = &ref
+		}
+		switch os.Args[2] {
+		case "request":
+			if err := runPayRequest(req, *qr, normalizeCluster(*cluster), strings.TrimSpace(*rpc)); err != nil {
+				log.Fatalf("pay request error: %v", err)
+			}
+		case "verify":
+			if req.Reference == nil || req.Amount == "" {
+				log.Fatal("missing required flags: --reference, --amount")
+			}
+			valid, err := runPayVerify(req, normalizeCluster(*cluster), strings.TrimSpace(*rpc))
+			if err != nil {
+				log.Fatalf("pay verify error: %v", err)
+			}
+			if !valid {
+				os.Exit(1)
+			}
+		default:
+			printUsage()
+			os.Exit(1)
+		}
+	case "faucet":
+		if len(os.Args) < 3 || os.Args[2] != "serve" {
+			printUsage()
+			os.Exit(1)
+		}
+		faucetCmd := flag.NewFlagSet("faucet serve", flag.ExitOnError)
+		keypair := faucetCmd.String("keypair", "", "Path to the funded treasury keypair JSON file")
+		listen := faucetCmd.String("listen", ":8080", "HTTP listen address")
+		maxLamports := faucetCmd.Uint64("max-lamports", lamportsPerSOL, "Largest single airdrop in lamports")
+		perAddress := faucetCmd.Int("per-address", 2, "Airdrops per recipient address per window")
+		perIP := faucetCmd.Int("per-ip", 5, "Requests per client IP per window")
+		window := faucetCmd.Duration("window", time.Hour, "Rate-limit window")
+		dailyCap := faucetCmd.Uint64("daily-cap", 20*lamportsPerSOL, "Lamports paid out per UTC day")
+		trustProxy := faucetCmd.Bool("trust-proxy", false, "Take the client IP from X-Forwarded-For")
+		auditLog := faucetCmd.String("audit-log", "faucet-audit.jsonl", "JSON lines audit log (appended)")
+		cluster := faucetCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+		rpc := faucetCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		_ = faucetCmd.Parse(os.Args[3:])
+		if *keypair == "" {
+			log.Fatal("missing required flag: --keypair")
+		}
+		if *perAddress < 1 || *perIP < 1 || *window <= 0 || *maxLamports == 0 {
+			log.Fatal("--per-address, --per-ip, --window and --max-lamports must be positive")
+		}
+		cfg := faucetConfig{
+			Listen:       *listen,
+			MaxLamports:  *maxLamports,
+			PerAddress:   *perAddress,
+			PerIP:        *perIP,
+			Window:       *window,
+			DailyCap:     *dailyCap,
+			TrustProxy:   *trustProxy,
+			AuditLogPath: *auditLog,
+		}
+		if err := runFaucetServe(*keypair, cfg, normalizeCluster(*cluster), strings.TrimSpace(*rpc)); err != nil {
+			log.Fatalf("faucet serve error: %v", err)
+		}
+	case "monitor":
+		monitorCmd := flag.NewFlagSet("monitor", flag.ExitOnError)
+		addrFile := monitorCmd.String("addresses", "", "File with one deposit address per line (first CSV column)")
+		webhook := monitorCmd.String("webhook", "", "URL that receives each deposit as a signed JSON POST")
+		secret := monitorCmd.String("secret", os.Getenv("MONITOR_WEBHOOK_SECRET"), "HMAC-SHA256 key for X-Signature (default: $MONITOR_WEBHOOK_SECRET)")
+		commitment := monitorCmd.String("commitment", "finalized", "Commitment a deposit must reach: confirmed|finalized")
+		interval := monitorCmd.Duration("interval", 15*time.Second, "Polling interval")
+		cursorPath := monitorCmd.String("cursor", "monitor-cursor.json", "File holding the last handled signature per account")
+		retries := monitorCmd.Int("retries", 5, "Webhook retries per deposit before the round is retried")
+		fromStart := monitorCmd.Bool("from-start", false, "Deliver past deposits of accounts that have no cursor yet")
+		cluster := monitorCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+		rpcURL := monitorCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		_ = monitorCmd.Parse(os.Args[2:])
+		if *addrFile == "" || *webhook == "" || *secret == "" {
+			log.Fatal("missing required flags: --addresses, --webhook, --secret")
+		}
+		if *commitment != string(rpc.CommitmentConfirmed) && *commitment != string(rpc.CommitmentFinalized) {
+			log.Fatal("--commitment must be confirmed or finalized")
+		}
+		addresses, err := parseAddresses("", *addrFile)
+		if err != nil {
+			log.Fatalf("invalid addresses: %v", err)
+		}
+		if len(addresses) == 0 {
+			log.Fatal("no addresses in --addresses")
+		}
+		cfg := monitorConfig{
+			Webhook:    *webhook,
+			Secret:     *secret,
+			Commitment: rpc.Commitment(*commitment),
+			Interval:   *interval,
+			CursorPath: *cursorPath,
+			Retries:    *retries,
+			FromStart:  *fromStart,
+		}
+		if err := runMonitor(addresses, cfg, normalizeCluster(*cluster), strings.TrimSpace(*rpcURL)); err != nil {
+			log.Fatalf("monitor error: %v", err)
+		}
+	case "serve":
+		serveCmd := flag.NewFlagSet("serve", flag.ExitOnError)
+		listen := serveCmd.String("listen", ":8080", "Listen address")
+		apiKeys := serveCmd.String("api-keys", os.Getenv("TRANSFER_API_KEYS"), "Comma-separated API keys (default: $TRANSFER_API_KEYS)")
+		fromPriv := serveCmd.String("from", "", "Signer private key (base58) for POST /transfer")
+		fromFile := serveCmd.String("fromFile", "", "Signer keypair JSON file for POST /transfer")
+		maxLamports := serveCmd.Uint64("max-lamports", 0, "Largest single POST /transfer in lamports (0 = no limit)")
+		cluster := serveCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+		rpcURL := serveCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		_ = serveCmd.Parse(os.Args[2:])
+		cfg := gatewayConfig{Listen: *listen, MaxLamports: *maxLamports}
+		for _, k := range strings.Split(*apiKeys, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				cfg.APIKeys = append(cfg.APIKeys, k)
+			}
+		}
+		if len(cfg.APIKeys) == 0 {
+			log.Fatal("missing --api-keys (or $TRANSFER_API_KEYS)")
+		}
+		if *fromPriv != "" || *fromFile != "" {
+			signer, err := loadSigner(*fromPriv, *fromFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			cfg.Signer = &signer
+		}
+		if err := runServe(cfg, normalizeCluster(*cluster), strings.TrimSpace(*rpcURL)); err != nil {
+			log.Fatalf("serve error: %v", err)
+		}
+	case "sweep":
+		sweepCmd := flag.NewFlagSet("sweep", flag.ExitOnError)
+		keysDir := sweepCmd.String("keys-dir", "", "Directory searched for keypair files (*.json, e.g. <name>/id.json)")
+		to := sweepCmd.String("to", "", "Treasury address (base58) receiving the funds")
+		closeAccounts := sweepCmd.Bool("close", false, "Sweep the whole balance, closing the accounts instead of leaving the rent-exempt minimum")
+		feePayerPath := sweepCmd.String("fee-payer", "", "Keypair file paying all fees (default: the richest key of each batch)")
+		dryRun := sweepCmd.Bool("dry-run", false, "Report what would be swept without sending")
+		cluster := sweepCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+		rpcURL := sweepCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		_ = sweepCmd.Parse(os.Args[2:])
+		if *keysDir == "" || *to == "" {
+			log.Fatal("missing required flags: --keys-dir, --to")
+		}
+		if !isValidBase58Pubkey(*to) {
+			log.Fatal("invalid --to address")
+		}
+		var feePayer *types.Account
+		if *feePayerPath != "" {
+			acc, err := loadAccountFromFile(*feePayerPath)
+			if err != nil {
+				log.Fatalf("failed to load --fee-payer: %v", err)
+			}
+			feePayer = &acc
+		}
+		failed, err := runSweep(*keysDir, common.PublicKeyFromString(*to), feePayer, *closeAccounts, *dryRun, normalizeCluster(*cluster), strings.TrimSpace(*rpcURL))
+		if err != nil {
+			log.Fatalf("sweep error: %v", err)
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+	case "export":
+		exportCmd := flag.NewFlagSet("export", flag.ExitOnError)
+		address := exportCmd.String("address", "", "Wallet address (base58); its SPL token accounts are included")
+		fromDate := exportCmd.String("from-date", "", "First day to export, YYYY-MM-DD (UTC, inclusive)")
+		toDate := exportCmd.String("to-date", "", "Last day to export, YYYY-MM-DD (UTC, inclusive)")
+		format := exportCmd.String("format", "csv", "Output format: csv|json")
+		out := exportCmd.String("out", "", "Write to this file instead of stdout")
+		cluster := exportCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+		rpcURL := exportCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		_ = exportCmd.Parse(os.Args[2:])
+		if *address == "" || *fromDate == "" || *toDate == "" {
+			log.Fatal("missing required flags: --address, --from-date, --to-date")
+		}
+		if !isValidBase58Pubkey(*address) {
+			log.Fatal("invalid --address")
+		}
+		from, err := parseExportDate(*fromDate)
+		if err != nil {
+			log.Fatal(err)
+		}
+		to, err := parseExportDate(*toDate)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if to.Before(from) {
+			log.Fatal("--to-date is before --from-date")
+		}
+		w := os.Stdout
+		if *out != "" {
+			if w, err = os.Create(*out); err != nil {
+				log.Fatalf("failed to create %s: %v", *out, err)
+			}
+		}
+		if err := runExport(*address, from, to.AddDate(0, 0, 1), *format, w, normalizeCluster(*cluster), strings.TrimSpace(*rpcURL)); err != nil {
+			log.Fatalf("export error: %v", err)
+		}
+		if *out != "" {
+			if err := w.Close(); err != nil {
+				log.Fatalf("failed to write %s: %v", *out, err)
+			}
+		}
+	case "pda":
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(1)
+		}
+		switch os.Args[2] {
+		case "derive":
+			deriveCmd := flag.NewFlagSet("pda derive", flag.ExitOnError)
+			program := deriveCmd.String("program", "", "Program id (base58) or known program name: chain|favorite|voting")
+			var seeds seedFlags
+			deriveCmd.Var(&seeds, "seed", "Seed as kind:value, repeatable (str, pubkey, u8, u16le, u32le, u64le, i64le, hex)")
+			_ = deriveCmd.Parse(os.Args[3:])
+			if *program == "" {
+				log.Fatal("missing --program")
+			}
+			if err := runPDADerive(strings.TrimSpace(*program), seeds); err != nil {
+				log.Fatalf("pda derive error: %v", err)
+			}
+		case "find":
+			findCmd := flag.NewFlagSet("pda find", flag.ExitOnError)
+			addr := findCmd.String("address", "", "Address to explain (base58)")
+			program := findCmd.String("program", "", "Only search this program's templates: chain|favorite|voting")
+			pubkeys := findCmd.String("pubkey", "", "Comma separated candidate pubkeys for pubkey seeds (user, payer, ...)")
+			strs := findCmd.String("string", "", "Comma separated candidate strings for string seeds (candidate names, ...)")
+			maxInt := findCmd.Uint64("max-int", 1000, "Integer seeds (poll ids, ...) are tried over 0..max-int")
+			_ = findCmd.Parse(os.Args[3:])
+			if !isValidBase58Pubkey(*addr) {
+				log.Fatal("missing or invalid --address base58")
+			}
+			if *maxInt > 1_000_000 {
+				log.Fatal("--max-int is limited to 1000000")
+			}
+			var keys []common.PublicKey
+			for _, pk := range strings.Split(*pubkeys, ",") {
+				if pk = strings.TrimSpace(pk); pk == "" {
+					continue
+				}
+				if !isValidBase58Pubkey(pk) {
+					log.Fatalf("invalid --pubkey %q", pk)
+				}
+				keys = append(keys, common.PublicKeyFromString(pk))
+			}
+			var names []string
+			for _, v := range strings.Split(*strs, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					names = append(names, v)
+				}
+			}
+			if err := runPDAFind(strings.TrimSpace(*addr), strings.TrimSpace(*program), keys, names, *maxInt); err != nil {
+				log.Fatalf("pda find error: %v", err)
+			}
+		default:
+			printUsage()
+			os.Exit(1)
+		}
+	case "reclaim":
+		reclaimCmd := flag.NewFlagSet("reclaim", flag.ExitOnError)
+		fromPriv := reclaimCmd.String("from", "", "Owner private key (base58)")
+		fromFile := reclaimCmd.String("fromFile", "", "Path to owner keypair JSON file (id.json)")
+		cluster := reclaimCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+		rpc := reclaimCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		_ = reclaimCmd.Parse(os.Args[2:])
+		if *fromPriv == "" && *fromFile == "" {
+			log.Fatal("missing required flags: --from or --fromFile")
+		}
+		if err := runReclaim(*fromPriv, *fromFile, normalizeCluster(*cluster), strings.TrimSpace(*rpc)); err != nil {
+			log.Fatalf("reclaim error: %v", err)
+		}
+	case "rent":
+		rentCmd := flag.NewFlagSet("rent", flag.ExitOnError)
+		program := rentCmd.String("program", "", "Program whose accounts to size: favorite|voting")
+		account := rentCmd.String("account", "", "Only this account type, e.g. Favorite, Poll, CandidateAccount")
+		space := rentCmd.Int64("space", -1, "Raw account size in bytes instead of an Anchor account")
+		cluster := rentCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+		rpc := rentCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		_ = rentCmd.Parse(os.Args[2:])
+		if *program == "" && *space < 0 {
+			log.Fatal("missing required flags: --program or --space")
+		}
+		if err := runRent(strings.TrimSpace(*program), strings.TrimSpace(*account), *space, normalizeCluster(*cluster), strings.TrimSpace(*rpc)); err != nil {
+			log.Fatalf("rent error: %v", err)
+		}
+	case "batch-transfer":
+		batchCmd := flag.NewFlagSet("batch-transfer", flag.ExitOnError)
+		fromPriv := batchCmd.String("from", "", "Sender private key (base58)")
+		fromFile := batchCmd.String("fromFile", "", "Path to Solana keypair JSON file (id.json)")
+		recipients := batchCmd.String("recipients", "", "CSV file of address,lamports lines")
+		tables := batchCmd.String("lookup-table", "", "Comma separated lookup table addresses (sends v0 transactions)")
+		dryRun := batchCmd.Bool("dry-run", false, "Only print how the transfers would be packed")
+		cluster := batchCmd.String("cluster", "devnet", "Cluster: devnet|testnet|mainnet|local")
+		rpc := batchCmd.String("rpc", "", "Custom RPC endpoint URL (override)")
+		_ = batchCmd.Parse(os.Args[2:])
+		if (*fromPriv == "" && *fromFile == "") || *recipients == "" {
+			log.Fatal("missing required flags: --from or --fromFile, --recipients")
+		}
+		if err := runBatchTransfer(*fromPriv, *fromFile, *recipients, *tables, *dryRun, normalizeCluster(*cluster), strings.TrimSpace(*rpc)); err != nil {
+			fatalTxError("batch-transfer error", err)
+		}
 	default:
 		printUsage()
 		os.Exit(1)
 	}
 }
 
-func runBalance(address string, cluster string, rpcOverride string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+// balanceResult is the output of `balance` and GET /balance/{address}.
+type balanceResult struct {
+	Address  string  `json:"address"`
+	Cluster  string  `json:"cluster"`
+	Lamports uint64  `json:"lamports"`
+	SOL      float64 `json:"sol"`
+	Tokens   any     `json:"tokens,omitempty"` // []tokenBalance; an interface so an empty list is still printed
+}
+
+// fetchBalance returns the SOL balance and, with tokens set, every SPL token balance.
+func fetchBalance(ctx context.Context, c *client.Client, cluster, address string, tokens bool) (balanceResult, error) {
 	bal, err := c.GetBalance(ctx, address)
 	if err != nil {
-		return fmt.Errorf("failed to get balance: %w", err)
+		return balanceResult{}, fmt.Errorf("failed to get balance: %w", err)
 	}
 	lam := uint64(bal)
 	sol := new(big.Float).Quo(new(big.Float).SetUint64(lam), new(big.Float).SetUint64(lamportsPerSOL))
 	solF, _ := sol.Float64()
 
-	out := map[string]any{
-		"address":  address,
-		"cluster":  cluster,
-		"lamports": lam,
-		"sol":      solF,
+	out := balanceResult{Address: address, Cluster: cluster, Lamports: lam, SOL: solF}
+	if tokens {
+		balances, err := tokenBalances(ctx, c, common.PublicKeyFromString(address))
+		if err != nil {
+			return balanceResult{}, err
+		}
+		out.Tokens = balances
+	}
+	return out, nil
+}
+
+// runBalance prints the SOL balance and, with tokens set, every SPL token balance.
+func runBalance(address string, tokens bool, cluster string, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := fetchBalance(ctx, client.NewClient(resolveEndpoint(cluster, rpcOverride)), cluster, address, tokens)
+	if err != nil {
+		return err
 	}
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	return enc.Encode(out)
 }
 
-func runTransfer(fromPrivBase58, fromFilePath, toAddrBase58 string, amountLamports uint64, cluster string, rpcOverride string) error {
+// transferResult is the output of `transfer` and POST /transfer.
+type transferResult struct {
+	Amount    uint64 `json:"amount"`
+	Blockhash string `json:"blockhash"`
+	Cluster   string `json:"cluster"`
+	From      string `json:"from"`
+	Memo      string `json:"memo,omitempty"`
+	Reference string `json:"reference,omitempty"`
+	To        string `json:"to"`
+	TxHash    string `json:"txhash"`
+}
+
+// sendTransfer sends SOL from the signer without waiting for confirmation; a
+// non-nil reference and a memo tag the transfer so `pay verify` can find it
+// (Solana Pay).
+func sendTransfer(ctx context.Context, c *client.Client, cluster string, from types.Account, to common.PublicKey, amountLamports uint64, reference *common.PublicKey, memoText string) (transferResult, error) {
+	b := txbuilder.New(c).FeePayer(from).Add(transferInstructions(from.PublicKey, to, amountLamports, reference, memoText)...)
+	txhash, err := b.Send(ctx)
+	if err != nil {
+		return transferResult{}, err
+	}
+	out := transferResult{
+		Amount:    amountLamports,
+		Blockhash: b.RecentBlockhash(),
+		Cluster:   cluster,
+		From:      from.PublicKey.ToBase58(),
+		Memo:      memoText,
+		To:        to.ToBase58(),
+		TxHash:    txhash,
+	}
+	if reference != nil {
+		out.Reference = reference.ToBase58()
+	}
+	return out, nil
+}
+
+// runTransfer sends SOL and prints the result; see sendTransfer.
+func runTransfer(fromPrivBase58, fromFilePath, toAddrBase58 string, amountLamports uint64, reference *common.PublicKey, memoText string, cluster string, rpcOverride string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
 	defer cancel()
-	var from types.Account
-	var err error
-	if strings.TrimSpace(fromPrivBase58) != "" {
-		from, err = types.AccountFromBase58(strings.TrimSpace(fromPrivBase58))
-		if err != nil {
-			return fmt.Errorf("invalid sender private key: %w", err)
-		}
-	} else {
-		from, err = loadAccountFromFile(strings.TrimSpace(fromFilePath))
-		if err != nil {
-			return fmt.Errorf("failed to load sender from file: %w", err)
-		}
+	from, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
 	}
 	// validate recipient and parse
 	if !isValidBase58Pubkey(toAddrBase58) {
@@ -130,70 +768,76 @@ func runTransfer(fromPrivBase58, fromFilePath, toAddrBase58 string, amountLampor
 	}
 	to := common.PublicKeyFromString(strings.TrimSpace(toAddrBase58))
 	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
-	latest, err := c.GetLatestBlockhash(ctx)
+
+	out, err := sendTransfer(ctx, c, cluster, from, to, amountLamports, reference, memoText)
 	if err != nil {
-		return fmt.Errorf("failed to get latest blockhash: %w", err)
+		return err
 	}
-	recent := latest.Blockhash
-
-	msg := types.NewMessage(types.NewMessageParam{
-		FeePayer:        from.PublicKey,
-		RecentBlockhash: recent,
-		Instructions: []types.Instruction{
-			sysprog.Transfer(sysprog.TransferParam{
-				From:   from.PublicKey,
-				To:     to,
-				Amount: amountLamports,
-			}),
-		},
-	})
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
 
-	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Message: msg,
-		Signers: []types.Account{from},
+// transferInstructions builds a system transfer. As Solana Pay requires, the
+// memo instruction comes first and the reference is appended to the transfer
+// as a read-only, non-signer account.
+func transferInstructions(from, to common.PublicKey, lamports uint64, reference *common.PublicKey, memoText string) []types.Instruction {
+	ix := sysprog.Transfer(sysprog.TransferParam{
+		From:   from,
+		To:     to,
+		Amount: lamports,
 	})
-	if err != nil {
-		return fmt.Errorf("failed to build transaction: %w", err)
+	if reference != nil {
+		ix.Accounts = append(ix.Accounts, types.AccountMeta{PubKey: *reference})
 	}
-
-	txhash, err := c.SendTransaction(ctx, tx)
-	if err != nil {
-		return fmt.Errorf("failed to send transaction: %w", err)
+	if memoText == "" {
+		return []types.Instruction{ix}
 	}
+	return []types.Instruction{memo.BuildMemo(memo.BuildMemoParam{Memo: []byte(memoText)}), ix}
+}
+
+// airdropResult is the output of `airdrop` and POST /airdrop.
+type airdropResult struct {
+	Cluster  string `json:"cluster"`
+	Lamports uint64 `json:"lamports"`
+	To       string `json:"to"`
+	TxHash   string `json:"txhash"`
+}
 
-	out := map[string]any{
-		"cluster":   cluster,
-		"blockhash": recent,
-		"txhash":    txhash,
-		"amount":    amountLamports,
-		"from":      from.PublicKey.ToBase58(),
-		"to":        to.ToBase58(),
+// requestAirdrop asks the cluster faucet for lamports (devnet/testnet/local only).
+func requestAirdrop(ctx context.Context, c *client.Client, cluster, to string, lamports uint64) (airdropResult, error) {
+	txhash, err := c.RequestAirdrop(ctx, to, lamports)
+	if err != nil {
+		return airdropResult{}, fmt.Errorf("failed to request airdrop: %w", err)
 	}
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(out)
+	return airdropResult{Cluster: cluster, Lamports: lamports, To: to, TxHash: txhash}, nil
 }
 
 func runAirdrop(toAddrBase58 string, lamports uint64, cluster string, rpcOverride string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
 	to := strings.TrimSpace(toAddrBase58)
-	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
-	txhash, err := c.RequestAirdrop(ctx, to, lamports)
+	out, err := requestAirdrop(ctx, client.NewClient(resolveEndpoint(cluster, rpcOverride)), cluster, to, lamports)
 	if err != nil {
-		return fmt.Errorf("failed to request airdrop: %w", err)
-	}
-	out := map[string]any{
-		"cluster":  cluster,
-		"txhash":   txhash,
-		"lamports": lamports,
-		"to":       to,
+		return err
 	}
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	return enc.Encode(out)
 }
 
+// fatalTxError exits, printing decoded instruction errors as structured JSON on stderr
+func fatalTxError(prefix string, err error) {
+	var pe *anchor.ProgramError
+	if errors.As(err, &pe) {
+		enc := json.NewEncoder(os.Stderr)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(map[string]any{"error": prefix, "programError": pe})
+		os.Exit(1)
+	}
+	log.Fatalf("%s: %v", prefix, err)
+}
+
 func endpointFor(cluster string) string {
 	switch cluster {
 	case "mainnet", "mainnet-beta":
@@ -228,6 +872,22 @@ func normalizeCluster(c string) string {
 	}
 }
 
+// loadSigner loads the signer from a base58 private key, falling back to a keypair file.
+func loadSigner(privBase58, filePath string) (types.Account, error) {
+	if strings.TrimSpace(privBase58) != "" {
+		acc, err := types.AccountFromBase58(strings.TrimSpace(privBase58))
+		if err != nil {
+			return types.Account{}, fmt.Errorf("invalid sender private key: %w", err)
+		}
+		return acc, nil
+	}
+	acc, err := loadAccountFromFile(strings.TrimSpace(filePath))
+	if err != nil {
+		return types.Account{}, fmt.Errorf("failed to load sender from file: %w", err)
+	}
+	return acc, nil
+}
+
 func loadAccountFromFile(path string) (types.Account, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -247,13 +907,82 @@ func loadAccountFromFile(path string) (types.Account, error) {
 func printUsage() {
 	fmt.Println(`Usage:
   Balance:
-    go run main.go balance --address <base58> [--cluster devnet|testnet|mainnet|local] [--rpc <url>]
+    go run main.go balance --address <base58> [--tokens] [--cluster devnet|testnet|mainnet|local] [--rpc <url>]
 
   Transfer SOL:
-    go run main.go transfer (--from <privateKeyBase58> | --fromFile ~/.config/solana/id.json) --to <addressBase58> --lamports <amount> [--cluster devnet|testnet|mainnet|local] [--rpc <url>]
+    go run main.go transfer (--from <privateKeyBase58> | --fromFile ~/.config/solana/id.json) --to <addressBase58> --lamports <amount> [--reference <base58>] [--memo <text>] [--cluster devnet|testnet|mainnet|local] [--rpc <url>]
 
   Airdrop (devnet/local only):
-    go run main.go airdrop --to <addressBase58> [--lamports 1000000000] [--cluster local|devnet] [--rpc <url>]`)
+    go run main.go airdrop --to <addressBase58> [--lamports 1000000000] [--cluster local|devnet] [--rpc <url>]
+
+  Create wallet PDA (chain program, seeds = ["wallet", payer]):
+    go run . wallet create (--from <privateKeyBase58> | --fromFile ~/.config/solana/id.json) [--seed <text>] [--lamports <amount>] [--lookup-table <table,...>] [--cluster ...] [--rpc <url>]
+
+  Query wallet balance via get_balance / BalanceEvent:
+    go run . wallet balance (--from <privateKeyBase58> | --fromFile ~/.config/solana/id.json) [--wallet <addressBase58>] [--simulate] [--lookup-table <table,...>] [--cluster ...] [--rpc <url>]
+
+  Address lookup tables (addresses become usable one slot after they are added):
+    go run . alt create (--from <privateKeyBase58> | --fromFile ~/.config/solana/id.json) [--addresses <a,b,...>] [--addresses-file <path>] [--cluster ...] [--rpc <url>]
+    go run . alt extend (--from ... | --fromFile ...) --table <addressBase58> (--addresses <a,b,...> | --addresses-file <path>) [--cluster ...] [--rpc <url>]
+    go run . alt show --table <addressBase58> [--cluster ...] [--rpc <url>]
+
+  SPL token mints (amounts are in UI units, e.g. 1.5):
+    go run . token create-mint (--from ... | --fromFile ...) [--decimals 9] [--no-freeze] [--cluster ...] [--rpc <url>]
+    go run . token mint-to (--from ... | --fromFile ...) --mint <mint> --amount <n> [--to <ownerWallet>] [--cluster ...] [--rpc <url>]
+    go run . token burn (--from ... | --fromFile ...) --mint <mint> --amount <n> [--account <tokenAccount>] [--cluster ...] [--rpc <url>]
+    go run . token accounts --owner <wallet> [--cluster ...] [--rpc <url>]
+    go run . token set-authority (--from ... | --fromFile ...) (--mint <mint> | --account <tokenAccount>) --type mint|freeze|owner|close [--new-authority <base58>] [--cluster ...] [--rpc <url>]
+
+  Native staking (the stake account address is derived from the signer and --seed):
+    go run . stake create (--from ... | --fromFile ...) --amount <sol> --validator <voteAccount> [--seed <text>] [--cluster ...] [--rpc <url>]
+    go run . stake deactivate (--from ... | --fromFile ...) --stake <stakeAccount> [--cluster ...] [--rpc <url>]
+    go run . stake withdraw (--from ... | --fromFile ...) --stake <stakeAccount> [--to <address>] [--lamports <n>] [--cluster ...] [--rpc <url>]
+    go run . stake show --stake <stakeAccount> [--epochs 5] [--cluster ...] [--rpc <url>]
+
+  Sign / verify an off-chain message (Solana off-chain envelope by default, --raw for plain ed25519;
+  verify-message exits 1 when the signature is invalid):
+    go run . sign-message (--from <privateKeyBase58> | --signer ~/.config/solana/id.json) (--message <text> | --message-file <path>) [--raw]
+    go run . verify-message --pubkey <base58> --signature <base58> (--message <text> | --message-file <path>) [--raw]
+
+  Solana Pay transfer requests (pay a request with: transfer --reference <ref> [--memo <text>]):
+    go run . pay request --recipient <wallet> [--amount <n>] [--spl-token <mint>] [--reference <base58>] [--label <text>] [--message <text>] [--memo <text>] [--qr=false]
+    go run . pay verify --reference <base58> --recipient <wallet> --amount <n> [--spl-token <mint>] [--memo <text>] [--cluster ...] [--rpc <url>]
+
+  Local faucet: POST /airdrop {"address": "...", "lamports": n} pays from the treasury; GET /status shows limits:
+    go run . faucet serve --keypair treasury.json [--listen :8080] [--max-lamports 1000000000] [--per-address 2] [--per-ip 5] [--window 1h] [--daily-cap 20000000000] [--trust-proxy] [--audit-log faucet-audit.jsonl] [--cluster ...] [--rpc <url>]
+
+  Watch deposit addresses (SOL and SPL) and POST each deposit to a webhook with Idempotency-Key and
+  X-Signature = hex(HMAC-SHA256(secret, X-Timestamp + "." + body)); the cursor file makes restarts resume exactly:
+    go run . monitor --addresses deposits.txt --webhook <url> --secret <key> [--commitment finalized] [--interval 15s] [--cursor monitor-cursor.json] [--retries 5] [--from-start] [--cluster ...] [--rpc <url>]
+
+  REST/JSON gateway: GET /balance/{address}[?tokens=true], POST /transfer {"to","lamports","reference"?,"memo"?},
+  POST /airdrop {"to","lamports"?}, GET /tx/{sig}; send X-API-Key or "Authorization: Bearer <key>".
+  The OpenAPI document is served unauthenticated at GET /openapi.json. Without a signer POST /transfer returns 503:
+    go run . serve --api-keys <k1,k2> [--listen :8080] [--from <privateKeyBase58> | --fromFile id.json] [--max-lamports <n>] [--cluster ...] [--rpc <url>]
+
+  Sweep the SOL of every keypair under a directory into a treasury, several transfers per transaction
+  (keeps the rent-exempt minimum unless --close; exits 1 if any key failed):
+    go run . sweep --keys-dir ./deposits --to <treasury> [--close] [--fee-payer treasury.json] [--dry-run] [--cluster ...] [--rpc <url>]
+
+  Export a ledger of every SOL and SPL balance change of a wallet (fees, counterparties and running balances),
+  ordered by slot and ledger order within the slot so repeated exports diff cleanly:
+    go run . export --address <base58> --from-date 2026-09-01 --to-date 2026-09-30 [--format csv|json] [--out ledger.csv] [--cluster ...] [--rpc <url>]
+
+  Derive a PDA (prints address and bump):
+    go run . pda derive --program <programId|chain|favorite|voting> --seed str:favorites --seed pubkey:<base58> [--seed u64le:7 ...]
+
+  Find which known seed template produces an address:
+    go run . pda find --address <base58> [--program chain|favorite|voting] [--pubkey <a,b,...>] [--string <name,...>] [--max-int 1000]
+
+  List the signer's closeable PDAs (favorites and named profiles, and polls it is the authority of with their candidates) and the rent closing them recovers:
+    go run . reclaim (--from <privateKeyBase58> | --fromFile ~/.config/solana/id.json) [--cluster ...] [--rpc <url>]
+
+  Account size and rent-exempt minimum (lamports to hold before initialize):
+    go run . rent --program favorite|voting [--account <AccountType>] [--cluster ...] [--rpc <url>]
+    go run . rent --space <bytes> [--cluster ...] [--rpc <url>]
+
+  Batch transfer from a CSV of address,lamports (v0 transactions when lookup tables are given):
+    go run . batch-transfer (--from ... | --fromFile ...) --recipients payouts.csv [--lookup-table <table,...>] [--dry-run] [--cluster ...] [--rpc <url>]`)
 }
 
 func isValidBase58Pubkey(s string) bool {
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/banksim"
+	"sdk/txbuilder"
+)
+
+// newTestBank starts an RPC server backed by a fresh bank simulator.
+func newTestBank(t *testing.T) (*banksim.Bank, *httptest.Server, *client.Client) {
+	t.Helper()
+	bank := banksim.New(banksim.Config{})
+	srv := httptest.NewServer(bank.Handler())
+	t.Cleanup(srv.Close)
+	return bank, srv, client.NewClient(srv.URL)
+}
+
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestFetchBalance(t *testing.T) {
+	bank, _, c := newTestBank(t)
+	ctx := testContext(t)
+	acc := types.NewAccount()
+	bank.Fund(acc.PublicKey, 1_500_000_000)
+
+	out, err := fetchBalance(ctx, c, "local", acc.PublicKey.ToBase58(), false)
+	if err != nil {
+		t.Fatalf("fetchBalance: %v", err)
+	}
+	if out.Lamports != 1_500_000_000 || out.SOL != 1.5 {
+		t.Fatalf("got %d lamports (%v SOL), want 1500000000 (1.5 SOL)", out.Lamports, out.SOL)
+	}
+	if out.Address != acc.PublicKey.ToBase58() || out.Cluster != "local" || out.Tokens != nil {
+		t.Fatalf("unexpected result %+v", out)
+	}
+}
+
+func TestSendTransfer(t *testing.T) {
+	bank, _, c := newTestBank(t)
+	ctx := testContext(t)
+	from, to, ref := types.NewAccount(), types.NewAccount(), types.NewAccount()
+	bank.Fund(from.PublicKey, 1_000_000_000)
+
+	out, err := sendTransfer(ctx, c, "local", from, to.PublicKey, 250_000_000, &ref.PublicKey, "order-42")
+	if err != nil {
+		t.Fatalf("sendTransfer: %v", err)
+	}
+	if out.TxHash == "" || out.Blockhash == "" || out.Reference != ref.PublicKey.ToBase58() || out.Memo != "order-42" {
+		t.Fatalf("unexpected result %+v", out)
+	}
+	if _, txErr, ok := bank.SignatureStatus(out.TxHash); !ok || txErr != nil {
+		t.Fatalf("status of %s: ok=%v err=%v", out.TxHash, ok, txErr)
+	}
+	if got := bank.Balance(to.PublicKey); got != 250_000_000 {
+		t.Fatalf("recipient has %d lamports, want 250000000", got)
+	}
+	if got, want := bank.Balance(from.PublicKey), uint64(1_000_000_000-250_000_000-banksim.DefaultLamportsPerSignature); got != want {
+		t.Fatalf("sender has %d lamports, want %d", got, want)
+	}
+}
+
+func TestSendTransferBadSignature(t *testing.T) {
+	bank, _, c := newTestBank(t)
+	ctx := testContext(t)
+	from, other, to := types.NewAccount(), types.NewAccount(), types.NewAccount()
+	bank.Fund(from.PublicKey, 1_000_000_000)
+
+	// The fee payer's address with another key's private key signs a
+	// signature that does not verify.
+	forged := types.Account{PublicKey: from.PublicKey, PrivateKey: other.PrivateKey}
+	_, err := sendTransfer(ctx, c, "local", forged, to.PublicKey, 1_000, nil, "")
+	if err == nil {
+		t.Fatal("sendTransfer with a bad signature succeeded")
+	}
+	if !strings.Contains(err.Error(), "signature verification failure") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := bank.Balance(from.PublicKey); got != 1_000_000_000 {
+		t.Fatalf("sender was charged: %d lamports left", got)
+	}
+}
+
+func TestSendTransferExpiredBlockhash(t *testing.T) {
+	bank, _, c := newTestBank(t)
+	ctx := testContext(t)
+	from, to := types.NewAccount(), types.NewAccount()
+	bank.Fund(from.PublicKey, 1_000_000_000)
+
+	stale, _ := bank.LatestBlockhash()
+	bank.Advance(banksim.DefaultMaxBlockhashAge + 1)
+	_, err := txbuilder.New(c).
+		FeePayer(from).
+		BlockhashFrom(txbuilder.FixedBlockhash(stale)).
+		Add(transferInstructions(from.PublicKey, to.PublicKey, 1_000, nil, "")...).
+		Send(ctx)
+	if err == nil {
+		t.Fatal("transfer with an expired blockhash succeeded")
+	}
+	if !strings.Contains(err.Error(), "BlockhashNotFound") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := bank.Balance(to.PublicKey); got != 0 {
+		t.Fatalf("recipient received %d lamports", got)
+	}
+}
+
+func TestRequestAirdrop(t *testing.T) {
+	bank, _, c := newTestBank(t)
+	ctx := testContext(t)
+	to := types.NewAccount().PublicKey
+
+	out, err := requestAirdrop(ctx, c, "local", to.ToBase58(), 2_000_000_000)
+	if err != nil {
+		t.Fatalf("requestAirdrop: %v", err)
+	}
+	if out.TxHash == "" || out.Lamports != 2_000_000_000 || out.To != to.ToBase58() {
+		t.Fatalf("unexpected result %+v", out)
+	}
+	if got := bank.Balance(to); got != 2_000_000_000 {
+		t.Fatalf("recipient has %d lamports, want 2000000000", got)
+	}
+}
+
+func TestRequestAirdropInvalidAddress(t *testing.T) {
+	_, _, c := newTestBank(t)
+	if _, err := requestAirdrop(testContext(t), c, "local", "not-an-address", 1); err == nil {
+		t.Fatal("airdrop to an invalid address succeeded")
+	}
+}
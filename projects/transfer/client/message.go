@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/mr-tron/base58"
+
+	"sdk/offchain"
+)
+
+// readMessage returns the --message text or the contents of --message-file.
+func readMessage(message, messageFile string) ([]byte, error) {
+	switch {
+	case message != "" && messageFile != "":
+		return nil, errors.New("use either --message or --message-file")
+	case messageFile != "":
+		return os.ReadFile(messageFile)
+	case message != "":
+		return []byte(message), nil
+	}
+	return nil, errors.New("missing --message or --message-file")
+}
+
+// messageFormat names what was signed: the raw bytes or the off-chain envelope.
+func messageFormat(msg []byte, raw bool) string {
+	if raw {
+		return "raw"
+	}
+	f, _ := offchain.Detect(msg)
+	return "offchain-" + f.String()
+}
+
+// runSignMessage signs msg with the signer, wrapped in the Solana off-chain
+// message envelope unless raw is set, and prints the base58 signature.
+func runSignMessage(fromPrivBase58, fromFilePath string, msg []byte, raw bool) error {
+	signer, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	var sig []byte
+	if raw {
+		sig = signer.Sign(msg)
+	} else if sig, err = offchain.Sign(signer, msg); err != nil {
+		return err
+	}
+
+	out := map[string]any{
+		"pubkey":    signer.PublicKey.ToBase58(),
+		"signature": base58.Encode(sig),
+		"format":    messageFormat(msg, raw),
+		"bytes":     len(msg),
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// runVerifyMessage checks a base58 signature over msg and prints the result.
+// It reports whether the signature is valid so the caller can set the exit code.
+func runVerifyMessage(pubkeyBase58, signatureBase58 string, msg []byte, raw bool) (bool, error) {
+	sig, err := base58.Decode(signatureBase58)
+	if err != nil {
+		return false, fmt.Errorf("invalid --signature base58: %w", err)
+	}
+	pubkey := common.PublicKeyFromString(pubkeyBase58)
+	var valid bool
+	if raw {
+		valid = offchain.VerifyRaw(pubkey, msg, sig)
+	} else if valid, err = offchain.Verify(pubkey, msg, sig); err != nil {
+		return false, err
+	}
+
+	out := map[string]any{
+		"pubkey": pubkey.ToBase58(),
+		"format": messageFormat(msg, raw),
+		"valid":  valid,
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return valid, enc.Encode(out)
+}
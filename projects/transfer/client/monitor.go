@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+)
+
+// monitorConfig holds the `monitor` settings.
+type monitorConfig struct {
+	Webhook    string
+	Secret     string // HMAC-SHA256 key for the X-Signature header
+	Commitment rpc.Commitment
+	Interval   time.Duration
+	CursorPath string
+	Retries    int
+	FromStart  bool // deliver history instead of starting at the current head
+}
+
+// deposit is the webhook payload for one incoming transfer.
+type deposit struct {
+	// ID is stable across restarts and retries; it is also sent as the
+	// Idempotency-Key header so the receiver can drop duplicates.
+	ID         string `json:"id"`
+	Type       string `json:"type"` // sol | spl
+	Address    string `json:"address"`
+	Account    string `json:"account"` // the address itself or its token account
+	Mint       string `json:"mint,omitempty"`
+	Amount     uint64 `json:"amount"`
+	Decimals   uint8  `json:"decimals"`
+	UIAmount   string `json:"uiAmount"`
+	Signature  string `json:"signature"`
+	Slot       uint64 `json:"slot"`
+	BlockTime  *int64 `json:"blockTime,omitempty"`
+	Commitment string `json:"commitment"`
+}
+
+// watchedAccount is a deposit address or one of its SPL token accounts.
+type watchedAccount struct {
+	Owner   common.PublicKey
+	Account common.PublicKey
+	Token   bool
+}
+
+// monitorCursor maps each watched account to the newest signature already
+// handled. It is rewritten after every transaction, so a restart resumes
+// exactly where the last run stopped.
+type monitorCursor map[string]string
+
+func loadCursor(path string) (monitorCursor, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return monitorCursor{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cur := monitorCursor{}
+	if err := json.Unmarshal(data, &cur); err != nil {
+		return nil, fmt.Errorf("invalid cursor file %s: %w", path, err)
+	}
+	return cur, nil
+}
+
+// save writes the cursor atomically so a crash never leaves it half written.
+func (cur monitorCursor) save(path string) error {
+	data, err := json.MarshalIndent(cur, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".cursor-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+type monitor struct {
+	cfg    monitorConfig
+	c      *client.Client
+	http   *http.Client
+	owners []common.PublicKey
+	cursor monitorCursor
+}
+
+// runMonitor polls the deposit addresses and their token accounts until
+// interrupted, posting each incoming transfer to the webhook.
+func runMonitor(addresses []common.PublicKey, cfg monitorConfig, cluster, rpcOverride string) error {
+	cursor, err := loadCursor(cfg.CursorPath)
+	if err != nil {
+		return err
+	}
+	m := &monitor{
+		cfg:    cfg,
+		c:      client.NewClient(resolveEndpoint(cluster, rpcOverride)),
+		http:   &http.Client{Timeout: 15 * time.Second},
+		owners: addresses,
+		cursor: cursor,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	log.Printf("monitor: watching %d addresses on %s at %s commitment", len(addresses), cluster, cfg.Commitment)
+	for {
+		if err := m.poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// The cursor only moves past delivered deposits; the next round retries.
+			log.Printf("monitor: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(cfg.Interval):
+		}
+	}
+}
+
+// poll handles every new transaction of every watched account.
+func (m *monitor) poll(ctx context.Context) error {
+	accounts, err := m.watchedAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	// Owners with a cursor from an earlier round (or an earlier run) have been
+	// watched before, so any token account of theirs without a cursor was
+	// opened since, possibly while the monitor was down, and its first
+	// transaction may be a deposit.
+	known := map[common.PublicKey]bool{}
+	for _, owner := range m.owners {
+		_, known[owner] = m.cursor[owner.ToBase58()]
+	}
+	for _, w := range accounts {
+		if err := m.pollAccount(ctx, w, w.Token && known[w.Owner]); err != nil {
+			return fmt.Errorf("%s: %w", w.Account.ToBase58(), err)
+		}
+	}
+	return nil
+}
+
+// watchedAccounts lists the deposit addresses plus their current token
+// accounts, so token accounts opened after startup are picked up.
+func (m *monitor) watchedAccounts(ctx context.Context) ([]watchedAccount, error) {
+	var out []watchedAccount
+	for _, owner := range m.owners {
+		out = append(out, watchedAccount{Owner: owner, Account: owner})
+		tokens, err := m.c.GetTokenAccountsByOwnerByProgram(ctx, owner.ToBase58(), common.TokenProgramID.ToBase58())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token accounts of %s: %w", owner.ToBase58(), err)
+		}
+		for _, t := range tokens {
+			out = append(out, watchedAccount{Owner: owner, Account: t.PublicKey, Token: true})
+		}
+	}
+	return out, nil
+}
+
+// pollAccount fetches the signatures newer than the cursor and handles them
+// oldest first, advancing the cursor after each one.
+func (m *monitor) pollAccount(ctx context.Context, w watchedAccount, replayNew bool) error {
+	key := w.Account.ToBase58()
+	until, seen := m.cursor[key]
+	// Accounts seen for the first time start at the current head, except
+	// with --from-start or replayNew (a token account opened after its owner
+	// was first watched), which start from their first signature.
+	if !seen && !m.cfg.FromStart && !replayNew {
+		latest, err := m.c.GetSignaturesForAddressWithConfig(ctx, key, client.GetSignaturesForAddressConfig{
+			Limit:      1,
+			Commitment: m.cfg.Commitment,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get signatures: %w", err)
+		}
+		// An empty cursor marks an account without history as seen, so its
+		// first transaction is handled as new.
+		m.cursor[key] = ""
+		if len(latest) > 0 {
+			m.cursor[key] = latest[0].Signature
+		}
+		return m.cursor.save(m.cfg.CursorPath)
+	}
+
+	var sigs rpc.GetSignaturesForAddress
+	before := ""
+	for {
+		page, err := m.c.GetSignaturesForAddressWithConfig(ctx, key, client.GetSignaturesForAddressConfig{
+			Before:     before,
+			Until:      until,
+			Commitment: m.cfg.Commitment,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get signatures: %w", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		sigs = append(sigs, page...)
+		before = page[len(page)-1].Signature
+	}
+
+	for i := len(sigs) - 1; i >= 0; i-- {
+		s := sigs[i]
+		if s.Err == nil {
+			d, err := m.deposit(ctx, w, s.Signature)
+			if err != nil {
+				return err
+			}
+			if d != nil {
+				if err := m.deliver(ctx, *d); err != nil {
+					return err
+				}
+			}
+		}
+		m.cursor[key] = s.Signature
+		if err := m.cursor.save(m.cfg.CursorPath); err != nil {
+			return fmt.Errorf("failed to save cursor: %w", err)
+		}
+	}
+	return nil
+}
+
+// deposit returns the incoming transfer to the watched account in one
+// transaction, or nil if its balance did not go up.
+func (m *monitor) deposit(ctx context.Context, w watchedAccount, signature string) (*deposit, error) {
+	tx, err := m.c.GetTransactionWithConfig(ctx, signature, client.GetTransactionConfig{Commitment: m.cfg.Commitment})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", signature, err)
+	}
+	if tx == nil || tx.Meta == nil || tx.Meta.Err != nil {
+		return nil, nil
+	}
+	index := -1
+	for i, k := range tx.AccountKeys {
+		if k == w.Account {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, nil
+	}
+
+	d := deposit{
+		Address:    w.Owner.ToBase58(),
+		Account:    w.Account.ToBase58(),
+		Signature:  signature,
+		Slot:       tx.Slot,
+		BlockTime:  tx.BlockTime,
+		Commitment: string(m.cfg.Commitment),
+	}
+	if !w.Token {
+		if index >= len(tx.Meta.PreBalances) || index >= len(tx.Meta.PostBalances) {
+			return nil, nil
+		}
+		delta := tx.Meta.PostBalances[index] - tx.Meta.PreBalances[index]
+		if delta <= 0 {
+			return nil, nil
+		}
+		d.Type, d.Amount, d.Decimals = "sol", uint64(delta), 9
+	} else {
+		var pre, post uint64
+		for _, b := range tx.Meta.PreTokenBalances {
+			if int(b.AccountIndex) == index {
+				pre, _ = strconv.ParseUint(b.UITokenAmount.Amount, 10, 64)
+			}
+		}
+		for _, b := range tx.Meta.PostTokenBalances {
+			if int(b.AccountIndex) == index {
+				post, _ = strconv.ParseUint(b.UITokenAmount.Amount, 10, 64)
+				d.Mint, d.Decimals = b.Mint, b.UITokenAmount.Decimals
+			}
+		}
+		if post <= pre || d.Mint == "" {
+			return nil, nil
+		}
+		d.Type, d.Amount = "spl", post-pre
+	}
+	d.UIAmount = formatTokenAmount(d.Amount, d.Decimals)
+	sum := sha256.Sum256([]byte(signature + ":" + d.Account))
+	d.ID = hex.EncodeToString(sum[:16])
+	return &d, nil
+}
+
+// deliver POSTs the deposit with exponential backoff. The body is signed as
+// hex(HMAC-SHA256(secret, timestamp + "." + body)) in X-Signature.
+func (m *monitor) deliver(ctx context.Context, d deposit) error {
+	body, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err = m.post(ctx, d.ID, body)
+		if err == nil {
+			log.Printf("monitor: delivered %s %s %s to %s (%s)", d.Type, d.UIAmount, d.Mint, d.Address, d.Signature)
+			return nil
+		}
+		if attempt > m.cfg.Retries {
+			return fmt.Errorf("webhook failed for deposit %s after %d attempts: %w", d.ID, attempt, err)
+		}
+		log.Printf("monitor: webhook attempt %d for %s failed: %v", attempt, d.ID, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, time.Minute)
+	}
+}
+
+func (m *monitor) post(ctx context.Context, id string, body []byte) error {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(m.cfg.Secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.cfg.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", id)
+	req.Header.Set("X-Timestamp", ts)
+	req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+	resp, err := m.http.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
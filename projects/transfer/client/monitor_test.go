@@ -0,0 +1,531 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+)
+
+// fakeChain is a JSON-RPC server holding address histories and transactions,
+// enough for the monitor's getSignaturesForAddress, getTransaction and
+// getTokenAccountsByOwner calls.
+type fakeChain struct {
+	mu       sync.Mutex
+	pageSize int
+	slot     uint64
+	history  map[string][]string // newest first
+	txs      map[string]map[string]any
+	tokens   map[string][]map[string]any // owner -> getTokenAccountsByOwner values
+	fetched  []string                    // getTransaction calls
+	pages    int                         // getSignaturesForAddress calls
+}
+
+func newFakeChain(t *testing.T, pageSize int) (*fakeChain, *client.Client) {
+	t.Helper()
+	f := &fakeChain{
+		pageSize: pageSize,
+		history:  map[string][]string{},
+		txs:      map[string]map[string]any{},
+		tokens:   map[string][]map[string]any{},
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, client.NewClient(srv.URL)
+}
+
+func (f *fakeChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     any               `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var first string
+	if len(req.Params) > 0 {
+		_ = json.Unmarshal(req.Params[0], &first)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var result any
+	switch req.Method {
+	case "getSignaturesForAddress":
+		var cfg struct {
+			Limit         int
+			Before, Until string
+		}
+		if len(req.Params) > 1 {
+			_ = json.Unmarshal(req.Params[1], &cfg)
+		}
+		f.pages++
+		limit := f.pageSize
+		if cfg.Limit > 0 && cfg.Limit < limit {
+			limit = cfg.Limit
+		}
+		page := []map[string]any{}
+		started := cfg.Before == ""
+		for _, sig := range f.history[first] {
+			if !started {
+				started = sig == cfg.Before
+				continue
+			}
+			if sig == cfg.Until || len(page) == limit {
+				break
+			}
+			tx := f.txs[sig]
+			page = append(page, map[string]any{"signature": sig, "slot": tx["slot"], "blockTime": tx["blockTime"], "err": tx["meta"].(map[string]any)["err"]})
+		}
+		result = page
+	case "getTransaction":
+		f.fetched = append(f.fetched, first)
+		result = f.txs[first]
+	case "getTokenAccountsByOwner":
+		values := f.tokens[first]
+		if values == nil {
+			values = []map[string]any{}
+		}
+		result = map[string]any{"context": map[string]any{"slot": f.slot}, "value": values}
+	default:
+		http.Error(w, "unsupported method "+req.Method, http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+}
+
+// addTx records a signed transaction touching accounts, with balances as
+// given by meta, at the head of the history of every address in touched.
+func (f *fakeChain) addTx(t *testing.T, msg types.Message, signer types.Account, meta map[string]any, touched ...common.PublicKey) string {
+	t.Helper()
+	tx, err := types.NewTransaction(types.NewTransactionParam{Message: msg, Signers: []types.Account{signer}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := base58.Encode(tx.Signatures[0])
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.slot++
+	blockTime := int64(1_700_000_000 + f.slot)
+	for _, key := range []string{"preTokenBalances", "postTokenBalances"} {
+		if meta[key] == nil {
+			meta[key] = []any{}
+		}
+	}
+	meta["fee"] = 5000
+	meta["loadedAddresses"] = map[string]any{"writable": []any{}, "readonly": []any{}}
+	f.txs[sig] = map[string]any{
+		"slot":        f.slot,
+		"blockTime":   blockTime,
+		"meta":        meta,
+		"transaction": []any{base64.StdEncoding.EncodeToString(raw), "base64"},
+	}
+	for _, a := range touched {
+		f.history[a.ToBase58()] = append([]string{sig}, f.history[a.ToBase58()]...)
+	}
+	return sig
+}
+
+// indexOf returns the position of key in the message's account list.
+func indexOf(t *testing.T, msg types.Message, key common.PublicKey) int {
+	t.Helper()
+	for i, k := range msg.Accounts {
+		if k == key {
+			return i
+		}
+	}
+	t.Fatalf("%s is not in the message", key.ToBase58())
+	return -1
+}
+
+// solDeposit sends lamports from a new payer to owner; failed marks the
+// transaction as failed on chain with unchanged balances.
+func (f *fakeChain) solDeposit(t *testing.T, owner common.PublicKey, lamports uint64, failed bool) string {
+	t.Helper()
+	payer := types.NewAccount()
+	msg := types.NewMessage(types.NewMessageParam{
+		FeePayer:        payer.PublicKey,
+		RecentBlockhash: common.PublicKey{}.ToBase58(),
+		Instructions:    []types.Instruction{system.Transfer(system.TransferParam{From: payer.PublicKey, To: owner, Amount: lamports})},
+	})
+	pre := make([]int64, len(msg.Accounts))
+	post := make([]int64, len(msg.Accounts))
+	pre[0], post[0] = 10_000_000_000, 10_000_000_000-5000
+	to := indexOf(t, msg, owner)
+	pre[to], post[to] = 1_000_000, 1_000_000
+	meta := map[string]any{"err": nil}
+	if failed {
+		meta["err"] = map[string]any{"InstructionError": []any{0, map[string]any{"Custom": 1}}}
+	} else {
+		post[0] -= int64(lamports)
+		post[to] += int64(lamports)
+	}
+	meta["preBalances"], meta["postBalances"] = pre, post
+	return f.addTx(t, msg, payer, meta, owner)
+}
+
+// openTokenAccount lists a new token account of owner for mint.
+func (f *fakeChain) openTokenAccount(owner, mint common.PublicKey) common.PublicKey {
+	account := types.NewAccount().PublicKey
+	data := make([]byte, token.TokenAccountSize)
+	copy(data, mint.Bytes())
+	copy(data[32:], owner.Bytes())
+	data[108] = byte(token.TokenAccountStateInitialized)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens[owner.ToBase58()] = append(f.tokens[owner.ToBase58()], map[string]any{
+		"pubkey": account.ToBase58(),
+		"account": map[string]any{
+			"data":       []any{base64.StdEncoding.EncodeToString(data), "base64"},
+			"executable": false,
+			"lamports":   2_039_280,
+			"owner":      common.TokenProgramID.ToBase58(),
+			"rentEpoch":  0,
+		},
+	})
+	return account
+}
+
+// tokenDeposit transfers amount of mint (6 decimals) into account.
+func (f *fakeChain) tokenDeposit(t *testing.T, account, mint common.PublicKey, amount uint64) string {
+	t.Helper()
+	sender := types.NewAccount()
+	source := types.NewAccount().PublicKey
+	msg := types.NewMessage(types.NewMessageParam{
+		FeePayer:        sender.PublicKey,
+		RecentBlockhash: common.PublicKey{}.ToBase58(),
+		Instructions: []types.Instruction{token.Transfer(token.TransferParam{
+			From: source, To: account, Auth: sender.PublicKey, Amount: amount,
+		})},
+	})
+	balance := func(index int, raw uint64) map[string]any {
+		return map[string]any{
+			"accountIndex": index,
+			"mint":         mint.ToBase58(),
+			"uiTokenAmount": map[string]any{
+				"amount": strconv.FormatUint(raw, 10), "decimals": 6, "uiAmountString": formatTokenAmount(raw, 6),
+			},
+		}
+	}
+	src, dst := indexOf(t, msg, source), indexOf(t, msg, account)
+	n := len(msg.Accounts)
+	meta := map[string]any{
+		"err":               nil,
+		"preBalances":       make([]int64, n),
+		"postBalances":      make([]int64, n),
+		"preTokenBalances":  []any{balance(src, 1_000_000_000), balance(dst, 0)},
+		"postTokenBalances": []any{balance(src, 1_000_000_000-amount), balance(dst, amount)},
+	}
+	return f.addTx(t, msg, sender, meta, account)
+}
+
+// webhookReceiver records deliveries and answers with the next status in
+// statuses (200 once they run out).
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (wr *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.requests = append(wr.requests, r)
+	wr.bodies = append(wr.bodies, body)
+	status := http.StatusOK
+	if len(wr.statuses) > 0 {
+		status, wr.statuses = wr.statuses[0], wr.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (wr *webhookReceiver) deposits(t *testing.T) []deposit {
+	t.Helper()
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	var out []deposit
+	for _, body := range wr.bodies {
+		var d deposit
+		if err := json.Unmarshal(body, &d); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+func newTestMonitor(t *testing.T, c *client.Client, owners []common.PublicKey, cfg monitorConfig) (*monitor, *webhookReceiver) {
+	t.Helper()
+	receiver := &webhookReceiver{}
+	srv := httptest.NewServer(receiver)
+	t.Cleanup(srv.Close)
+	cfg.Webhook = srv.URL
+	cfg.Commitment = "confirmed"
+	if cfg.CursorPath == "" {
+		cfg.CursorPath = filepath.Join(t.TempDir(), "cursor.json")
+	}
+	if cfg.Secret == "" {
+		cfg.Secret = "test-secret"
+	}
+	return &monitor{cfg: cfg, c: c, http: srv.Client(), owners: owners, cursor: monitorCursor{}}, receiver
+}
+
+func TestMonitorCursorFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cursor.json")
+
+	cur, err := loadCursor(path)
+	if err != nil || len(cur) != 0 {
+		t.Fatalf("missing cursor file: %v, %v", cur, err)
+	}
+	cur["a"], cur["b"] = "sig-a", ""
+	if err := cur.save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadCursor(path)
+	if err != nil || len(loaded) != 2 || loaded["a"] != "sig-a" {
+		t.Fatalf("loaded %v, %v", loaded, err)
+	}
+	if _, ok := loaded["b"]; !ok {
+		t.Fatal("an empty cursor entry was dropped")
+	}
+	// The temporary file is renamed into place, never left behind.
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("cursor directory holds %d files", len(entries))
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadCursor(path); err == nil {
+		t.Fatal("a corrupt cursor file was accepted")
+	}
+}
+
+func TestMonitorPagesBackToTheCursor(t *testing.T) {
+	chain, c := newFakeChain(t, 2)
+	owner := types.NewAccount().PublicKey
+	for i := 0; i < 5; i++ {
+		chain.solDeposit(t, owner, 1_000, false)
+	}
+	m, receiver := newTestMonitor(t, c, []common.PublicKey{owner}, monitorConfig{})
+	ctx := context.Background()
+
+	// The first round starts at the head: history is not delivered.
+	if err := m.poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(receiver.deposits(t)) != 0 || len(chain.fetched) != 0 {
+		t.Fatalf("history was handled: %d deliveries, %d transactions fetched", len(receiver.deposits(t)), len(chain.fetched))
+	}
+	head := chain.history[owner.ToBase58()][0]
+	if m.cursor[owner.ToBase58()] != head {
+		t.Fatal("cursor does not point at the head")
+	}
+
+	// Five new transactions over three pages of two; one failed.
+	var want []string
+	for i := 0; i < 5; i++ {
+		sig := chain.solDeposit(t, owner, uint64(100*(i+1)), i == 2)
+		if i != 2 {
+			want = append(want, sig)
+		}
+	}
+	chain.pages = 0
+	if err := m.poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got := receiver.deposits(t)
+	if len(got) != len(want) {
+		t.Fatalf("delivered %d deposits, want %d", len(got), len(want))
+	}
+	for i, d := range got {
+		if d.Signature != want[i] {
+			t.Fatalf("deposit %d is %s, want %s (oldest first)", i, d.Signature, want[i])
+		}
+		if d.Type != "sol" || d.Address != owner.ToBase58() || d.Account != owner.ToBase58() || d.Decimals != 9 || d.Commitment != "confirmed" {
+			t.Fatalf("deposit %d: %+v", i, d)
+		}
+	}
+	if got[0].Amount != 100 || got[0].UIAmount != "0.0000001" {
+		t.Fatalf("first deposit %d (%s)", got[0].Amount, got[0].UIAmount)
+	}
+	// Three full or partial pages and an empty one; nothing at or past the cursor is read.
+	if chain.pages != 4 {
+		t.Fatalf("%d signature pages requested, want 4", chain.pages)
+	}
+	for _, sig := range chain.fetched {
+		for _, old := range chain.history[owner.ToBase58()][5:] {
+			if sig == old {
+				t.Fatalf("transaction %s before the cursor was fetched", sig)
+			}
+		}
+	}
+	newest := chain.history[owner.ToBase58()][0]
+	saved, err := loadCursor(m.cfg.CursorPath)
+	if err != nil || saved[owner.ToBase58()] != newest {
+		t.Fatalf("saved cursor %v, %v; want %s", saved, err, newest)
+	}
+
+	// Nothing new: no deliveries and one empty page.
+	if err := m.poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(receiver.deposits(t)) != len(want) {
+		t.Fatal("a deposit was delivered twice")
+	}
+}
+
+func TestMonitorFromStart(t *testing.T) {
+	chain, c := newFakeChain(t, 1000)
+	owner := types.NewAccount().PublicKey
+	chain.solDeposit(t, owner, 1_000, false)
+	chain.solDeposit(t, owner, 2_000, false)
+	m, receiver := newTestMonitor(t, c, []common.PublicKey{owner}, monitorConfig{FromStart: true})
+	if err := m.poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := receiver.deposits(t); len(got) != 2 || got[0].Amount != 1_000 || got[1].Amount != 2_000 {
+		t.Fatalf("deposits %+v", got)
+	}
+}
+
+func TestMonitorReplaysNewTokenAccounts(t *testing.T) {
+	chain, c := newFakeChain(t, 1000)
+	owner, mint := types.NewAccount().PublicKey, types.NewAccount().PublicKey
+	existing := chain.openTokenAccount(owner, mint)
+	chain.tokenDeposit(t, existing, mint, 5_000_000)
+	m, receiver := newTestMonitor(t, c, []common.PublicKey{owner}, monitorConfig{})
+	ctx := context.Background()
+
+	// On the first round the owner is new, so its token accounts start at the head too.
+	if err := m.poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := receiver.deposits(t); len(got) != 0 {
+		t.Fatalf("history of an existing token account was delivered: %+v", got)
+	}
+
+	// A token account opened later (e.g. while the monitor was down) is
+	// replayed from its first transaction.
+	opened := chain.openTokenAccount(owner, mint)
+	sig := chain.tokenDeposit(t, opened, mint, 2_500_000)
+	if err := m.poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got := receiver.deposits(t)
+	if len(got) != 1 {
+		t.Fatalf("delivered %d deposits, want 1", len(got))
+	}
+	d := got[0]
+	if d.Type != "spl" || d.Signature != sig || d.Account != opened.ToBase58() || d.Address != owner.ToBase58() ||
+		d.Mint != mint.ToBase58() || d.Amount != 2_500_000 || d.Decimals != 6 || d.UIAmount != "2.5" {
+		t.Fatalf("deposit %+v", d)
+	}
+}
+
+func TestMonitorWebhookSignature(t *testing.T) {
+	chain, c := newFakeChain(t, 1000)
+	owner := types.NewAccount().PublicKey
+	m, receiver := newTestMonitor(t, c, []common.PublicKey{owner}, monitorConfig{FromStart: true, Secret: "s3cret", Retries: 1})
+	sig := chain.solDeposit(t, owner, 42, false)
+	// The first attempt fails, the retry is accepted.
+	receiver.statuses = []int{http.StatusInternalServerError}
+
+	if err := m.poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(receiver.requests) != 2 {
+		t.Fatalf("%d webhook attempts, want 2", len(receiver.requests))
+	}
+	sum := sha256.Sum256([]byte(sig + ":" + owner.ToBase58()))
+	id := hex.EncodeToString(sum[:16])
+	for i, r := range receiver.requests {
+		body := receiver.bodies[i]
+		if got := r.Header.Get("Idempotency-Key"); got != id {
+			t.Fatalf("attempt %d: Idempotency-Key %q, want %q", i, got, id)
+		}
+		ts := r.Header.Get("X-Timestamp")
+		if _, err := strconv.ParseInt(ts, 10, 64); err != nil {
+			t.Fatalf("attempt %d: X-Timestamp %q", i, ts)
+		}
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(ts + "."))
+		mac.Write(body)
+		if got := r.Header.Get("X-Signature"); !hmac.Equal([]byte(got), []byte(hex.EncodeToString(mac.Sum(nil)))) {
+			t.Fatalf("attempt %d: X-Signature %q does not match the body", i, got)
+		}
+		var d deposit
+		if err := json.Unmarshal(body, &d); err != nil || d.ID != id {
+			t.Fatalf("attempt %d: payload id %q, %v", i, d.ID, err)
+		}
+	}
+}
+
+func TestMonitorKeepsCursorWhenWebhookFails(t *testing.T) {
+	chain, c := newFakeChain(t, 1000)
+	owner := types.NewAccount().PublicKey
+	m, receiver := newTestMonitor(t, c, []common.PublicKey{owner}, monitorConfig{})
+	ctx := context.Background()
+	if err := m.poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	first := chain.solDeposit(t, owner, 1, false)
+	second := chain.solDeposit(t, owner, 2, false)
+	// The first deposit is delivered, the second is refused.
+	receiver.statuses = []int{http.StatusOK, http.StatusServiceUnavailable}
+
+	if err := m.poll(ctx); err == nil {
+		t.Fatal("poll succeeded although the webhook failed")
+	}
+	if got := m.cursor[owner.ToBase58()]; got != first {
+		t.Fatalf("cursor %s, want the last delivered %s", got, first)
+	}
+	// The next round resends only the refused deposit, with the same id.
+	if err := m.poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got := receiver.deposits(t)
+	if len(got) != 3 || got[1].Signature != second || got[2].ID != got[1].ID {
+		t.Fatalf("deliveries %+v", got)
+	}
+}
+
+// TestFakeTokenAccountLayout checks that the fake chain lists token accounts
+// the client decodes, so the replay test exercises real parsing.
+func TestFakeTokenAccountLayout(t *testing.T) {
+	chain, c := newFakeChain(t, 1000)
+	owner, mint := types.NewAccount().PublicKey, types.NewAccount().PublicKey
+	account := chain.openTokenAccount(owner, mint)
+	tokens, err := c.GetTokenAccountsByOwnerByProgram(context.Background(), owner.ToBase58(), common.TokenProgramID.ToBase58())
+	if err != nil || len(tokens) != 1 || tokens[0].PublicKey != account || tokens[0].Mint != mint {
+		t.Fatalf("token accounts %+v, %v", tokens, err)
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/skip2/go-qrcode"
+)
+
+// payRequest holds the fields of a Solana Pay transfer request URL.
+type payRequest struct {
+	Recipient common.PublicKey
+	Amount    string // UI units; empty lets the wallet ask
+	SPLToken  *common.PublicKey
+	Reference *common.PublicKey
+	Label     string
+	Message   string
+	Memo      string
+}
+
+// URL renders the request as solana:<recipient>?amount=...&reference=...
+func (r payRequest) URL() string {
+	var params []string
+	add := func(key, value string) {
+		// Wallets expect %20 rather than + for spaces.
+		params = append(params, key+"="+strings.ReplaceAll(url.QueryEscape(value), "+", "%20"))
+	}
+	if r.Amount != "" {
+		add("amount", r.Amount)
+	}
+	if r.SPLToken != nil {
+		add("spl-token", r.SPLToken.ToBase58())
+	}
+	if r.Reference != nil {
+		add("reference", r.Reference.ToBase58())
+	}
+	if r.Label != "" {
+		add("label", r.Label)
+	}
+	if r.Message != "" {
+		add("message", r.Message)
+	}
+	if r.Memo != "" {
+		add("memo", r.Memo)
+	}
+	u := "solana:" + r.Recipient.ToBase58()
+	if len(params) > 0 {
+		u += "?" + strings.Join(params, "&")
+	}
+	return u
+}
+
+// runPayRequest prints a transfer request URL as JSON and draws it as a QR
+// code on stderr. Without a reference a fresh random key is used, which
+// `pay verify` later looks up.
+func runPayRequest(req payRequest, qr bool, cluster, rpcOverride string) error {
+	if req.Reference == nil {
+		ref := types.NewAccount().PublicKey
+		req.Reference = &ref
+	}
+	var raw uint64
+	if req.Amount != "" {
+		decimals := uint8(9)
+		if req.SPLToken != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			mint, err := fetchMint(ctx, client.NewClient(resolveEndpoint(cluster, rpcOverride)), *req.SPLToken)
+			if err != nil {
+				return err
+			}
+			decimals = mint.Decimals
+		}
+		var err error
+		if raw, err = parseTokenAmount(req.Amount, decimals); err != nil {
+			return err
+		}
+		req.Amount = formatTokenAmount(raw, decimals)
+	}
+
+	link := req.URL()
+	if qr {
+		code, err := qrcode.New(link, qrcode.Medium)
+		if err != nil {
+			return fmt.Errorf("failed to encode QR code: %w", err)
+		}
+		fmt.Fprint(os.Stderr, code.ToSmallString(false))
+	}
+	out := map[string]any{
+		"url":       link,
+		"recipient": req.Recipient.ToBase58(),
+		"reference": req.Reference.ToBase58(),
+	}
+	if req.Amount != "" {
+		out["amount"] = req.Amount
+		out["rawAmount"] = raw
+	}
+	if req.SPLToken != nil {
+		out["splToken"] = req.SPLToken.ToBase58()
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false) // keep & in the URL readable
+	return enc.Encode(out)
+}
+
+// payment is a transaction that carries the reference key.
+type payment struct {
+	Signature string `json:"signature"`
+	Slot      uint64 `json:"slot"`
+	BlockTime *int64 `json:"blockTime,omitempty"`
+	Received  uint64 `json:"received"`
+}
+
+// runPayVerify finds the transactions that carry the reference key and checks
+// that one of them succeeded, paid at least amount to the recipient (or its
+// associated token account for SPL payments) and, if given, carried the memo.
+// It reports whether a valid payment was found so the caller can set the exit code.
+func runPayVerify(req payRequest, cluster, rpcOverride string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+
+	decimals := uint8(9)
+	target := req.Recipient
+	if req.SPLToken != nil {
+		mint, err := fetchMint(ctx, c, *req.SPLToken)
+		if err != nil {
+			return false, err
+		}
+		decimals = mint.Decimals
+		if target, _, err = common.FindAssociatedTokenAddress(req.Recipient, *req.SPLToken); err != nil {
+			return false, fmt.Errorf("failed to derive associated token account: %w", err)
+		}
+	}
+	amount, err := parseTokenAmount(req.Amount, decimals)
+	if err != nil {
+		return false, err
+	}
+
+	sigs, err := c.GetSignaturesForAddressWithConfig(ctx, req.Reference.ToBase58(), client.GetSignaturesForAddressConfig{
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get signatures: %w", err)
+	}
+	out := map[string]any{
+		"cluster":   cluster,
+		"reference": req.Reference.ToBase58(),
+		"recipient": req.Recipient.ToBase58(),
+		"amount":    formatTokenAmount(amount, decimals),
+		"valid":     false,
+	}
+	if len(sigs) == 0 {
+		out["reason"] = "no transaction carries the reference"
+	}
+	// Newest first; the first valid payment wins.
+	for _, s := range sigs {
+		tx, err := c.GetTransactionWithConfig(ctx, s.Signature, client.GetTransactionConfig{Commitment: rpc.CommitmentConfirmed})
+		if err != nil {
+			return false, fmt.Errorf("failed to get transaction %s: %w", s.Signature, err)
+		}
+		if tx == nil || tx.Meta == nil {
+			continue
+		}
+		received, err := validatePayment(tx, target, req.SPLToken, amount, req.Memo)
+		if err != nil {
+			out["reason"] = fmt.Sprintf("%s: %v", s.Signature, err)
+			continue
+		}
+		out["valid"] = true
+		out["payment"] = payment{
+			Signature: s.Signature,
+			Slot:      tx.Slot,
+			BlockTime: tx.BlockTime,
+			Received:  received,
+		}
+		delete(out, "reason")
+		break
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return out["valid"].(bool), enc.Encode(out)
+}
+
+// validatePayment checks one transaction against the request and returns the
+// amount the target account received, in raw units.
+func validatePayment(tx *client.Transaction, target common.PublicKey, mint *common.PublicKey, amount uint64, memoText string) (uint64, error) {
+	if tx.Meta.Err != nil {
+		return 0, fmt.Errorf("transaction failed: %v", tx.Meta.Err)
+	}
+	index := -1
+	for i, k := range tx.AccountKeys {
+		if k == target {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return 0, errors.New("recipient not found in transaction")
+	}
+
+	var received uint64
+	if mint == nil {
+		if index >= len(tx.Meta.PreBalances) || index >= len(tx.Meta.PostBalances) {
+			return 0, errors.New("transaction has no balances for the recipient")
+		}
+		if delta := tx.Meta.PostBalances[index] - tx.Meta.PreBalances[index]; delta > 0 {
+			received = uint64(delta)
+		}
+	} else {
+		// A token account created by the payment has no pre-balance entry.
+		pre, err := tokenBalanceAt(tx.Meta.PreTokenBalances, index, *mint)
+		if err != nil {
+			return 0, err
+		}
+		post, err := tokenBalanceAt(tx.Meta.PostTokenBalances, index, *mint)
+		if err != nil {
+			return 0, err
+		}
+		if post > pre {
+			received = post - pre
+		}
+	}
+	if received < amount {
+		return received, fmt.Errorf("recipient received %d, want %d", received, amount)
+	}
+
+	if memoText != "" && !hasMemo(tx, memoText) {
+		return received, fmt.Errorf("memo %q not found", memoText)
+	}
+	return received, nil
+}
+
+func tokenBalanceAt(balances []rpc.TransactionMetaTokenBalance, index int, mint common.PublicKey) (uint64, error) {
+	for _, b := range balances {
+		if int(b.AccountIndex) != index {
+			continue
+		}
+		if b.Mint != mint.ToBase58() {
+			return 0, fmt.Errorf("recipient token account holds %s, not %s", b.Mint, mint.ToBase58())
+		}
+		v, err := strconv.ParseUint(b.UITokenAmount.Amount, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid token amount %q", b.UITokenAmount.Amount)
+		}
+		return v, nil
+	}
+	return 0, nil
+}
+
+// hasMemo reports whether a top-level Memo program instruction carries text.
+func hasMemo(tx *client.Transaction, text string) bool {
+	for _, ix := range tx.Transaction.Message.Instructions {
+		if ix.ProgramIDIndex < len(tx.AccountKeys) &&
+			tx.AccountKeys[ix.ProgramIDIndex] == common.MemoProgramID &&
+			string(ix.Data) == text {
+			return true
+		}
+	}
+	return false
+}
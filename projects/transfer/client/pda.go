@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/blocto/solana-go-sdk/common"
+
+	"sdk/anchor"
+	"sdk/programs/chain"
+	"sdk/programs/favorite"
+	"sdk/programs/voting"
+)
+
+// knownPrograms maps program names accepted by --program to their PDA seed templates.
+var knownPrograms = map[string][]anchor.PDATemplate{
+	"chain":    chain.PDATemplates,
+	"favorite": favorite.PDATemplates,
+	"voting":   voting.PDATemplates,
+}
+
+// seedFlags collects repeated --seed kind:value flags.
+type seedFlags []string
+
+func (s *seedFlags) String() string { return strings.Join(*s, ",") }
+
+func (s *seedFlags) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// resolveProgramID accepts a known program name or a base58 program id.
+func resolveProgramID(program string) (common.PublicKey, error) {
+	if ts, ok := knownPrograms[program]; ok && len(ts) > 0 {
+		return ts[0].ProgramID, nil
+	}
+	if !isValidBase58Pubkey(program) {
+		return common.PublicKey{}, fmt.Errorf("unknown program %q (chain, favorite, voting or a base58 id)", program)
+	}
+	return common.PublicKeyFromString(program), nil
+}
+
+// runPDADerive derives the program address for the given seeds.
+func runPDADerive(program string, seeds []string) error {
+	programID, err := resolveProgramID(program)
+	if err != nil {
+		return err
+	}
+	raw := make([][]byte, len(seeds))
+	for i, s := range seeds {
+		if raw[i], err = anchor.ParseSeed(s); err != nil {
+			return err
+		}
+	}
+	address, bump, err := common.FindProgramAddress(raw, programID)
+	if err != nil {
+		return fmt.Errorf("failed to derive address: %w", err)
+	}
+
+	out := map[string]any{
+		"program": programID.ToBase58(),
+		"seeds":   seeds,
+		"address": address.ToBase58(),
+		"bump":    bump,
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// runPDAFind searches the known seed templates for the seeds that produce address.
+// Pubkey seeds are tried from pubkeys, string seeds from strs and integer seeds over 0..maxInt.
+func runPDAFind(address, program string, pubkeys []common.PublicKey, strs []string, maxInt uint64) error {
+	var templates []anchor.PDATemplate
+	if program != "" {
+		ts, ok := knownPrograms[program]
+		if !ok {
+			return fmt.Errorf("unknown program %q (chain, favorite, voting)", program)
+		}
+		templates = ts
+	} else {
+		for _, name := range []string{"chain", "favorite", "voting"} {
+			templates = append(templates, knownPrograms[name]...)
+		}
+	}
+	matches := anchor.FindPDA(common.PublicKeyFromString(address), templates, anchor.PDACandidates{
+		Pubkeys: pubkeys,
+		Strings: strs,
+		MaxInt:  maxInt,
+	})
+
+	type match struct {
+		Program   string   `json:"program"`
+		ProgramID string   `json:"programId"`
+		Account   string   `json:"account"`
+		Template  string   `json:"template"`
+		Seeds     []string `json:"seeds"`
+		Bump      uint8    `json:"bump"`
+	}
+	found := make([]match, len(matches))
+	for i, m := range matches {
+		found[i] = match{
+			Program:   m.Template.Program,
+			ProgramID: m.Template.ProgramID.ToBase58(),
+			Account:   m.Template.Account,
+			Template:  m.Template.String(),
+			Seeds:     m.Seeds,
+			Bump:      m.Bump,
+		}
+	}
+	searched := make([]string, len(templates))
+	for i, t := range templates {
+		searched[i] = t.Program + "." + t.Account + " " + t.String()
+	}
+	out := map[string]any{
+		"address":  address,
+		"matches":  found,
+		"searched": searched,
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		return fmt.Errorf("no known seed template produces %s with the given candidates", address)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+
+	"sdk/anchor"
+	"sdk/programs/favorite"
+	"sdk/programs/voting"
+)
+
+// closeablePDA is a PDA kind whose program has a close instruction that
+// returns the account's lamports to the owner it belongs to.
+type closeablePDA struct {
+	Program       string
+	Account       string
+	ProgramID     common.PublicKey
+	Discriminator anchor.Discriminator
+	// Find returns the candidate addresses of owner's accounts of this kind;
+	// the scan keeps the ones that exist.
+	Find func(ctx context.Context, c *client.Client, owner common.PublicKey) ([]common.PublicKey, error)
+}
+
+// closeablePDAs lists every PDA the reclaim scan looks for.
+var closeablePDAs = []closeablePDA{
+	{
+		// The default favorites (close) and every named profile (close_profile).
+		Program:       "favorite",
+		Account:       "favorites",
+		ProgramID:     favorite.ProgramID,
+		Discriminator: favorite.FavoriteDiscriminator,
+		Find:          userFavorites,
+	},
+	{
+		// Closed by `poll close` (close_poll), together with its candidates.
+		Program:       "voting",
+		Account:       "poll",
+		ProgramID:     voting.ProgramID,
+		Discriminator: voting.PollDiscriminator,
+		Find: func(ctx context.Context, c *client.Client, owner common.PublicKey) ([]common.PublicKey, error) {
+			polls, err := authorityPolls(ctx, c, owner)
+			if err != nil {
+				return nil, err
+			}
+			out := make([]common.PublicKey, 0, len(polls))
+			for _, address := range polls {
+				out = append(out, address)
+			}
+			return out, nil
+		},
+	},
+	{
+		Program:       "voting",
+		Account:       "candidate",
+		ProgramID:     voting.ProgramID,
+		Discriminator: voting.CandidateAccountDiscriminator,
+		Find:          authorityCandidates,
+	},
+}
+
+// userFavorites finds owner's favorites accounts by the user stored right
+// after the discriminator. Profile names are not stored, so named profiles
+// cannot be derived and have to be looked up this way.
+func userFavorites(ctx context.Context, c *client.Client, owner common.PublicKey) ([]common.PublicKey, error) {
+	accounts, err := anchor.FetchProgramAccounts(ctx, c, favorite.ProgramID,
+		anchor.DiscriminatorFilter(favorite.FavoriteDiscriminator),
+		anchor.MemcmpFilter(8, owner.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]common.PublicKey, 0, len(accounts))
+	for _, acc := range accounts {
+		out = append(out, acc.Pubkey)
+	}
+	return out, nil
+}
+
+// maxReclaimPollID is the highest poll id tried when recovering the ids of the
+// owner's polls, as the indexer does by default.
+const maxReclaimPollID = 1000
+
+// authorityPolls maps the ids of the polls whose authority is owner to their
+// addresses. Polls do not store their id, so it is recovered by matching
+// ["poll", id_le] PDAs; polls with a higher id are not found.
+func authorityPolls(ctx context.Context, c *client.Client, owner common.PublicKey) (map[uint64]common.PublicKey, error) {
+	accounts, err := anchor.FetchProgramAccounts(ctx, c, voting.ProgramID, anchor.DiscriminatorFilter(voting.PollDiscriminator))
+	if err != nil {
+		return nil, err
+	}
+	owned := map[common.PublicKey]bool{}
+	for _, acc := range accounts {
+		poll, err := voting.DecodePoll(acc.Data)
+		if err == nil && poll.Authority == owner {
+			owned[acc.Pubkey] = true
+		}
+	}
+	polls := map[uint64]common.PublicKey{}
+	for id := uint64(0); id <= maxReclaimPollID && len(polls) < len(owned); id++ {
+		address, _, err := voting.FindPollAccountAddress(id)
+		if err == nil && owned[address] {
+			polls[id] = address
+		}
+	}
+	return polls, nil
+}
+
+// authorityCandidates finds the candidate accounts of owner's polls. Only the
+// poll authority can close them (through close_poll), so they are listed with
+// the polls rather than left to lock their rent.
+func authorityCandidates(ctx context.Context, c *client.Client, owner common.PublicKey) ([]common.PublicKey, error) {
+	polls, err := authorityPolls(ctx, c, owner)
+	if err != nil || len(polls) == 0 {
+		return nil, err
+	}
+	accounts, err := anchor.FetchProgramAccounts(ctx, c, voting.ProgramID, anchor.DiscriminatorFilter(voting.CandidateAccountDiscriminator))
+	if err != nil {
+		return nil, err
+	}
+	var out []common.PublicKey
+	for _, acc := range accounts {
+		candidate, err := voting.DecodeCandidateAccount(acc.Data)
+		if err != nil {
+			continue
+		}
+		for id := range polls {
+			address, _, err := voting.FindCandidateAccountAddress(id, candidate.CandidateName)
+			if err == nil && address == acc.Pubkey {
+				out = append(out, acc.Pubkey)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+// reclaimable is a closeable PDA found on chain.
+type reclaimable struct {
+	Program  string `json:"program"`
+	Account  string `json:"account"`
+	Address  string `json:"address"`
+	Space    int    `json:"space"`
+	Lamports uint64 `json:"lamports"`
+}
+
+// scanReclaimable finds the owner's accounts of each closeable PDA kind and
+// returns the ones that exist and are still owned by their program.
+func scanReclaimable(ctx context.Context, c *client.Client, owner common.PublicKey) ([]reclaimable, error) {
+	var addrs []string
+	var kinds []closeablePDA
+	for _, p := range closeablePDAs {
+		found, err := p.Find(ctx, c, owner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find %s.%s accounts: %w", p.Program, p.Account, err)
+		}
+		for _, address := range found {
+			addrs = append(addrs, address.ToBase58())
+			kinds = append(kinds, p)
+		}
+	}
+	found := []reclaimable{}
+	// getMultipleAccounts takes at most 100 addresses per call.
+	for start := 0; start < len(addrs); start += 100 {
+		end := min(start+100, len(addrs))
+		infos, err := c.GetMultipleAccounts(ctx, addrs[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get accounts: %w", err)
+		}
+		for i, info := range infos {
+			p := kinds[start+i]
+			// Missing accounts come back zeroed; closed ones are reassigned to the system program.
+			if info.Owner != p.ProgramID {
+				continue
+			}
+			if _, err := anchor.CheckDiscriminator(info.Data, p.Discriminator); err != nil {
+				continue
+			}
+			found = append(found, reclaimable{
+				Program:  p.Program,
+				Account:  p.Account,
+				Address:  addrs[start+i],
+				Space:    len(info.Data),
+				Lamports: info.Lamports,
+			})
+		}
+	}
+	return found, nil
+}
+
+// runReclaim lists the signer's closeable PDAs and the rent that closing them recovers.
+func runReclaim(fromPrivBase58, fromFilePath, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	owner, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	found, err := scanReclaimable(ctx, c, owner.PublicKey)
+	if err != nil {
+		return err
+	}
+	var total uint64
+	for _, r := range found {
+		total += r.Lamports
+	}
+
+	out := map[string]any{
+		"cluster":       cluster,
+		"owner":         owner.PublicKey.ToBase58(),
+		"accounts":      found,
+		"totalLamports": total,
+		"totalSol":      float64(total) / lamportsPerSOL,
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
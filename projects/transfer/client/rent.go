@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+
+	"sdk/anchor"
+	"sdk/idl"
+	"sdk/programs/favorite"
+	"sdk/programs/voting"
+)
+
+// accountMaxLens holds the #[max_len] annotations of each program's accounts,
+// which the IDL does not carry.
+var accountMaxLens = map[string]anchor.MaxLens{
+	"favorite": favorite.MaxLens,
+	"voting":   voting.MaxLens,
+}
+
+// runRent reports the size and rent-exempt minimum of a program's accounts
+// (all of them unless account is set), or of a raw space when space >= 0.
+func runRent(program, account string, space int64, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+
+	var costs []anchor.AccountCost
+	if space >= 0 {
+		rent, err := c.GetMinimumBalanceForRentExemption(ctx, uint64(space))
+		if err != nil {
+			return fmt.Errorf("failed to get rent-exempt minimum: %w", err)
+		}
+		costs = append(costs, anchor.AccountCost{
+			Space:    int(space),
+			Rent:     rent,
+			Fee:      anchor.LamportsPerSignature,
+			Required: rent + anchor.LamportsPerSignature,
+		})
+	} else {
+		lens, ok := accountMaxLens[program]
+		if !ok {
+			return fmt.Errorf("unknown program %q (favorite, voting)", program)
+		}
+		schema, err := idl.Load(program)
+		if err != nil {
+			return err
+		}
+		for _, acc := range schema.Accounts {
+			if account != "" && acc.Name != account {
+				continue
+			}
+			cost, err := schema.AccountRent(ctx, c, acc.Name, lens)
+			if err != nil {
+				return err
+			}
+			costs = append(costs, cost)
+		}
+		if len(costs) == 0 {
+			return fmt.Errorf("program %s has no account %q", program, account)
+		}
+	}
+
+	out := map[string]any{
+		"cluster":  cluster,
+		"accounts": costs,
+	}
+	if program != "" {
+		out["program"] = program
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
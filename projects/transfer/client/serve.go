@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+
+	"sdk/anchor"
+)
+
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// maxMemoLen bounds the memo so a transfer always fits in one transaction.
+const maxMemoLen = 256
+
+// gatewayConfig holds the `serve` settings.
+type gatewayConfig struct {
+	Listen      string
+	APIKeys     []string
+	Signer      *types.Account // pays POST /transfer; nil disables the endpoint
+	MaxLamports uint64         // largest single transfer; 0 means no limit
+}
+
+// gateway exposes balance, transfer, airdrop and transaction lookups as a
+// REST/JSON API for non-Go backends. Every endpoint except the OpenAPI
+// document requires an API key.
+type gateway struct {
+	cfg     gatewayConfig
+	cluster string
+	c       *client.Client
+}
+
+// txResult is the output of GET /tx/{sig}.
+type txResult struct {
+	Signature          string   `json:"signature"`
+	Slot               uint64   `json:"slot"`
+	BlockTime          *int64   `json:"blockTime,omitempty"`
+	Fee                uint64   `json:"fee"`
+	Success            bool     `json:"success"`
+	Err                any      `json:"err,omitempty"`
+	ConfirmationStatus string   `json:"confirmationStatus,omitempty"`
+	Accounts           []string `json:"accounts"`
+	Logs               []string `json:"logs"`
+}
+
+// errTxNotFound is returned by fetchTransaction for unknown signatures.
+var errTxNotFound = errors.New("transaction not found")
+
+// fetchTransaction looks up a confirmed transaction and its confirmation status.
+func fetchTransaction(ctx context.Context, c *client.Client, sig string) (txResult, error) {
+	tx, err := c.GetTransactionWithConfig(ctx, sig, client.GetTransactionConfig{Commitment: rpc.CommitmentConfirmed})
+	if err != nil {
+		return txResult{}, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if tx == nil || tx.Meta == nil {
+		return txResult{}, errTxNotFound
+	}
+	out := txResult{
+		Signature: sig,
+		Slot:      tx.Slot,
+		BlockTime: tx.BlockTime,
+		Fee:       tx.Meta.Fee,
+		Success:   tx.Meta.Err == nil,
+		Err:       tx.Meta.Err,
+		Accounts:  make([]string, len(tx.AccountKeys)),
+		Logs:      tx.Meta.LogMessages,
+	}
+	for i, k := range tx.AccountKeys {
+		out.Accounts[i] = k.ToBase58()
+	}
+	if out.Logs == nil {
+		out.Logs = []string{}
+	}
+	if status, err := c.GetSignatureStatus(ctx, sig); err == nil && status != nil && status.ConfirmationStatus != nil {
+		out.ConfirmationStatus = string(*status.ConfirmationStatus)
+	}
+	return out, nil
+}
+
+// authorized accepts "Authorization: Bearer <key>" or "X-API-Key: <key>".
+func (g *gateway) authorized(r *http.Request) bool {
+	key := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); key == "" && strings.HasPrefix(auth, "Bearer ") {
+		key = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if key == "" {
+		return false
+	}
+	ok := false
+	for _, k := range g.cfg.APIKeys {
+		// Compare against every key so timing does not reveal which one matched.
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			ok = true
+		}
+	}
+	return ok
+}
+
+// withAuth rejects requests without a valid API key and logs every request.
+func (g *gateway) withAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !g.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="transfer-client"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "missing or invalid API key"})
+			log.Printf("serve: %s %s 401", r.Method, r.URL.Path)
+			return
+		}
+		start := time.Now()
+		next(w, r)
+		log.Printf("serve: %s %s (%s)", r.Method, r.URL.Path, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// writeError reports err as {"error": ...}, adding the decoded program error
+// the same way `transfer` does on the command line.
+func writeError(w http.ResponseWriter, status int, err error) {
+	out := map[string]any{"error": err.Error()}
+	var pe *anchor.ProgramError
+	if errors.As(err, &pe) {
+		out["programError"] = pe
+	}
+	writeJSON(w, status, out)
+}
+
+// decodeBody parses a JSON request body, rejecting unknown fields.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return nil
+}
+
+// handleBalance serves GET /balance/{address}?tokens=true.
+func (g *gateway) handleBalance(w http.ResponseWriter, r *http.Request) {
+	address := r.PathValue("address")
+	if !isValidBase58Pubkey(address) {
+		writeError(w, http.StatusBadRequest, errors.New("invalid address"))
+		return
+	}
+	tokens := false
+	if v := r.URL.Query().Get("tokens"); v != "" {
+		var err error
+		if tokens, err = strconv.ParseBool(v); err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("tokens must be true or false"))
+			return
+		}
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	out, err := fetchBalance(ctx, g.c, g.cluster, address, tokens)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleTransfer serves POST /transfer {"to", "lamports", "reference"?, "memo"?},
+// paid by the gateway's signer.
+func (g *gateway) handleTransfer(w http.ResponseWriter, r *http.Request) {
+	if g.cfg.Signer == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("no signer configured; start serve with --from or --fromFile"))
+		return
+	}
+	var body struct {
+		To        string `json:"to"`
+		Lamports  uint64 `json:"lamports"`
+		Reference string `json:"reference"`
+		Memo      string `json:"memo"`
+	}
+	if err := decodeBody(w, r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var problems []string
+	if !isValidBase58Pubkey(body.To) {
+		problems = append(problems, "to must be a base58 address")
+	}
+	if body.Lamports == 0 {
+		problems = append(problems, "lamports must be greater than 0")
+	}
+	if g.cfg.MaxLamports > 0 && body.Lamports > g.cfg.MaxLamports {
+		problems = append(problems, fmt.Sprintf("lamports must be at most %d", g.cfg.MaxLamports))
+	}
+	if body.Reference != "" && !isValidBase58Pubkey(body.Reference) {
+		problems = append(problems, "reference must be a base58 address")
+	}
+	if len(body.Memo) > maxMemoLen {
+		problems = append(problems, fmt.Sprintf("memo must be at most %d bytes", maxMemoLen))
+	}
+	if len(problems) > 0 {
+		writeError(w, http.StatusBadRequest, errors.New(strings.Join(problems, "; ")))
+		return
+	}
+	var reference *common.PublicKey
+	if body.Reference != "" {
+		pk := common.PublicKeyFromString(body.Reference)
+		reference = &pk
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 25*time.Second)
+	defer cancel()
+	out, err := sendTransfer(ctx, g.c, g.cluster, *g.cfg.Signer, common.PublicKeyFromString(body.To), body.Lamports, reference, body.Memo)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleAirdrop serves POST /airdrop {"to", "lamports"?}; lamports defaults to 1 SOL.
+func (g *gateway) handleAirdrop(w http.ResponseWriter, r *http.Request) {
+	if g.cluster == "mainnet" {
+		writeError(w, http.StatusBadRequest, errors.New("airdrops are not available on mainnet"))
+		return
+	}
+	var body struct {
+		To       string `json:"to"`
+		Lamports uint64 `json:"lamports"`
+	}
+	if err := decodeBody(w, r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !isValidBase58Pubkey(body.To) {
+		writeError(w, http.StatusBadRequest, errors.New("to must be a base58 address"))
+		return
+	}
+	if body.Lamports == 0 {
+		body.Lamports = lamportsPerSOL
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+	defer cancel()
+	out, err := requestAirdrop(ctx, g.c, g.cluster, body.To, body.Lamports)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleTx serves GET /tx/{sig}.
+func (g *gateway) handleTx(w http.ResponseWriter, r *http.Request) {
+	sig := r.PathValue("sig")
+	if b, err := base58.Decode(sig); err != nil || len(b) != 64 {
+		writeError(w, http.StatusBadRequest, errors.New("invalid transaction signature"))
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	out, err := fetchTransaction(ctx, g.c, sig)
+	switch {
+	case errors.Is(err, errTxNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case err != nil:
+		writeError(w, http.StatusBadGateway, err)
+	default:
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPIDocument)
+}
+
+// routes registers the gateway endpoints; only the OpenAPI document is
+// served without an API key.
+func (g *gateway) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", handleOpenAPI)
+	mux.HandleFunc("GET /balance/{address}", g.withAuth(g.handleBalance))
+	mux.HandleFunc("POST /transfer", g.withAuth(g.handleTransfer))
+	mux.HandleFunc("POST /airdrop", g.withAuth(g.handleAirdrop))
+	mux.HandleFunc("GET /tx/{sig}", g.withAuth(g.handleTx))
+	return mux
+}
+
+// runServe serves the REST gateway until interrupted.
+func runServe(cfg gatewayConfig, cluster, rpcOverride string) error {
+	g := &gateway{cfg: cfg, cluster: cluster, c: client.NewClient(resolveEndpoint(cluster, rpcOverride))}
+	srv := &http.Server{Addr: cfg.Listen, Handler: g.routes(), ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+	signer := "none (POST /transfer disabled)"
+	if cfg.Signer != nil {
+		signer = cfg.Signer.PublicKey.ToBase58()
+	}
+	log.Printf("serve: %s, signer %s, listening on %s", cluster, signer, cfg.Listen)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// newTestGateway serves the gateway routes against a bank simulator with a
+// funded signer and the API key "good-key".
+func newTestGateway(t *testing.T, cfg gatewayConfig) (*gateway, *httptest.Server) {
+	t.Helper()
+	bank, _, c := newTestBank(t)
+	signer := types.NewAccount()
+	bank.Fund(signer.PublicKey, 10_000_000_000)
+	cfg.APIKeys = []string{"other-key", "good-key"}
+	cfg.Signer = &signer
+	g := &gateway{cfg: cfg, cluster: "local", c: c}
+	srv := httptest.NewServer(g.routes())
+	t.Cleanup(srv.Close)
+	return g, srv
+}
+
+// call sends a request with the given headers and returns the status code
+// and the decoded JSON response.
+func call(t *testing.T, srv *httptest.Server, method, path string, body any, header map[string]string) (int, map[string]any) {
+	t.Helper()
+	var data []byte
+	if s, ok := body.(string); ok {
+		data = []byte(s)
+	} else if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, srv.URL+path, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var out map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("%s %s: invalid response: %v", method, path, err)
+	}
+	return resp.StatusCode, out
+}
+
+var bearer = map[string]string{"Authorization": "Bearer good-key"}
+
+func TestGatewayAuth(t *testing.T) {
+	_, srv := newTestGateway(t, gatewayConfig{})
+	path := "/balance/" + types.NewAccount().PublicKey.ToBase58()
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+	}{
+		{"no key", nil, http.StatusUnauthorized},
+		{"wrong bearer", map[string]string{"Authorization": "Bearer bad-key"}, http.StatusUnauthorized},
+		{"wrong X-API-Key", map[string]string{"X-API-Key": "bad-key"}, http.StatusUnauthorized},
+		{"not a bearer token", map[string]string{"Authorization": "Basic good-key"}, http.StatusUnauthorized},
+		{"empty bearer", map[string]string{"Authorization": "Bearer "}, http.StatusUnauthorized},
+		{"key prefix", map[string]string{"X-API-Key": "good"}, http.StatusUnauthorized},
+		// X-API-Key takes precedence over Authorization.
+		{"wrong X-API-Key with a good bearer", map[string]string{"X-API-Key": "bad-key", "Authorization": "Bearer good-key"}, http.StatusUnauthorized},
+		{"bearer", bearer, http.StatusOK},
+		{"X-API-Key", map[string]string{"X-API-Key": "good-key"}, http.StatusOK},
+		{"second configured key", map[string]string{"X-API-Key": "other-key"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out := call(t, srv, http.MethodGet, path, nil, tt.header)
+			if code != tt.want {
+				t.Fatalf("status %d, want %d: %v", code, tt.want, out)
+			}
+			if code == http.StatusUnauthorized && out["error"] != "missing or invalid API key" {
+				t.Fatalf("error %v", out["error"])
+			}
+		})
+	}
+
+	// Every other endpoint is behind the same check.
+	for _, r := range []struct{ method, path string }{
+		{http.MethodPost, "/transfer"},
+		{http.MethodPost, "/airdrop"},
+		{http.MethodGet, "/tx/" + strings.Repeat("1", 64)},
+	} {
+		if code, _ := call(t, srv, r.method, r.path, map[string]any{}, nil); code != http.StatusUnauthorized {
+			t.Fatalf("%s %s without a key: status %d", r.method, r.path, code)
+		}
+	}
+}
+
+func TestGatewayOpenAPIIsPublic(t *testing.T) {
+	_, srv := newTestGateway(t, gatewayConfig{})
+	resp, err := srv.Client().Get(srv.URL + "/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	var doc struct {
+		OpenAPI string         `json:"openapi"`
+		Paths   map[string]any `json:"paths"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI == "" || doc.Paths["/transfer"] == nil {
+		t.Fatalf("unexpected document %+v", doc)
+	}
+}
+
+func TestGatewayTransfer(t *testing.T) {
+	g, srv := newTestGateway(t, gatewayConfig{MaxLamports: 1_000_000})
+	to := types.NewAccount().PublicKey.ToBase58()
+
+	code, out := call(t, srv, http.MethodPost, "/transfer", map[string]any{"to": to, "lamports": 1_000_000, "memo": "order-7"}, bearer)
+	if code != http.StatusOK {
+		t.Fatalf("status %d: %v", code, out)
+	}
+	if out["to"] != to || out["from"] != g.cfg.Signer.PublicKey.ToBase58() || out["amount"] != float64(1_000_000) || out["memo"] != "order-7" || out["txhash"] == "" {
+		t.Fatalf("unexpected result %v", out)
+	}
+
+	code, out = call(t, srv, http.MethodPost, "/transfer", map[string]any{"to": to, "lamports": 1_000_001}, bearer)
+	if code != http.StatusBadRequest || out["error"] != "lamports must be at most 1000000" {
+		t.Fatalf("over MaxLamports: status %d, %v", code, out)
+	}
+}
+
+func TestGatewayTransferValidation(t *testing.T) {
+	_, srv := newTestGateway(t, gatewayConfig{})
+	to := types.NewAccount().PublicKey.ToBase58()
+	tests := []struct {
+		name string
+		body any
+		want string
+	}{
+		{"invalid JSON", "{", "invalid JSON body"},
+		{"unknown field", map[string]any{"to": to, "lamports": 1, "amount": 1}, "unknown field"},
+		{"bad address", map[string]any{"to": "not-an-address", "lamports": 1}, "to must be a base58 address"},
+		{"zero lamports", map[string]any{"to": to}, "lamports must be greater than 0"},
+		{"bad reference", map[string]any{"to": to, "lamports": 1, "reference": "0OIl"}, "reference must be a base58 address"},
+		{"long memo", map[string]any{"to": to, "lamports": 1, "memo": strings.Repeat("m", maxMemoLen+1)}, "memo must be at most 256 bytes"},
+		{"every problem at once", map[string]any{"to": "", "memo": strings.Repeat("m", maxMemoLen+1)},
+			"to must be a base58 address; lamports must be greater than 0; memo must be at most 256 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out := call(t, srv, http.MethodPost, "/transfer", tt.body, bearer)
+			msg, _ := out["error"].(string)
+			if code != http.StatusBadRequest || !strings.Contains(msg, tt.want) {
+				t.Fatalf("status %d, error %q; want 400 containing %q", code, msg, tt.want)
+			}
+		})
+	}
+}
+
+func TestGatewayTransferWithoutSigner(t *testing.T) {
+	g, srv := newTestGateway(t, gatewayConfig{})
+	g.cfg.Signer = nil
+	body := map[string]any{"to": types.NewAccount().PublicKey.ToBase58(), "lamports": 1}
+	if code, out := call(t, srv, http.MethodPost, "/transfer", body, bearer); code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d: %v", code, out)
+	}
+}
+
+func TestGatewayRequestValidation(t *testing.T) {
+	g, srv := newTestGateway(t, gatewayConfig{})
+	tests := []struct {
+		name, method, path string
+		body               any
+		want               string
+	}{
+		{"balance address", http.MethodGet, "/balance/not-an-address", nil, "invalid address"},
+		{"balance tokens flag", http.MethodGet, "/balance/" + types.NewAccount().PublicKey.ToBase58() + "?tokens=maybe", nil, "tokens must be true or false"},
+		{"airdrop address", http.MethodPost, "/airdrop", map[string]any{"to": "nope"}, "to must be a base58 address"},
+		{"airdrop unknown field", http.MethodPost, "/airdrop", map[string]any{"address": "nope"}, "unknown field"},
+		{"tx signature", http.MethodGet, "/tx/abc", nil, "invalid transaction signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out := call(t, srv, tt.method, tt.path, tt.body, bearer)
+			msg, _ := out["error"].(string)
+			if code != http.StatusBadRequest || !strings.Contains(msg, tt.want) {
+				t.Fatalf("status %d, error %q; want 400 containing %q", code, msg, tt.want)
+			}
+		})
+	}
+
+	g.cluster = "mainnet"
+	code, out := call(t, srv, http.MethodPost, "/airdrop", map[string]any{"to": types.NewAccount().PublicKey.ToBase58()}, bearer)
+	if code != http.StatusBadRequest || out["error"] != "airdrops are not available on mainnet" {
+		t.Fatalf("mainnet airdrop: status %d, %v", code, out)
+	}
+}
+
+func TestGatewayAirdropDefault(t *testing.T) {
+	_, srv := newTestGateway(t, gatewayConfig{})
+	to := types.NewAccount().PublicKey.ToBase58()
+	code, out := call(t, srv, http.MethodPost, "/airdrop", map[string]any{"to": to}, bearer)
+	if code != http.StatusOK || out["lamports"] != float64(lamportsPerSOL) || out["to"] != to {
+		t.Fatalf("status %d: %v", code, out)
+	}
+}
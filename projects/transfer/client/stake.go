@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/stake"
+	"github.com/blocto/solana-go-sdk/program/sysprog"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/borsh"
+)
+
+// StakeStateV2 variants (bincode u32 tag).
+const (
+	stakeStateUninitialized uint32 = iota
+	stakeStateInitialized
+	stakeStateStake
+	stakeStateRewardsPool
+)
+
+// stakeState is the decoded part of a stake account that `stake show` prints.
+type stakeState struct {
+	State             string `json:"state"`
+	RentExemptReserve uint64 `json:"rentExemptReserve"`
+	Staker            string `json:"staker"`
+	Withdrawer        string `json:"withdrawer"`
+	LockupUnixTime    int64  `json:"lockupUnixTimestamp"`
+	LockupEpoch       uint64 `json:"lockupEpoch"`
+	Custodian         string `json:"custodian"`
+	Voter             string `json:"voter,omitempty"`
+	Stake             uint64 `json:"stake,omitempty"`
+	ActivationEpoch   uint64 `json:"activationEpoch,omitempty"`
+	// DeactivationEpoch is math.MaxUint64 until the stake is deactivated.
+	DeactivationEpoch uint64 `json:"deactivationEpoch,omitempty"`
+	CreditsObserved   uint64 `json:"creditsObserved,omitempty"`
+}
+
+// decodeStakeState decodes the bincode StakeStateV2 layout:
+// u32 tag, Meta{rent_exempt_reserve, Authorized, Lockup}, then for delegated
+// accounts Stake{Delegation{voter, stake, activation, deactivation, rate}, credits}.
+func decodeStakeState(data []byte) (stakeState, error) {
+	d := borsh.NewDecoder(data)
+	var s stakeState
+	tag := d.ReadU32()
+	switch tag {
+	case stakeStateUninitialized:
+		s.State = "uninitialized"
+		return s, d.Err()
+	case stakeStateRewardsPool:
+		s.State = "rewardsPool"
+		return s, d.Err()
+	case stakeStateInitialized:
+		s.State = "initialized"
+	case stakeStateStake:
+		s.State = "delegated"
+	default:
+		return s, fmt.Errorf("unknown stake state %d", tag)
+	}
+	s.RentExemptReserve = d.ReadU64()
+	s.Staker = d.ReadPubkey().ToBase58()
+	s.Withdrawer = d.ReadPubkey().ToBase58()
+	s.LockupUnixTime = d.ReadI64()
+	s.LockupEpoch = d.ReadU64()
+	s.Custodian = d.ReadPubkey().ToBase58()
+	if tag == stakeStateStake {
+		s.Voter = d.ReadPubkey().ToBase58()
+		s.Stake = d.ReadU64()
+		s.ActivationEpoch = d.ReadU64()
+		s.DeactivationEpoch = d.ReadU64()
+		_ = d.ReadF64() // warmup_cooldown_rate, unused since the 1.16 rate change
+		s.CreditsObserved = d.ReadU64()
+	}
+	if err := d.Err(); err != nil {
+		return stakeState{}, fmt.Errorf("decode stake account: %w", err)
+	}
+	return s, nil
+}
+
+// activationStatus derives the delegation status from its epochs. It ignores
+// the cluster-wide warmup/cooldown limit, so a large stake can stay
+// activating or deactivating for more epochs than reported here.
+func activationStatus(s stakeState, epoch uint64) string {
+	if s.State != "delegated" {
+		return "inactive"
+	}
+	switch {
+	case s.ActivationEpoch == s.DeactivationEpoch:
+		// Deactivated in the epoch it was delegated: never became active.
+		return "inactive"
+	case s.DeactivationEpoch != math.MaxUint64 && epoch > s.DeactivationEpoch:
+		return "inactive"
+	case s.DeactivationEpoch != math.MaxUint64:
+		return "deactivating"
+	case epoch <= s.ActivationEpoch:
+		return "activating"
+	default:
+		return "active"
+	}
+}
+
+// runStakeCreate creates a stake account derived from the signer and seed,
+// initializes it with the signer as staker and withdrawer, and delegates it
+// to the vote account. The account holds amount plus its rent-exempt reserve.
+func runStakeCreate(fromPrivBase58, fromFilePath, amount, validatorBase58, seed string, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	owner, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	lamports, err := parseTokenAmount(amount, 9)
+	if err != nil {
+		return err
+	}
+	if seed == "" {
+		seed = fmt.Sprintf("stake:%d", time.Now().Unix())
+	}
+	if len(seed) > 32 {
+		return errors.New("--seed must be at most 32 bytes")
+	}
+	validator := common.PublicKeyFromString(validatorBase58)
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	vote, err := c.GetAccountInfo(ctx, validator.ToBase58())
+	if err != nil {
+		return fmt.Errorf("failed to get vote account: %w", err)
+	}
+	if vote.Owner != common.VoteProgramID {
+		return fmt.Errorf("%s is not a vote account", validator.ToBase58())
+	}
+	rent, err := c.GetMinimumBalanceForRentExemption(ctx, stake.AccountSize)
+	if err != nil {
+		return fmt.Errorf("failed to get rent-exempt minimum: %w", err)
+	}
+
+	stakeAccount := common.CreateWithSeed(owner.PublicKey, seed, common.StakeProgramID)
+	ixs := []types.Instruction{
+		sysprog.CreateAccountWithSeed(sysprog.CreateAccountWithSeedParam{
+			From:     owner.PublicKey,
+			New:      stakeAccount,
+			Base:     owner.PublicKey,
+			Owner:    common.StakeProgramID,
+			Seed:     seed,
+			Lamports: lamports + rent,
+			Space:    stake.AccountSize,
+		}),
+		stake.Initialize(stake.InitializeParam{
+			Stake: stakeAccount,
+			Auth: stake.Authorized{
+				Staker:     owner.PublicKey,
+				Withdrawer: owner.PublicKey,
+			},
+		}),
+		stake.DelegateStake(stake.DelegateStakeParam{
+			Stake: stakeAccount,
+			Auth:  owner.PublicKey,
+			Vote:  validator,
+		}),
+	}
+	txhash, err := sendAndConfirm(ctx, c, owner, ixs)
+	if err != nil {
+		return err
+	}
+
+	out := map[string]any{
+		"cluster":      cluster,
+		"txhash":       txhash,
+		"stake":        stakeAccount.ToBase58(),
+		"seed":         seed,
+		"validator":    validator.ToBase58(),
+		"lamports":     lamports,
+		"rentLamports": rent,
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// runStakeDeactivate starts cooling down a delegated stake; it becomes
+// withdrawable once the deactivation epoch has passed.
+func runStakeDeactivate(fromPrivBase58, fromFilePath, stakeBase58 string, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	staker, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	stakeAccount := common.PublicKeyFromString(stakeBase58)
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	s, _, err := fetchStake(ctx, c, stakeAccount)
+	if err != nil {
+		return err
+	}
+	if s.State != "delegated" {
+		return fmt.Errorf("stake account %s is %s, not delegated", stakeBase58, s.State)
+	}
+
+	ix := stake.Deactivate(stake.DeactivateParam{
+		Stake: stakeAccount,
+		Auth:  staker.PublicKey,
+	})
+	txhash, err := sendAndConfirm(ctx, c, staker, []types.Instruction{ix})
+	if err != nil {
+		return err
+	}
+
+	out := map[string]any{
+		"cluster": cluster,
+		"txhash":  txhash,
+		"stake":   stakeAccount.ToBase58(),
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// runStakeWithdraw moves lamports out of an inactive stake account; zero
+// lamports withdraws the whole balance, which closes the account.
+func runStakeWithdraw(fromPrivBase58, fromFilePath, stakeBase58, toBase58 string, lamports uint64, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	withdrawer, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	stakeAccount := common.PublicKeyFromString(stakeBase58)
+	to := withdrawer.PublicKey
+	if toBase58 != "" {
+		to = common.PublicKeyFromString(toBase58)
+	}
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	_, balance, err := fetchStake(ctx, c, stakeAccount)
+	if err != nil {
+		return err
+	}
+	if lamports == 0 {
+		lamports = balance
+	}
+
+	ix := stake.Withdraw(stake.WithdrawParam{
+		Stake:    stakeAccount,
+		Auth:     withdrawer.PublicKey,
+		To:       to,
+		Lamports: lamports,
+	})
+	txhash, err := sendAndConfirm(ctx, c, withdrawer, []types.Instruction{ix})
+	if err != nil {
+		return err
+	}
+
+	out := map[string]any{
+		"cluster":  cluster,
+		"txhash":   txhash,
+		"stake":    stakeAccount.ToBase58(),
+		"to":       to.ToBase58(),
+		"lamports": lamports,
+		"closed":   lamports == balance,
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// stakeReward is one epoch's inflation reward paid to a stake account.
+type stakeReward struct {
+	Epoch         uint64 `json:"epoch"`
+	EffectiveSlot uint64 `json:"effectiveSlot"`
+	Amount        uint64 `json:"amount"`
+	PostBalance   uint64 `json:"postBalance"`
+	Commission    *uint8 `json:"commission,omitempty"`
+}
+
+// runStakeShow prints the decoded stake account, its activation status in the
+// current epoch and the inflation rewards of the last `epochs` epochs.
+func runStakeShow(stakeBase58 string, epochs uint64, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	stakeAccount := common.PublicKeyFromString(stakeBase58)
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	s, balance, err := fetchStake(ctx, c, stakeAccount)
+	if err != nil {
+		return err
+	}
+	info, err := c.GetEpochInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get epoch info: %w", err)
+	}
+
+	// Rewards for an epoch are paid at the start of the next one, so the
+	// current epoch has none yet. Epoch 0 cannot be requested (omitempty).
+	rewards := []stakeReward{}
+	for e := info.Epoch; e > 1 && info.Epoch-e < epochs; e-- {
+		epoch := e - 1
+		if s.State == "delegated" && epoch < s.ActivationEpoch {
+			break
+		}
+		res, err := c.RpcClient.GetInflationRewardWithConfig(ctx, []string{stakeAccount.ToBase58()}, rpc.GetInflationRewardConfig{
+			Commitment: rpc.CommitmentConfirmed,
+			Epoch:      epoch,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get inflation reward for epoch %d: %w", epoch, err)
+		}
+		if res.Error != nil {
+			// Nodes without the epoch's blocks reject older requests; keep what we have.
+			break
+		}
+		if len(res.Result) == 0 || res.Result[0] == nil {
+			continue
+		}
+		r := res.Result[0]
+		rewards = append(rewards, stakeReward{
+			Epoch:         r.Epoch,
+			EffectiveSlot: r.EffectiveSlot,
+			Amount:        r.Amount,
+			PostBalance:   r.PostBalance,
+			Commission:    r.Commission,
+		})
+	}
+
+	out := map[string]any{
+		"cluster":  cluster,
+		"stake":    stakeAccount.ToBase58(),
+		"lamports": balance,
+		"epoch":    info.Epoch,
+		"status":   activationStatus(s, info.Epoch),
+		"account":  s,
+		"rewards":  rewards,
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// fetchStake loads and decodes a stake account and returns its lamport balance.
+func fetchStake(ctx context.Context, c *client.Client, stakeAccount common.PublicKey) (stakeState, uint64, error) {
+	info, err := c.GetAccountInfo(ctx, stakeAccount.ToBase58())
+	if err != nil {
+		return stakeState{}, 0, fmt.Errorf("failed to get stake account: %w", err)
+	}
+	if info.Owner != common.StakeProgramID {
+		return stakeState{}, 0, fmt.Errorf("%s is not a stake account", stakeAccount.ToBase58())
+	}
+	s, err := decodeStakeState(info.Data)
+	if err != nil {
+		return stakeState{}, 0, err
+	}
+	return s, info.Lamports, nil
+}
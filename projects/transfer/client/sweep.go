@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/sysprog"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/txbuilder"
+)
+
+// sweepEntry is the report line for one keypair file.
+type sweepEntry struct {
+	File     string `json:"file"`
+	Address  string `json:"address,omitempty"`
+	Balance  uint64 `json:"balance"`
+	Swept    uint64 `json:"swept"`
+	Fee      uint64 `json:"fee"` // paid by this key as fee payer of its batch
+	TxHash   string `json:"txhash,omitempty"`
+	Status   string `json:"status"` // swept | skipped | failed | planned
+	Reason   string `json:"reason,omitempty"`
+	account  types.Account
+	transfer uint64 // lamports above what must stay behind
+}
+
+// runSweep moves the SOL of every keypair under keysDir to the treasury.
+// Without closeAccounts each key keeps the rent-exempt minimum so the account
+// stays open; with it the whole balance is moved and the account is deleted.
+// Transfers are packed several per transaction. The fees come from feePayer
+// when given, otherwise from the richest key of each batch, which must stay
+// rent-exempt after paying the fee. It reports how many keys failed so the
+// caller can set the exit code.
+func runSweep(keysDir string, to common.PublicKey, feePayer *types.Account, closeAccounts, dryRun bool, cluster, rpcOverride string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+
+	entries, err := loadSweepKeys(keysDir, to)
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, fmt.Errorf("no keypair files in %s", keysDir)
+	}
+	rentMin, err := c.GetMinimumBalanceForRentExemption(ctx, 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rent-exempt minimum: %w", err)
+	}
+	keep := rentMin
+	if closeAccounts {
+		keep = 0
+	}
+	if err := fetchSweepBalances(ctx, c, entries, keep); err != nil {
+		return 0, err
+	}
+
+	var ready []*sweepEntry
+	for _, e := range entries {
+		if e.Status == "" {
+			ready = append(ready, e)
+		}
+	}
+	// Richest first, so the first key of every batch can cover its fee.
+	sort.SliceStable(ready, func(i, j int) bool { return ready[i].transfer > ready[j].transfer })
+
+	var fees uint64
+	if len(ready) > 0 {
+		ixs := make([]types.Instruction, len(ready))
+		for i, e := range ready {
+			ixs[i] = sysprog.Transfer(sysprog.TransferParam{From: e.account.PublicKey, To: to, Amount: e.transfer})
+		}
+		payer := ready[0].account.PublicKey
+		if feePayer != nil {
+			payer = feePayer.PublicKey
+		}
+		batches, err := txbuilder.PackInstructions(payer, ixs)
+		if err != nil {
+			return 0, err
+		}
+		next := 0
+		for _, batch := range batches {
+			fees += sweepBatch(ctx, c, ready[next:next+len(batch)], to, feePayer, rentMin, dryRun)
+			next += len(batch)
+		}
+	}
+
+	var swept uint64
+	failed := 0
+	counts := map[string]int{}
+	for _, e := range entries {
+		counts[e.Status]++
+		swept += e.Swept
+		if e.Status == "failed" {
+			failed++
+		}
+	}
+	out := map[string]any{
+		"cluster": cluster,
+		"to":      to.ToBase58(),
+		"close":   closeAccounts,
+		"dryRun":  dryRun,
+		"keys":    len(entries),
+		"swept":   swept,
+		"fees":    fees,
+		"counts":  counts,
+		"entries": entries,
+	}
+	if feePayer != nil {
+		out["feePayer"] = feePayer.PublicKey.ToBase58()
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return failed, enc.Encode(out)
+}
+
+// loadSweepKeys loads every *.json keypair under dir (e.g. deposits/<name>/id.json),
+// in path order. Unreadable files become failed entries instead of aborting.
+func loadSweepKeys(dir string, to common.PublicKey) ([]*sweepEntry, error) {
+	var entries []*sweepEntry
+	seen := map[common.PublicKey]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+		e := &sweepEntry{File: path}
+		entries = append(entries, e)
+		acc, err := loadAccountFromFile(path)
+		if err != nil {
+			e.Status, e.Reason = "failed", fmt.Sprintf("invalid keypair file: %v", err)
+			return nil
+		}
+		e.account, e.Address = acc, acc.PublicKey.ToBase58()
+		switch {
+		case acc.PublicKey == to:
+			e.Status, e.Reason = "skipped", "key is the destination"
+		case seen[acc.PublicKey] != "":
+			e.Status, e.Reason = "skipped", "same key as "+seen[acc.PublicKey]
+		default:
+			seen[acc.PublicKey] = path
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// fetchSweepBalances fills in balances and marks keys with nothing to sweep
+// (or that are not plain system accounts) as skipped.
+func fetchSweepBalances(ctx context.Context, c *client.Client, entries []*sweepEntry, keep uint64) error {
+	var pending []*sweepEntry
+	for _, e := range entries {
+		if e.Status == "" {
+			pending = append(pending, e)
+		}
+	}
+	// getMultipleAccounts takes at most 100 addresses per call.
+	for start := 0; start < len(pending); start += 100 {
+		chunk := pending[start:min(start+100, len(pending))]
+		addrs := make([]string, len(chunk))
+		for i, e := range chunk {
+			addrs[i] = e.Address
+		}
+		infos, err := c.GetMultipleAccounts(ctx, addrs)
+		if err != nil {
+			return fmt.Errorf("failed to get balances: %w", err)
+		}
+		for i, info := range infos {
+			e := chunk[i]
+			e.Balance = info.Lamports
+			switch {
+			case info.Lamports == 0:
+				e.Status, e.Reason = "skipped", "empty account"
+			case info.Owner != common.SystemProgramID || len(info.Data) > 0:
+				e.Status, e.Reason = "skipped", fmt.Sprintf("not a plain system account (owner %s)", info.Owner.ToBase58())
+			case info.Lamports <= keep:
+				e.Status, e.Reason = "skipped", "balance at or below the rent-exempt minimum"
+			default:
+				e.transfer = info.Lamports - keep
+			}
+		}
+	}
+	return nil
+}
+
+// sweepBatch sends one transaction moving every entry's balance to the
+// treasury and returns the fee paid. Without feePayer the first entry pays
+// the fee, so its transfer is reduced by it.
+func sweepBatch(ctx context.Context, c *client.Client, batch []*sweepEntry, to common.PublicKey, feePayer *types.Account, rentMin uint64, dryRun bool) uint64 {
+	fail := func(reason string) uint64 {
+		for _, e := range batch {
+			e.Status, e.Reason, e.Swept, e.Fee = "failed", reason, 0, 0
+		}
+		return 0
+	}
+	ixs := func() []types.Instruction {
+		out := make([]types.Instruction, len(batch))
+		for i, e := range batch {
+			out[i] = sysprog.Transfer(sysprog.TransferParam{From: e.account.PublicKey, To: to, Amount: e.Swept})
+		}
+		return out
+	}
+	for _, e := range batch {
+		e.Swept = e.transfer
+	}
+	payer, signers := batch[0].account, []types.Account{}
+	for _, e := range batch[1:] {
+		signers = append(signers, e.account)
+	}
+	if feePayer != nil {
+		payer, signers = *feePayer, append(signers, batch[0].account)
+	}
+
+	latest, err := c.GetLatestBlockhash(ctx)
+	if err != nil {
+		return fail(fmt.Sprintf("failed to get blockhash: %v", err))
+	}
+	fee, err := c.GetFeeForMessage(ctx, txbuilder.NewMessage(payer.PublicKey, latest.Blockhash, ixs()))
+	if err != nil || fee == nil {
+		return fail(fmt.Sprintf("failed to estimate fee: %v", err))
+	}
+	if feePayer == nil {
+		// The fee is charged before the transfers run, and the payer must
+		// still be rent-exempt at that point.
+		first := batch[0]
+		if first.transfer <= *fee || first.Balance < *fee+rentMin {
+			return fail(fmt.Sprintf("%s cannot pay the %d lamport fee and stay rent-exempt; use --fee-payer", first.Address, *fee))
+		}
+		first.Swept, first.Fee = first.transfer-*fee, *fee
+	}
+
+	if dryRun {
+		for _, e := range batch {
+			e.Status = "planned"
+		}
+		return *fee
+	}
+	txhash, err := txbuilder.New(c).FeePayer(payer).Signers(signers...).Add(ixs()...).SendAndConfirm(ctx)
+	if err != nil {
+		return fail(err.Error())
+	}
+	for _, e := range batch {
+		e.Status, e.TxHash = "swept", txhash
+	}
+	return *fee
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/banksim"
+)
+
+// writeKeypair stores acc as a solana-keygen JSON file under dir.
+func writeKeypair(t *testing.T, dir, name string, acc types.Account) {
+	t.Helper()
+	ints := make([]int, len(acc.PrivateKey))
+	for i, b := range acc.PrivateKey {
+		ints[i] = int(b)
+	}
+	data, err := json.Marshal(ints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type sweepReport struct {
+	Swept   uint64         `json:"swept"`
+	Fees    uint64         `json:"fees"`
+	Counts  map[string]int `json:"counts"`
+	Entries []sweepEntry   `json:"entries"`
+}
+
+// sweepWithReport runs runSweep against url and decodes the report it prints.
+func sweepWithReport(t *testing.T, keysDir string, to types.Account, feePayer *types.Account, closeAccounts, dryRun bool, url string) (int, sweepReport) {
+	t.Helper()
+	out, err := os.CreateTemp(t.TempDir(), "report-*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	stdout := os.Stdout
+	os.Stdout = out
+	failed, err := runSweep(keysDir, to.PublicKey, feePayer, closeAccounts, dryRun, "local", url)
+	os.Stdout = stdout
+	if err != nil {
+		t.Fatalf("runSweep: %v", err)
+	}
+	if _, err := out.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	var report sweepReport
+	if err := json.NewDecoder(out).Decode(&report); err != nil {
+		t.Fatalf("invalid report: %v", err)
+	}
+	return failed, report
+}
+
+// sweepFixture funds two keys, leaves one empty and adds an unreadable file.
+func sweepFixture(t *testing.T, bank *banksim.Bank) (dir string, rich, poor types.Account) {
+	t.Helper()
+	dir = t.TempDir()
+	rich, poor, empty := types.NewAccount(), types.NewAccount(), types.NewAccount()
+	bank.Fund(rich.PublicKey, 1_000_000_000)
+	bank.Fund(poor.PublicKey, 500_000_000)
+	writeKeypair(t, dir, "rich/id.json", rich)
+	writeKeypair(t, dir, "poor/id.json", poor)
+	writeKeypair(t, dir, "empty/id.json", empty)
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("not a keypair"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return dir, rich, poor
+}
+
+func TestSweepKeepsRentExemptMinimum(t *testing.T) {
+	bank, srv, _ := newTestBank(t)
+	dir, rich, poor := sweepFixture(t, bank)
+	treasury := types.NewAccount()
+	rent := banksim.RentExemptMinimum(0)
+
+	failed, report := sweepWithReport(t, dir, treasury, nil, false, false, srv.URL)
+	// Only the unreadable file fails.
+	if failed != 1 || report.Counts["swept"] != 2 || report.Counts["skipped"] != 1 {
+		t.Fatalf("failed=%d counts=%v", failed, report.Counts)
+	}
+	// The richest key pays one signature fee per key in the batch.
+	fee := uint64(2 * banksim.DefaultLamportsPerSignature)
+	want := 1_500_000_000 - 2*rent - fee
+	if report.Swept != want || report.Fees != fee {
+		t.Fatalf("report swept %d with fees %d, want %d with %d", report.Swept, report.Fees, want, fee)
+	}
+	if got := bank.Balance(treasury.PublicKey); got != want {
+		t.Fatalf("treasury has %d lamports, want %d", got, want)
+	}
+	for _, acc := range []types.Account{rich, poor} {
+		if got := bank.Balance(acc.PublicKey); got != rent {
+			t.Fatalf("%s has %d lamports, want the rent-exempt %d", acc.PublicKey.ToBase58(), got, rent)
+		}
+	}
+}
+
+func TestSweepCloseWithFeePayer(t *testing.T) {
+	bank, srv, _ := newTestBank(t)
+	dir, rich, poor := sweepFixture(t, bank)
+	treasury, payer := types.NewAccount(), types.NewAccount()
+	bank.Fund(payer.PublicKey, 1_000_000_000)
+
+	_, report := sweepWithReport(t, dir, treasury, &payer, true, false, srv.URL)
+	if report.Counts["swept"] != 2 || report.Swept != 1_500_000_000 {
+		t.Fatalf("swept %d lamports, counts %v", report.Swept, report.Counts)
+	}
+	if got := bank.Balance(treasury.PublicKey); got != 1_500_000_000 {
+		t.Fatalf("treasury has %d lamports, want 1500000000", got)
+	}
+	for _, acc := range []types.Account{rich, poor} {
+		if _, ok := bank.GetAccount(acc.PublicKey); ok {
+			t.Fatalf("%s was not closed", acc.PublicKey.ToBase58())
+		}
+	}
+	fee := uint64(3 * banksim.DefaultLamportsPerSignature)
+	if got := bank.Balance(payer.PublicKey); got != 1_000_000_000-fee {
+		t.Fatalf("fee payer has %d lamports, want %d", got, 1_000_000_000-fee)
+	}
+}
+
+func TestSweepDryRun(t *testing.T) {
+	bank, srv, _ := newTestBank(t)
+	dir, rich, _ := sweepFixture(t, bank)
+	treasury := types.NewAccount()
+
+	_, report := sweepWithReport(t, dir, treasury, nil, true, true, srv.URL)
+	if report.Counts["planned"] != 2 {
+		t.Fatalf("counts %v, want 2 planned", report.Counts)
+	}
+	if got := bank.Balance(treasury.PublicKey); got != 0 {
+		t.Fatalf("dry run moved %d lamports", got)
+	}
+	if got := bank.Balance(rich.PublicKey); got != 1_000_000_000 {
+		t.Fatalf("dry run charged the payer: %d lamports left", got)
+	}
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/associated_token_account"
+	"github.com/blocto/solana-go-sdk/program/sysprog"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/txbuilder"
+)
+
+// tokenBalance is one SPL token account with its amount in raw and UI units.
+type tokenBalance struct {
+	Address  string `json:"address"`
+	Mint     string `json:"mint"`
+	Owner    string `json:"owner"`
+	Amount   uint64 `json:"amount"`
+	Decimals uint8  `json:"decimals"`
+	UIAmount string `json:"uiAmount"`
+}
+
+// runTokenCreateMint creates and initializes a new mint with the signer as
+// mint authority (and freeze authority unless noFreeze is set).
+func runTokenCreateMint(fromPrivBase58, fromFilePath string, decimals uint8, noFreeze bool, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	payer, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	rent, err := c.GetMinimumBalanceForRentExemption(ctx, token.MintAccountSize)
+	if err != nil {
+		return fmt.Errorf("failed to get rent-exempt minimum: %w", err)
+	}
+
+	mint := types.NewAccount()
+	var freeze *common.PublicKey
+	if !noFreeze {
+		freeze = &payer.PublicKey
+	}
+	ixs := []types.Instruction{
+		sysprog.CreateAccount(sysprog.CreateAccountParam{
+			From:     payer.PublicKey,
+			New:      mint.PublicKey,
+			Owner:    common.TokenProgramID,
+			Lamports: rent,
+			Space:    token.MintAccountSize,
+		}),
+		token.InitializeMint2(token.InitializeMint2Param{
+			Decimals:   decimals,
+			Mint:       mint.PublicKey,
+			MintAuth:   payer.PublicKey,
+			FreezeAuth: freeze,
+		}),
+	}
+	// The new mint account signs its own creation.
+	txhash, err := txbuilder.New(c).FeePayer(payer).Signers(mint).Add(ixs...).SendAndConfirm(ctx)
+	if err != nil {
+		return err
+	}
+
+	out := map[string]any{
+		"cluster":       cluster,
+		"txhash":        txhash,
+		"mint":          mint.PublicKey.ToBase58(),
+		"decimals":      decimals,
+		"mintAuthority": payer.PublicKey.ToBase58(),
+		"rentLamports":  rent,
+	}
+	if freeze != nil {
+		out["freezeAuthority"] = freeze.ToBase58()
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// runTokenMintTo mints amount (in UI units) to the owner's associated token
+// account, creating the account first when it does not exist yet.
+func runTokenMintTo(fromPrivBase58, fromFilePath, mintBase58, ownerBase58, amount string, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	authority, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	mint := common.PublicKeyFromString(mintBase58)
+	owner := authority.PublicKey
+	if ownerBase58 != "" {
+		owner = common.PublicKeyFromString(ownerBase58)
+	}
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	info, err := fetchMint(ctx, c, mint)
+	if err != nil {
+		return err
+	}
+	raw, err := parseTokenAmount(amount, info.Decimals)
+	if err != nil {
+		return err
+	}
+	ata, _, err := common.FindAssociatedTokenAddress(owner, mint)
+	if err != nil {
+		return fmt.Errorf("failed to derive associated token account: %w", err)
+	}
+
+	ixs := []types.Instruction{
+		associated_token_account.CreateIdempotent(associated_token_account.CreateIdempotentParam{
+			Funder:                 authority.PublicKey,
+			Owner:                  owner,
+			Mint:                   mint,
+			AssociatedTokenAccount: ata,
+		}),
+		token.MintToChecked(token.MintToCheckedParam{
+			Mint:     mint,
+			Auth:     authority.PublicKey,
+			To:       ata,
+			Amount:   raw,
+			Decimals: info.Decimals,
+		}),
+	}
+	txhash, err := sendAndConfirm(ctx, c, authority, ixs)
+	if err != nil {
+		return err
+	}
+
+	out := map[string]any{
+		"cluster":  cluster,
+		"txhash":   txhash,
+		"mint":     mint.ToBase58(),
+		"owner":    owner.ToBase58(),
+		"account":  ata.ToBase58(),
+		"amount":   raw,
+		"uiAmount": formatTokenAmount(raw, info.Decimals),
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// runTokenBurn burns amount (in UI units) from the signer's token account for
+// mint; accountBase58 overrides the associated token account.
+func runTokenBurn(fromPrivBase58, fromFilePath, mintBase58, accountBase58, amount string, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	owner, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	mint := common.PublicKeyFromString(mintBase58)
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	info, err := fetchMint(ctx, c, mint)
+	if err != nil {
+		return err
+	}
+	raw, err := parseTokenAmount(amount, info.Decimals)
+	if err != nil {
+		return err
+	}
+	var account common.PublicKey
+	if accountBase58 != "" {
+		account = common.PublicKeyFromString(accountBase58)
+	} else {
+		if account, _, err = common.FindAssociatedTokenAddress(owner.PublicKey, mint); err != nil {
+			return fmt.Errorf("failed to derive associated token account: %w", err)
+		}
+	}
+
+	ix := token.BurnChecked(token.BurnCheckedParam{
+		Account:  account,
+		Auth:     owner.PublicKey,
+		Mint:     mint,
+		Amount:   raw,
+		Decimals: info.Decimals,
+	})
+	txhash, err := sendAndConfirm(ctx, c, owner, []types.Instruction{ix})
+	if err != nil {
+		return err
+	}
+
+	out := map[string]any{
+		"cluster":  cluster,
+		"txhash":   txhash,
+		"mint":     mint.ToBase58(),
+		"account":  account.ToBase58(),
+		"amount":   raw,
+		"uiAmount": formatTokenAmount(raw, info.Decimals),
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// runTokenAccounts lists every SPL token account owned by owner.
+func runTokenAccounts(ownerBase58, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+	balances, err := tokenBalances(ctx, c, common.PublicKeyFromString(ownerBase58))
+	if err != nil {
+		return err
+	}
+
+	out := map[string]any{
+		"cluster":  cluster,
+		"owner":    ownerBase58,
+		"accounts": balances,
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// tokenAuthorityTypes maps --type values to SPL token authority types.
+var tokenAuthorityTypes = map[string]token.AuthorityType{
+	"mint":   token.AuthorityTypeMintTokens,
+	"freeze": token.AuthorityTypeFreezeAccount,
+	"owner":  token.AuthorityTypeAccountOwner,
+	"close":  token.AuthorityTypeCloseAccount,
+}
+
+// runTokenSetAuthority changes an authority of a mint or token account. An
+// empty newAuthority revokes it permanently.
+func runTokenSetAuthority(fromPrivBase58, fromFilePath, accountBase58, authType, newAuthority string, cluster, rpcOverride string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	current, err := loadSigner(fromPrivBase58, fromFilePath)
+	if err != nil {
+		return err
+	}
+	kind, ok := tokenAuthorityTypes[authType]
+	if !ok {
+		return fmt.Errorf("unknown authority type %q (mint, freeze, owner, close)", authType)
+	}
+	var next *common.PublicKey
+	if newAuthority != "" {
+		pk := common.PublicKeyFromString(newAuthority)
+		next = &pk
+	}
+	account := common.PublicKeyFromString(accountBase58)
+	c := client.NewClient(resolveEndpoint(cluster, rpcOverride))
+
+	ix := token.SetAuthority(token.SetAuthorityParam{
+		Account:  account,
+		NewAuth:  next,
+		AuthType: kind,
+		Auth:     current.PublicKey,
+	})
+	txhash, err := sendAndConfirm(ctx, c, current, []types.Instruction{ix})
+	if err != nil {
+		return err
+	}
+
+	out := map[string]any{
+		"cluster":      cluster,
+		"txhash":       txhash,
+		"account":      account.ToBase58(),
+		"type":         authType,
+		"oldAuthority": current.PublicKey.ToBase58(),
+		"newAuthority": nil,
+	}
+	if next != nil {
+		out["newAuthority"] = next.ToBase58()
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func fetchMint(ctx context.Context, c *client.Client, mint common.PublicKey) (token.MintAccount, error) {
+	info, err := c.GetAccountInfo(ctx, mint.ToBase58())
+	if err != nil {
+		return token.MintAccount{}, fmt.Errorf("failed to get mint: %w", err)
+	}
+	if info.Owner != common.TokenProgramID {
+		return token.MintAccount{}, fmt.Errorf("%s is not an SPL token mint", mint.ToBase58())
+	}
+	m, err := token.MintAccountFromData(info.Data)
+	if err != nil {
+		return token.MintAccount{}, fmt.Errorf("failed to decode mint %s: %w", mint.ToBase58(), err)
+	}
+	return m, nil
+}
+
+// tokenBalances loads owner's token accounts and the decimals of their mints,
+// sorted by mint then address.
+func tokenBalances(ctx context.Context, c *client.Client, owner common.PublicKey) ([]tokenBalance, error) {
+	accounts, err := c.GetTokenAccountsByOwnerByProgram(ctx, owner.ToBase58(), common.TokenProgramID.ToBase58())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token accounts: %w", err)
+	}
+	var mints []string
+	index := map[common.PublicKey]int{}
+	for _, a := range accounts {
+		if _, ok := index[a.Mint]; !ok {
+			index[a.Mint] = len(mints)
+			mints = append(mints, a.Mint.ToBase58())
+		}
+	}
+	decimals := make([]uint8, len(mints))
+	// getMultipleAccounts takes at most 100 addresses per call.
+	for start := 0; start < len(mints); start += 100 {
+		end := min(start+100, len(mints))
+		infos, err := c.GetMultipleAccounts(ctx, mints[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get mints: %w", err)
+		}
+		for i, info := range infos {
+			if m, err := token.MintAccountFromData(info.Data); err == nil {
+				decimals[start+i] = m.Decimals
+			}
+		}
+	}
+
+	balances := make([]tokenBalance, 0, len(accounts))
+	for _, a := range accounts {
+		d := decimals[index[a.Mint]]
+		balances = append(balances, tokenBalance{
+			Address:  a.PublicKey.ToBase58(),
+			Mint:     a.Mint.ToBase58(),
+			Owner:    a.Owner.ToBase58(),
+			Amount:   a.Amount,
+			Decimals: d,
+			UIAmount: formatTokenAmount(a.Amount, d),
+		})
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Mint != balances[j].Mint {
+			return balances[i].Mint < balances[j].Mint
+		}
+		return balances[i].Address < balances[j].Address
+	})
+	return balances, nil
+}
+
+// parseTokenAmount converts a decimal UI amount such as "1.5" into raw base
+// units, rejecting more fractional digits than the mint supports.
+func parseTokenAmount(s string, decimals uint8) (uint64, error) {
+	s = strings.TrimSpace(s)
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, errors.New("missing --amount")
+	}
+	if len(frac) > int(decimals) {
+		return 0, fmt.Errorf("amount %s has more than %d decimal places", s, decimals)
+	}
+	digits := whole + frac + strings.Repeat("0", int(decimals)-len(frac))
+	v, ok := new(big.Int).SetString(digits, 10)
+	if !ok || v.Sign() < 0 || strings.ContainsAny(digits, "+-") {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if !v.IsUint64() {
+		return 0, fmt.Errorf("amount %s overflows u64", s)
+	}
+	if v.Sign() == 0 {
+		return 0, errors.New("amount must be greater than zero")
+	}
+	return v.Uint64(), nil
+}
+
+// formatTokenAmount renders raw base units as a UI amount without trailing zeros.
+func formatTokenAmount(raw uint64, decimals uint8) string {
+	s := fmt.Sprintf("%0*d", int(decimals)+1, raw)
+	if decimals == 0 {
+		return s
+	}
+	whole, frac := s[:len(s)-int(decimals)], strings.TrimRight(s[len(s)-int(decimals):], "0")
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/mr-tron/base58"
+
+	"sdk/merkle"
+	"sdk/programs/voting"
+)
+
+// allowlistProof is the file written by `allowlist proof` and read by `vote --proof`.
+type allowlistProof struct {
+	Voter string        `json:"voter"`
+	Root  merkle.Hash   `json:"root"`
+	Proof []merkle.Hash `json:"proof"`
+}
+
+// readAllowlist loads addresses from the first column of a CSV file. Blank
+// lines, # comments and a header row that is not an address are skipped.
+func readAllowlist(path string) ([]common.PublicKey, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var addrs []common.PublicKey
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		addr := strings.TrimSpace(strings.Split(line, ",")[0])
+		if b, err := base58.Decode(addr); err != nil || len(b) != 32 {
+			if len(addrs) == 0 {
+				continue // header
+			}
+			return nil, fmt.Errorf("%s:%d: invalid address %q", path, n, addr)
+		}
+		addrs = append(addrs, common.PublicKeyFromString(addr))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("%s: no addresses", path)
+	}
+	return addrs, nil
+}
+
+func loadAllowlistTree(path string) (*merkle.Tree, error) {
+	addrs, err := readAllowlist(path)
+	if err != nil {
+		return nil, err
+	}
+	return merkle.New(addrs)
+}
+
+// runAllowlistRoot prints the Merkle root to pass as `poll create --root`.
+func runAllowlistRoot(csvPath string) error {
+	tree, err := loadAllowlistTree(csvPath)
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]any{
+		"root":   tree.Root(),
+		"voters": tree.Len(),
+	})
+}
+
+// runAllowlistProof prints (or writes to out) the voter's proof for `vote --proof`.
+func runAllowlistProof(csvPath, voter, out string) error {
+	tree, err := loadAllowlistTree(csvPath)
+	if err != nil {
+		return err
+	}
+	if b, err := base58.Decode(voter); err != nil || len(b) != 32 {
+		return fmt.Errorf("invalid --voter %q", voter)
+	}
+	pk := common.PublicKeyFromString(voter)
+	proof, err := tree.Proof(pk)
+	if err != nil {
+		return err
+	}
+	p := allowlistProof{Voter: voter, Root: tree.Root(), Proof: proof}
+	if out == "" {
+		return printJSON(p)
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, append(data, '\n'), 0o644)
+}
+
+// parseVotingMode builds the poll mode from the `poll create` flags. The
+// allowlist root comes from --root or is computed from the --allowlist CSV.
+func parseVotingMode(mode, mint, root, allowlist string) (voting.VotingMode, error) {
+	switch mode {
+	case "", "one-wallet":
+		return voting.VotingMode{Kind: voting.VotingModeKindOneWalletOneVote}, nil
+	case "token":
+		if b, err := base58.Decode(mint); err != nil || len(b) != 32 {
+			return voting.VotingMode{}, fmt.Errorf("token mode needs a valid --mint")
+		}
+		return voting.VotingMode{
+			Kind:          voting.VotingModeKindTokenWeighted,
+			TokenWeighted: &voting.VotingModeTokenWeighted{Mint: common.PublicKeyFromString(mint)},
+		}, nil
+	case "allowlist":
+		var h merkle.Hash
+		switch {
+		case root != "":
+			parsed, err := merkle.ParseHash(root)
+			if err != nil {
+				return voting.VotingMode{}, err
+			}
+			h = parsed
+		case allowlist != "":
+			tree, err := loadAllowlistTree(allowlist)
+			if err != nil {
+				return voting.VotingMode{}, err
+			}
+			h = tree.Root()
+		default:
+			return voting.VotingMode{}, fmt.Errorf("allowlist mode needs --root or --allowlist")
+		}
+		return voting.VotingMode{
+			Kind:            voting.VotingModeKindMerkleAllowlist,
+			MerkleAllowlist: &voting.VotingModeMerkleAllowlist{Root: h},
+		}, nil
+	}
+	return voting.VotingMode{}, fmt.Errorf("unknown --mode %q (one-wallet, token, allowlist)", mode)
+}
+
+// voterProof returns the signer's allowlist proof from a proof file or, failing
+// that, by rebuilding the tree from the allowlist CSV. The proof must lead to root.
+func voterProof(voter common.PublicKey, root merkle.Hash, proofPath, allowlist string) ([][32]uint8, error) {
+	var proof []merkle.Hash
+	switch {
+	case proofPath != "":
+		data, err := os.ReadFile(proofPath)
+		if err != nil {
+			return nil, err
+		}
+		var p allowlistProof
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("invalid proof file %s: %w", proofPath, err)
+		}
+		proof = p.Proof
+	case allowlist != "":
+		tree, err := loadAllowlistTree(allowlist)
+		if err != nil {
+			return nil, err
+		}
+		if proof, err = tree.Proof(voter); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("poll uses an allowlist: pass --proof or --allowlist")
+	}
+	if !merkle.Verify(root, voter, proof) {
+		return nil, fmt.Errorf("proof does not prove %s against the poll root %s", voter.ToBase58(), root)
+	}
+	out := make([][32]uint8, len(proof))
+	for i, h := range proof {
+		out[i] = h
+	}
+	return out, nil
+}
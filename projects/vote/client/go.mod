@@ -0,0 +1,17 @@
+module vote-client
+
+go 1.23.4
+
+require (
+	github.com/blocto/solana-go-sdk v1.30.0
+	github.com/mr-tron/base58 v1.2.0
+)
+
+require (
+	filippo.io/edwards25519 v1.0.0-rc.1 // indirect
+	github.com/gorilla/websocket v1.5.3 // indirect
+)
+
+require sdk v0.0.0
+
+replace sdk => ../../sdk
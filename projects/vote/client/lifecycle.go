@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/anchor"
+	"sdk/programs/voting"
+)
+
+// runPollUpdateWindow moves the voting window; zero start/end keep the current value.
+// The program rejects the update after voting ends, or a new start once voting has begun.
+func runPollUpdateWindow(keypairPath, lookupTables string, pollID uint64, start, end int64, cluster, rpcOverride string) error {
+	signer, err := loadAccountFromFile(keypairPath)
+	if err != nil {
+		return fmt.Errorf("failed to load keypair: %w", err)
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	c := newClient(cluster, rpcOverride)
+	pollPDA, poll, err := fetchPoll(ctx, c, pollID)
+	if err != nil {
+		return err
+	}
+	if poll.Authority != signer.PublicKey {
+		return fmt.Errorf("signer %s is not the poll authority %s", signer.PublicKey.ToBase58(), poll.Authority.ToBase58())
+	}
+	if start == 0 {
+		start = int64(poll.PollVoteStart)
+	}
+	if end == 0 {
+		end = int64(poll.PollVoteEnd)
+	}
+	if end <= start {
+		return fmt.Errorf("--end must be after --start")
+	}
+
+	sig, err := sendInstructions(ctx, c, signer, lookupTables, voting.NewUpdatePollWindowInstruction(
+		voting.UpdatePollWindowAccounts{
+			Authority:   signer.PublicKey,
+			PollAccount: pollPDA,
+		},
+		voting.UpdatePollWindowArgs{
+			PollID: pollID,
+			Start:  uint64(start),
+			End:    uint64(end),
+		},
+	))
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]any{
+		"cluster": cluster,
+		"txhash":  sig,
+		"pollId":  pollID,
+		"poll":    pollPDA.ToBase58(),
+		"start":   start,
+		"end":     end,
+	})
+}
+
+// runPollFinalize writes the winner into the poll once voting has ended.
+// The program requires every candidate account of the poll.
+func runPollFinalize(keypairPath, lookupTables string, pollID uint64, names []string, cluster, rpcOverride string) error {
+	signer, err := loadAccountFromFile(keypairPath)
+	if err != nil {
+		return fmt.Errorf("failed to load keypair: %w", err)
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	c := newClient(cluster, rpcOverride)
+	pollPDA, poll, err := fetchPoll(ctx, c, pollID)
+	if err != nil {
+		return err
+	}
+	candidates, err := pollCandidates(ctx, c, pollID, names)
+	if err != nil {
+		return err
+	}
+	if uint64(len(candidates)) != poll.PollVoteIndex {
+		return fmt.Errorf("found %d candidate accounts but poll %d has %d candidates", len(candidates), pollID, poll.PollVoteIndex)
+	}
+
+	ix := voting.NewFinalizeResultsInstruction(
+		voting.FinalizeResultsAccounts{
+			Signer:      signer.PublicKey,
+			PollAccount: pollPDA,
+		},
+		voting.FinalizeResultsArgs{PollID: pollID},
+	)
+	ix.Accounts = append(ix.Accounts, candidateMetas(candidates)...)
+	sig, err := sendInstructions(ctx, c, signer, lookupTables, ix)
+	if err != nil {
+		return err
+	}
+	_, poll, err = fetchPoll(ctx, c, pollID)
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]any{
+		"cluster":     cluster,
+		"txhash":      sig,
+		"pollId":      pollID,
+		"poll":        pollPDA.ToBase58(),
+		"winner":      poll.Winner,
+		"winnerVotes": poll.WinnerVotes,
+	})
+}
+
+// receiptsPerTx is how many [receipt, voter] pairs one close_receipts carries.
+const receiptsPerTx = 10
+
+// runPollClose closes the poll's vote receipts (rent back to each voter), then
+// the poll and its candidate accounts, returning their rent to the authority.
+// The program refuses while voting is open, and requires every candidate account
+// of the poll.
+func runPollClose(keypairPath, lookupTables string, pollID uint64, names []string, cluster, rpcOverride string) error {
+	signer, err := loadAccountFromFile(keypairPath)
+	if err != nil {
+		return fmt.Errorf("failed to load keypair: %w", err)
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	c := newClient(cluster, rpcOverride)
+	pollPDA, poll, err := fetchPoll(ctx, c, pollID)
+	if err != nil {
+		return err
+	}
+	if poll.Authority != signer.PublicKey {
+		return fmt.Errorf("signer %s is not the poll authority %s", signer.PublicKey.ToBase58(), poll.Authority.ToBase58())
+	}
+	candidates, err := pollCandidates(ctx, c, pollID, names)
+	if err != nil {
+		return err
+	}
+	if uint64(len(candidates)) != poll.PollVoteIndex {
+		return fmt.Errorf("found %d candidate accounts but poll %d has %d candidates", len(candidates), pollID, poll.PollVoteIndex)
+	}
+
+	// close_poll refuses while receipts remain, so they go first, in batches.
+	receipts, err := scanReceipts(ctx, c, pollID)
+	if err != nil {
+		return err
+	}
+	var receiptTxs []string
+	for start := 0; start < len(receipts); start += receiptsPerTx {
+		ix := voting.NewCloseReceiptsInstruction(
+			voting.CloseReceiptsAccounts{
+				Authority:   signer.PublicKey,
+				PollAccount: pollPDA,
+			},
+			voting.CloseReceiptsArgs{PollID: pollID},
+		)
+		for _, r := range receipts[start:min(start+receiptsPerTx, len(receipts))] {
+			ix.Accounts = append(ix.Accounts,
+				types.AccountMeta{PubKey: r.address, IsWritable: true},
+				types.AccountMeta{PubKey: r.voter, IsWritable: true},
+			)
+		}
+		sig, err := sendInstructions(ctx, c, signer, lookupTables, ix)
+		if err != nil {
+			return fmt.Errorf("close receipts: %w", err)
+		}
+		receiptTxs = append(receiptTxs, sig)
+	}
+
+	addrs := []string{pollPDA.ToBase58()}
+	for _, r := range candidates {
+		addrs = append(addrs, r.Address)
+	}
+	infos, err := c.GetMultipleAccounts(ctx, addrs)
+	if err != nil {
+		return fmt.Errorf("failed to fetch accounts: %w", err)
+	}
+	var reclaimed uint64
+	for _, info := range infos {
+		reclaimed += info.Lamports
+	}
+
+	ix := voting.NewClosePollInstruction(
+		voting.ClosePollAccounts{
+			Authority:   signer.PublicKey,
+			PollAccount: pollPDA,
+		},
+		voting.ClosePollArgs{PollID: pollID},
+	)
+	ix.Accounts = append(ix.Accounts, candidateMetas(candidates)...)
+	sig, err := sendInstructions(ctx, c, signer, lookupTables, ix)
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]any{
+		"cluster":    cluster,
+		"txhash":     sig,
+		"pollId":     pollID,
+		"poll":       pollPDA.ToBase58(),
+		"candidates": len(candidates),
+		"receipts":   len(receipts),
+		"receiptTxs": receiptTxs,
+		"reclaimed":  reclaimed,
+	})
+}
+
+// receiptAccount is a vote receipt of a poll and the voter its rent returns to.
+type receiptAccount struct {
+	address common.PublicKey
+	voter   common.PublicKey
+}
+
+// scanReceipts finds the poll's vote receipts. Receipts do not store the poll
+// id, so every receipt is matched against the [poll_id_le, voter] PDA.
+func scanReceipts(ctx context.Context, c *client.Client, pollID uint64) ([]receiptAccount, error) {
+	accounts, err := anchor.FetchProgramAccounts(ctx, c, voting.ProgramID, anchor.DiscriminatorFilter(voting.VoteReceiptDiscriminator))
+	if err != nil {
+		return nil, err
+	}
+	var out []receiptAccount
+	for _, acc := range accounts {
+		receipt, err := voting.DecodeVoteReceipt(acc.Data)
+		if err != nil {
+			continue
+		}
+		pda, _, err := voting.FindReceiptAddress(pollID, receipt.Voter)
+		if err != nil || pda != acc.Pubkey {
+			continue
+		}
+		out = append(out, receiptAccount{address: acc.Pubkey, voter: receipt.Voter})
+	}
+	return out, nil
+}
+
+// pollCandidates loads the named candidates, or every candidate of the poll.
+func pollCandidates(ctx context.Context, c *client.Client, pollID uint64, names []string) ([]candidateResult, error) {
+	if len(names) > 0 {
+		return fetchNamedCandidates(ctx, c, pollID, names)
+	}
+	return scanCandidates(ctx, c, pollID)
+}
+
+// candidateMetas passes candidate accounts as writable remaining accounts.
+func candidateMetas(candidates []candidateResult) []types.AccountMeta {
+	metas := make([]types.AccountMeta, len(candidates))
+	for i, r := range candidates {
+		metas[i] = types.AccountMeta{PubKey: common.PublicKeyFromString(r.Address), IsWritable: true}
+	}
+	return metas
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+
+	"sdk/anchor"
+	"sdk/txbuilder"
+)
+
+const defaultKeypair = "$HOME/.config/solana/id.json"
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "poll":
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(1)
+		}
+		switch os.Args[2] {
+		case "create":
+			fs := flag.NewFlagSet("poll create", flag.ExitOnError)
+			pollID := fs.Uint64("poll-id", 0, "Poll id (u64, part of the poll PDA seeds)")
+			name := fs.String("name", "", "Poll name (max 10 bytes)")
+			desc := fs.String("desc", "", "Poll description (max 100 bytes)")
+			start := fs.Int64("start", 0, "Voting start, unix seconds (default: now)")
+			end := fs.Int64("end", 0, "Voting end, unix seconds (default: start + 24h)")
+			mode := fs.String("mode", "one-wallet", "Voting mode: one-wallet|token|allowlist")
+			mint := fs.String("mint", "", "Token mint whose balance weighs votes (--mode token)")
+			root := fs.String("root", "", "Allowlist Merkle root, hex (--mode allowlist)")
+			allowlist := fs.String("allowlist", "", "CSV of allowed voter addresses to compute the root from (--mode allowlist)")
+			keypair, lookupTable, cluster, rpcURL := commonFlags(fs)
+			_ = fs.Parse(os.Args[3:])
+			if *name == "" {
+				log.Fatal("missing --name")
+			}
+			votingMode, err := parseVotingMode(strings.TrimSpace(*mode), strings.TrimSpace(*mint), strings.TrimSpace(*root), strings.TrimSpace(*allowlist))
+			if err != nil {
+				log.Fatalf("poll create error: %v", err)
+			}
+			if err := runPollCreate(*keypair, *lookupTable, *pollID, *name, *desc, *start, *end, votingMode, normalizeCluster(*cluster), strings.TrimSpace(*rpcURL)); err != nil {
+				fatalTxError("poll create error", err)
+			}
+		case "show":
+			fs := flag.NewFlagSet("poll show", flag.ExitOnError)
+			pollID := fs.Uint64("poll-id", 0, "Poll id")
+			cluster, rpcURL := clusterFlags(fs)
+			_ = fs.Parse(os.Args[3:])
+			if err := runPollShow(*pollID, normalizeCluster(*cluster), strings.TrimSpace(*rpcURL)); err != nil {
+				log.Fatalf("poll show error: %v", err)
+			}
+		case "update-window":
+			fs := flag.NewFlagSet("poll update-window", flag.ExitOnError)
+			pollID := fs.Uint64("poll-id", 0, "Poll id")
+			start := fs.Int64("start", 0, "New voting start, unix seconds (default: unchanged)")
+			end := fs.Int64("end", 0, "New voting end, unix seconds (default: unchanged)")
+			keypair, lookupTable, cluster, rpcURL := commonFlags(fs)
+			_ = fs.Parse(os.Args[3:])
+			if *start == 0 && *end == 0 {
+				log.Fatal("missing --start or --end")
+			}
+			if err := runPollUpdateWindow(*keypair, *lookupTable, *pollID, *start, *end, normalizeCluster(*cluster), strings.TrimSpace(*rpcURL)); err != nil {
+				fatalTxError("poll update-window error", err)
+			}
+		case "finalize", "close":
+			fs := flag.NewFlagSet("poll "+os.Args[2], flag.ExitOnError)
+			pollID := fs.Uint64("poll-id", 0, "Poll id")
+			candidates := fs.String("candidates", "", "Comma separated candidate names (default: scan all candidate accounts)")
+			keypair, lookupTable, cluster, rpcURL := commonFlags(fs)
+			_ = fs.Parse(os.Args[3:])
+			var err error
+			if os.Args[2] == "finalize" {
+				err = runPollFinalize(*keypair, *lookupTable, *pollID, splitList(*candidates), normalizeCluster(*cluster), strings.TrimSpace(*rpcURL))
+			} else {
+				err = runPollClose(*keypair, *lookupTable, *pollID, splitList(*candidates), normalizeCluster(*cluster), strings.TrimSpace(*rpcURL))
+			}
+			if err != nil {
+				fatalTxError("poll "+os.Args[2]+" error", err)
+			}
+		default:
+			printUsage()
+			os.Exit(1)
+		}
+	case "candidate":
+		if len(os.Args) < 3 || os.Args[2] != "add" {
+			printUsage()
+			os.Exit(1)
+		}
+		fs := flag.NewFlagSet("candidate add", flag.ExitOnError)
+		pollID := fs.Uint64("poll-id", 0, "Poll id")
+		candidate := fs.String("candidate", "", "Candidate name (max 10 bytes)")
+		keypair, lookupTable, cluster, rpcURL := commonFlags(fs)
+		_ = fs.Parse(os.Args[3:])
+		if *candidate == "" {
+			log.Fatal("missing --candidate")
+		}
+		if err := runCandidateAdd(*keypair, *lookupTable, *pollID, *candidate, normalizeCluster(*cluster), strings.TrimSpace(*rpcURL)); err != nil {
+			fatalTxError("candidate add error", err)
+		}
+	case "vote":
+		fs := flag.NewFlagSet("vote", flag.ExitOnError)
+		pollID := fs.Uint64("poll-id", 0, "Poll id")
+		candidate := fs.String("candidate", "", "Candidate name")
+		tokenAccount := fs.String("token-account", "", "Token account holding the poll mint (default: associated token account)")
+		proof := fs.String("proof", "", "Allowlist proof file written by `allowlist proof`")
+		allowlist := fs.String("allowlist", "", "Allowlist CSV to build the proof from instead of --proof")
+		keypair, lookupTable, cluster, rpcURL := commonFlags(fs)
+		_ = fs.Parse(os.Args[2:])
+		if *candidate == "" {
+			log.Fatal("missing --candidate")
+		}
+		if err := runVote(*keypair, *lookupTable, *pollID, *candidate, strings.TrimSpace(*tokenAccount), strings.TrimSpace(*proof), strings.TrimSpace(*allowlist), normalizeCluster(*cluster), strings.TrimSpace(*rpcURL)); err != nil {
+			fatalTxError("vote error", err)
+		}
+	case "allowlist":
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(1)
+		}
+		fs := flag.NewFlagSet("allowlist "+os.Args[2], flag.ExitOnError)
+		csvPath := fs.String("csv", "", "CSV file with one voter address per line (first column)")
+		voter := fs.String("voter", "", "Voter address to prove (allowlist proof)")
+		out := fs.String("out", "", "Write the proof to this file instead of stdout")
+		_ = fs.Parse(os.Args[3:])
+		if *csvPath == "" {
+			log.Fatal("missing --csv")
+		}
+		var err error
+		switch os.Args[2] {
+		case "root":
+			err = runAllowlistRoot(*csvPath)
+		case "proof":
+			if *voter == "" {
+				log.Fatal("missing --voter")
+			}
+			err = runAllowlistProof(*csvPath, strings.TrimSpace(*voter), *out)
+		default:
+			printUsage()
+			os.Exit(1)
+		}
+		if err != nil {
+			log.Fatalf("allowlist %s error: %v", os.Args[2], err)
+		}
+	case "results":
+		fs := flag.NewFlagSet("results", flag.ExitOnError)
+		pollID := fs.Uint64("poll-id", 0, "Poll id")
+		candidates := fs.String("candidates", "", "Comma separated candidate names (default: scan all candidate accounts)")
+		asJSON := fs.Bool("json", false, "Print results as JSON instead of a table")
+		cluster, rpcURL := clusterFlags(fs)
+		_ = fs.Parse(os.Args[2:])
+		if err := runResults(*pollID, splitList(*candidates), *asJSON, normalizeCluster(*cluster), strings.TrimSpace(*rpcURL)); err != nil {
+			log.Fatalf("results error: %v", err)
+		}
+	default:
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+func commonFlags(fs *flag.FlagSet) (keypair, lookupTable, cluster, rpcURL *string) {
+	keypair = fs.String("keypair", defaultKeypair, "Path to Solana keypair JSON file (id.json)")
+	lookupTable = fs.String("lookup-table", "", "Comma separated address lookup tables (sends a v0 transaction)")
+	cluster, rpcURL = clusterFlags(fs)
+	return keypair, lookupTable, cluster, rpcURL
+}
+
+func clusterFlags(fs *flag.FlagSet) (cluster, rpcURL *string) {
+	cluster = fs.String("cluster", "local", "Cluster: devnet|testnet|mainnet|local")
+	rpcURL = fs.String("rpc", "", "Custom RPC endpoint URL (override)")
+	return cluster, rpcURL
+}
+
+// sendInstructions signs ixs with signer (also the fee payer), submits them and
+// waits for confirmation, decoding program errors such as VotingEnded on failure. Passing lookup table
+// addresses compiles a v0 message that references accounts by table index.
+func sendInstructions(ctx context.Context, c *client.Client, signer types.Account, lookupTables string, ixs ...types.Instruction) (string, error) {
+	var keys []common.PublicKey
+	for _, s := range splitList(lookupTables) {
+		keys = append(keys, common.PublicKeyFromString(s))
+	}
+	tables, err := txbuilder.FetchLookupTableAccounts(ctx, c, keys...)
+	if err != nil {
+		return "", err
+	}
+	return txbuilder.New(c).FeePayer(signer).Add(ixs...).LookupTables(tables...).SendAndConfirm(ctx)
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// fatalTxError exits, printing decoded instruction errors as structured JSON on stderr
+func fatalTxError(prefix string, err error) {
+	var pe *anchor.ProgramError
+	if errors.As(err, &pe) {
+		enc := json.NewEncoder(os.Stderr)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(map[string]any{"error": prefix, "programError": pe})
+		os.Exit(1)
+	}
+	log.Fatalf("%s: %v", prefix, err)
+}
+
+func newClient(cluster, rpcOverride string) *client.Client {
+	return client.NewClient(resolveEndpoint(cluster, rpcOverride))
+}
+
+func newContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 30*time.Second)
+}
+
+func endpointFor(cluster string) string {
+	switch cluster {
+	case "mainnet", "mainnet-beta":
+		return rpc.MainnetRPCEndpoint
+	case "testnet":
+		return rpc.TestnetRPCEndpoint
+	case "devnet":
+		return rpc.DevnetRPCEndpoint
+	default:
+		return "http://127.0.0.1:8899"
+	}
+}
+
+func resolveEndpoint(cluster, rpcOverride string) string {
+	if strings.TrimSpace(rpcOverride) != "" {
+		return strings.TrimSpace(rpcOverride)
+	}
+	return endpointFor(cluster)
+}
+
+func normalizeCluster(c string) string {
+	c = strings.ToLower(strings.TrimSpace(c))
+	switch c {
+	case "mainnet", "mainnet-beta":
+		return "mainnet"
+	case "testnet":
+		return "testnet"
+	case "devnet":
+		return "devnet"
+	default:
+		return "local"
+	}
+}
+
+func loadAccountFromFile(path string) (types.Account, error) {
+	data, err := os.ReadFile(os.ExpandEnv(strings.TrimSpace(path)))
+	if err != nil {
+		return types.Account{}, err
+	}
+	var ints []int
+	if err := json.Unmarshal(data, &ints); err != nil {
+		return types.Account{}, err
+	}
+	b := make([]byte, len(ints))
+	for i, v := range ints {
+		b[i] = byte(v)
+	}
+	return types.AccountFromBytes(b)
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func printUsage() {
+	fmt.Println(`Usage:
+  Create poll:
+    go run . poll create --poll-id <u64> --name <name> [--desc <text>] [--start <unix>] [--end <unix>] [--mode one-wallet|token|allowlist] [--mint <base58>] [--root <hex> | --allowlist voters.csv] [--keypair ~/.config/solana/id.json] [--lookup-table <table,...>] [--cluster local|devnet|testnet|mainnet] [--rpc <url>]
+
+  Show poll:
+    go run . poll show --poll-id <u64> [--cluster ...] [--rpc <url>]
+
+  Move the voting window (authority only; the start is fixed once voting has started):
+    go run . poll update-window --poll-id <u64> [--start <unix>] [--end <unix>] [--keypair ...] [--cluster ...] [--rpc <url>]
+
+  Write the winner into the poll after voting ends (passes every candidate account):
+    go run . poll finalize --poll-id <u64> [--candidates a,b,c] [--keypair ...] [--cluster ...] [--rpc <url>]
+
+  Close the vote receipts (rent back to each voter), then the poll and all of its candidates (rent back to the authority; not while voting is open):
+    go run . poll close --poll-id <u64> [--candidates a,b,c] [--keypair ...] [--cluster ...] [--rpc <url>]
+
+  Add candidate (poll authority only, before voting starts):
+    go run . candidate add --poll-id <u64> --candidate <name> [--keypair ...] [--lookup-table <table,...>] [--cluster ...] [--rpc <url>]
+
+  Vote:
+    go run . vote --poll-id <u64> --candidate <name> [--token-account <base58>] [--proof proof.json | --allowlist voters.csv] [--keypair ...] [--lookup-table <table,...>] [--cluster ...] [--rpc <url>]
+
+  Allowlist Merkle root for poll create, and a voter's proof for vote:
+    go run . allowlist root --csv voters.csv
+    go run . allowlist proof --csv voters.csv --voter <base58> [--out proof.json]
+
+  Results (ranked):
+    go run . results --poll-id <u64> [--candidates a,b,c] [--json] [--cluster ...] [--rpc <url>]`)
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+
+	"sdk/anchor"
+	"sdk/programs/voting"
+)
+
+// Field limits from the #[max_len] annotations in the voting program.
+const (
+	maxPollNameLen  = 10
+	maxPollDescLen  = 100
+	maxCandidateLen = 10
+)
+
+func runPollCreate(keypairPath, lookupTables string, pollID uint64, name, desc string, start, end int64, mode voting.VotingMode, cluster, rpcOverride string) error {
+	if len(name) > maxPollNameLen {
+		return fmt.Errorf("poll name exceeds %d bytes", maxPollNameLen)
+	}
+	if len(desc) > maxPollDescLen {
+		return fmt.Errorf("poll description exceeds %d bytes", maxPollDescLen)
+	}
+	if start == 0 {
+		start = time.Now().Unix()
+	}
+	if end == 0 {
+		end = start + int64(24*time.Hour/time.Second)
+	}
+	if end <= start {
+		return fmt.Errorf("--end must be after --start")
+	}
+	signer, err := loadAccountFromFile(keypairPath)
+	if err != nil {
+		return fmt.Errorf("failed to load keypair: %w", err)
+	}
+	pollPDA, _, err := voting.FindPollAccountAddress(pollID)
+	if err != nil {
+		return fmt.Errorf("failed to derive poll PDA: %w", err)
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	c := newClient(cluster, rpcOverride)
+	sig, err := sendInstructions(ctx, c, signer, lookupTables, voting.NewInitializePollInstruction(
+		voting.InitializePollAccounts{
+			Signer:      signer.PublicKey,
+			PollAccount: pollPDA,
+		},
+		voting.InitializePollArgs{
+			PollID: pollID,
+			Start:  uint64(start),
+			End:    uint64(end),
+			Name:   name,
+			Desc:   desc,
+			Mode:   mode,
+		},
+	))
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]any{
+		"cluster": cluster,
+		"txhash":  sig,
+		"pollId":  pollID,
+		"poll":    pollPDA.ToBase58(),
+		"start":   start,
+		"end":     end,
+		"mode":    mode,
+	})
+}
+
+func runCandidateAdd(keypairPath, lookupTables string, pollID uint64, candidate, cluster, rpcOverride string) error {
+	if len(candidate) > maxCandidateLen {
+		return fmt.Errorf("candidate name exceeds %d bytes", maxCandidateLen)
+	}
+	signer, err := loadAccountFromFile(keypairPath)
+	if err != nil {
+		return fmt.Errorf("failed to load keypair: %w", err)
+	}
+	pollPDA, _, err := voting.FindPollAccountAddress(pollID)
+	if err != nil {
+		return fmt.Errorf("failed to derive poll PDA: %w", err)
+	}
+	candidatePDA, _, err := voting.FindCandidateAccountAddress(pollID, candidate)
+	if err != nil {
+		return fmt.Errorf("failed to derive candidate PDA: %w", err)
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	c := newClient(cluster, rpcOverride)
+	sig, err := sendInstructions(ctx, c, signer, lookupTables, voting.NewInitializeCandidateInstruction(
+		voting.InitializeCandidateAccounts{
+			Authority:        signer.PublicKey,
+			PollAccount:      pollPDA,
+			CandidateAccount: candidatePDA,
+		},
+		voting.InitializeCandidateArgs{
+			PollID:    pollID,
+			Candidate: candidate,
+		},
+	))
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]any{
+		"cluster":   cluster,
+		"txhash":    sig,
+		"pollId":    pollID,
+		"candidate": candidate,
+		"account":   candidatePDA.ToBase58(),
+	})
+}
+
+// runVote casts the signer's vote. Token-weighted polls use tokenAccount (default:
+// the signer's associated token account for the poll mint); allowlist polls need
+// a proof file or the allowlist CSV to build the proof from.
+func runVote(keypairPath, lookupTables string, pollID uint64, candidate, tokenAccount, proofPath, allowlist, cluster, rpcOverride string) error {
+	signer, err := loadAccountFromFile(keypairPath)
+	if err != nil {
+		return fmt.Errorf("failed to load keypair: %w", err)
+	}
+	pollPDA, _, err := voting.FindPollAccountAddress(pollID)
+	if err != nil {
+		return fmt.Errorf("failed to derive poll PDA: %w", err)
+	}
+	candidatePDA, _, err := voting.FindCandidateAccountAddress(pollID, candidate)
+	if err != nil {
+		return fmt.Errorf("failed to derive candidate PDA: %w", err)
+	}
+	receiptPDA, _, err := voting.FindReceiptAddress(pollID, signer.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to derive receipt PDA: %w", err)
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	c := newClient(cluster, rpcOverride)
+	// The program rejects a second vote with AlreadyVoted; check first to save the fee.
+	receipt, err := fetchReceipt(ctx, c, receiptPDA)
+	if err != nil {
+		return err
+	}
+	if receipt != nil {
+		return fmt.Errorf("%s already voted for %q in poll %d at %s (receipt %s)",
+			signer.PublicKey.ToBase58(), receipt.Candidate, pollID, time.Unix(receipt.VotedAt, 0).UTC().Format(time.RFC3339), receiptPDA.ToBase58())
+	}
+	_, poll, err := fetchPoll(ctx, c, pollID)
+	if err != nil {
+		return err
+	}
+
+	accounts := voting.VoteAccounts{
+		Signer:           signer.PublicKey,
+		PollAccount:      pollPDA,
+		CandidateAccount: candidatePDA,
+		Receipt:          receiptPDA,
+	}
+	args := voting.VoteArgs{
+		PollID:    pollID,
+		Candidate: candidate,
+	}
+	switch poll.Mode.Kind {
+	case voting.VotingModeKindTokenWeighted:
+		if tokenAccount != "" {
+			accounts.VoterTokenAccount = common.PublicKeyFromString(tokenAccount)
+		} else if accounts.VoterTokenAccount, _, err = common.FindAssociatedTokenAddress(signer.PublicKey, poll.Mode.TokenWeighted.Mint); err != nil {
+			return fmt.Errorf("failed to derive token account: %w", err)
+		}
+	case voting.VotingModeKindMerkleAllowlist:
+		if args.Proof, err = voterProof(signer.PublicKey, poll.Mode.MerkleAllowlist.Root, proofPath, allowlist); err != nil {
+			return err
+		}
+	}
+	sig, err := sendInstructions(ctx, c, signer, lookupTables, voting.NewVoteInstruction(accounts, args))
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]any{
+		"cluster":   cluster,
+		"txhash":    sig,
+		"pollId":    pollID,
+		"candidate": candidate,
+		"voter":     signer.PublicKey.ToBase58(),
+		"mode":      poll.Mode.Kind.String(),
+		"receipt":   receiptPDA.ToBase58(),
+	})
+}
+
+// fetchReceipt returns the voter's receipt, or nil if they have not voted yet.
+func fetchReceipt(ctx context.Context, c *client.Client, receiptPDA common.PublicKey) (*voting.VoteReceipt, error) {
+	info, err := c.GetAccountInfo(ctx, receiptPDA.ToBase58())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vote receipt: %w", err)
+	}
+	if len(info.Data) == 0 {
+		return nil, nil
+	}
+	return voting.DecodeVoteReceipt(info.Data)
+}
+
+func runPollShow(pollID uint64, cluster, rpcOverride string) error {
+	ctx, cancel := newContext()
+	defer cancel()
+	c := newClient(cluster, rpcOverride)
+	pollPDA, poll, err := fetchPoll(ctx, c, pollID)
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]any{
+		"pollId":      pollID,
+		"address":     pollPDA.ToBase58(),
+		"name":        poll.PollName,
+		"desc":        poll.PollDesc,
+		"start":       poll.PollVoteStart,
+		"end":         poll.PollVoteEnd,
+		"candidates":  poll.PollVoteIndex,
+		"status":      pollStatus(poll, time.Now()),
+		"authority":   poll.Authority.ToBase58(),
+		"mode":        poll.Mode,
+		"finalized":   poll.Finalized,
+		"winner":      poll.Winner,
+		"winnerVotes": poll.WinnerVotes,
+	})
+}
+
+type candidateResult struct {
+	Rank      int     `json:"rank"`
+	Candidate string  `json:"candidate"`
+	Votes     uint64  `json:"votes"`
+	Share     float64 `json:"share"`
+	Address   string  `json:"address"`
+}
+
+func runResults(pollID uint64, names []string, asJSON bool, cluster, rpcOverride string) error {
+	ctx, cancel := newContext()
+	defer cancel()
+	c := newClient(cluster, rpcOverride)
+	_, poll, err := fetchPoll(ctx, c, pollID)
+	if err != nil {
+		return err
+	}
+
+	var results []candidateResult
+	if len(names) > 0 {
+		results, err = fetchNamedCandidates(ctx, c, pollID, names)
+	} else {
+		results, err = scanCandidates(ctx, c, pollID)
+	}
+	if err != nil {
+		return err
+	}
+	rankResults(results)
+
+	if asJSON {
+		return printJSON(map[string]any{
+			"pollId":  pollID,
+			"name":    poll.PollName,
+			"status":  pollStatus(poll, time.Now()),
+			"results": results,
+		})
+	}
+	fmt.Printf("Poll %d: %s (%s)\n", pollID, poll.PollName, pollStatus(poll, time.Now()))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tCANDIDATE\tVOTES\tSHARE\tACCOUNT")
+	for _, r := range results {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%.1f%%\t%s\n", r.Rank, r.Candidate, r.Votes, r.Share*100, r.Address)
+	}
+	return w.Flush()
+}
+
+func fetchPoll(ctx context.Context, c *client.Client, pollID uint64) (common.PublicKey, *voting.Poll, error) {
+	pollPDA, _, err := voting.FindPollAccountAddress(pollID)
+	if err != nil {
+		return common.PublicKey{}, nil, fmt.Errorf("failed to derive poll PDA: %w", err)
+	}
+	info, err := c.GetAccountInfo(ctx, pollPDA.ToBase58())
+	if err != nil {
+		return pollPDA, nil, fmt.Errorf("failed to fetch poll account: %w", err)
+	}
+	if len(info.Data) == 0 {
+		return pollPDA, nil, fmt.Errorf("poll %d not found at %s", pollID, pollPDA.ToBase58())
+	}
+	poll, err := voting.DecodePoll(info.Data)
+	if err != nil {
+		return pollPDA, nil, err
+	}
+	return pollPDA, poll, nil
+}
+
+// fetchNamedCandidates loads the candidate PDAs for the given names.
+func fetchNamedCandidates(ctx context.Context, c *client.Client, pollID uint64, names []string) ([]candidateResult, error) {
+	addrs := make([]string, len(names))
+	for i, name := range names {
+		pda, _, err := voting.FindCandidateAccountAddress(pollID, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive candidate PDA for %q: %w", name, err)
+		}
+		addrs[i] = pda.ToBase58()
+	}
+	infos, err := c.GetMultipleAccounts(ctx, addrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch candidate accounts: %w", err)
+	}
+	results := make([]candidateResult, 0, len(infos))
+	for i, info := range infos {
+		if len(info.Data) == 0 {
+			return nil, fmt.Errorf("candidate %q not found in poll %d", names[i], pollID)
+		}
+		cand, err := voting.DecodeCandidateAccount(info.Data)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, candidateResult{Candidate: cand.CandidateName, Votes: cand.CandidateVotes, Address: addrs[i]})
+	}
+	return results, nil
+}
+
+// scanCandidates lists every CandidateAccount of the program and keeps the ones
+// whose [poll_id_le, candidate] PDA matches the account address. Candidate accounts
+// do not store their poll id, so re-deriving the PDA is the only way to attribute them.
+func scanCandidates(ctx context.Context, c *client.Client, pollID uint64) ([]candidateResult, error) {
+	accounts, err := anchor.FetchProgramAccounts(ctx, c, voting.ProgramID, anchor.DiscriminatorFilter(voting.CandidateAccountDiscriminator))
+	if err != nil {
+		return nil, err
+	}
+	var results []candidateResult
+	for _, acc := range accounts {
+		cand, err := voting.DecodeCandidateAccount(acc.Data)
+		if err != nil {
+			continue
+		}
+		pda, _, err := voting.FindCandidateAccountAddress(pollID, cand.CandidateName)
+		if err != nil || pda != acc.Pubkey {
+			continue
+		}
+		results = append(results, candidateResult{Candidate: cand.CandidateName, Votes: cand.CandidateVotes, Address: acc.Pubkey.ToBase58()})
+	}
+	return results, nil
+}
+
+// rankResults sorts by votes (desc) then name, assigning competition ranks (1, 1, 3).
+func rankResults(results []candidateResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Votes != results[j].Votes {
+			return results[i].Votes > results[j].Votes
+		}
+		return results[i].Candidate < results[j].Candidate
+	})
+	var total uint64
+	for _, r := range results {
+		total += r.Votes
+	}
+	for i := range results {
+		if i > 0 && results[i].Votes == results[i-1].Votes {
+			results[i].Rank = results[i-1].Rank
+		} else {
+			results[i].Rank = i + 1
+		}
+		if total > 0 {
+			results[i].Share = float64(results[i].Votes) / float64(total)
+		}
+	}
+}
+
+func pollStatus(poll *voting.Poll, now time.Time) string {
+	ts := now.Unix()
+	switch {
+	case poll.Finalized:
+		return "finalized"
+	case ts < int64(poll.PollVoteStart):
+		return "pending"
+	case ts > int64(poll.PollVoteEnd):
+		return "ended"
+	default:
+		return "active"
+	}
+}
@@ -1,7 +1,8 @@
 #![allow(unexpected_cfgs)]
 #![allow(deprecated)]
 
-use anchor_lang::{prelude::*, solana_program::message};
+use anchor_lang::{prelude::*, solana_program::hash::hashv, solana_program::message};
+use anchor_spl::token::TokenAccount;
 
 declare_id!("31Tq6cGFa1CU8JaU51snTvKaXaKqWP3M3dFBWNXeJqYj");
 
@@ -15,27 +16,78 @@ pub mod voting {
         start: u64,
         end: u64,
         name: String,
-        desc: String) -> Result<()> {
+        desc: String,
+        mode: VotingMode) -> Result<()> {
         let poll = &mut ctx.accounts.poll_account;
+        if start >= end {
+            return Err(VotingErrorCode::InvalidPollWindow.into());
+        }
         poll.poll_name = name;
         poll.poll_desc = desc;
         poll.poll_vote_start = start;
         poll.poll_vote_end = end;
         poll.poll_vote_index = 0;
+        poll.authority = ctx.accounts.signer.key();
+        poll.finalized = false;
+        poll.winner = String::new();
+        poll.winner_votes = 0;
+        poll.mode = mode;
         Ok(())
     }
 
+    /// Adds a candidate. Only the poll authority can call it, and only before
+    /// voting starts.
     pub fn initialize_candidate(ctx: Context<InitializeCandidate>,_poll_id: u64,candidate:String) -> Result<()>{
-        
-        ctx.accounts.candidate_account.candidate_name = candidate;
+        if ctx.accounts.poll_account.finalized {
+            return Err(VotingErrorCode::PollFinalized.into());
+        }
+        if Clock::get()?.unix_timestamp >= (ctx.accounts.poll_account.poll_vote_start as i64) {
+            return Err(VotingErrorCode::VotingStarted.into());
+        }
 
-        ctx.accounts.poll_account.poll_vote_index += 1;
+        // Only count a candidate once; init_if_needed also accepts an existing account.
+        if ctx.accounts.candidate_account.candidate_name.is_empty() {
+            ctx.accounts.poll_account.poll_vote_index += 1;
+        }
+        ctx.accounts.candidate_account.candidate_name = candidate;
+        // No votes can have been cast yet, so any count here is stale.
+        ctx.accounts.candidate_account.candidate_votes = 0;
         
         Ok(())
     }
 
 
-    pub fn vote(ctx: Context<Vote>,_poll_id: u64,_candidate:String) -> Result<()>{
+    /// Casts the signer's vote. The poll's `mode` decides its weight: one per
+    /// wallet, the signer's balance of the poll mint (`voter_token_account`),
+    /// or one per wallet proven to be in the allowlist by `proof`.
+    pub fn vote(ctx: Context<Vote>,_poll_id: u64,_candidate:String,proof: Vec<[u8; 32]>) -> Result<()>{
+        let voter = ctx.accounts.signer.key();
+        let weight = match &ctx.accounts.poll_account.mode {
+            VotingMode::OneWalletOneVote => 1,
+            // Balances are read at vote time, so tokens moved to another wallet
+            // after voting can vote again; use a non-transferable mint for binding polls.
+            VotingMode::TokenWeighted { mint } => {
+                let token = ctx
+                    .accounts
+                    .voter_token_account
+                    .as_ref()
+                    .ok_or(VotingErrorCode::MissingTokenAccount)?;
+                if token.mint != *mint || token.owner != voter {
+                    return Err(VotingErrorCode::InvalidTokenAccount.into());
+                }
+                if token.amount == 0 {
+                    return Err(VotingErrorCode::NoVotingPower.into());
+                }
+                token.amount
+            }
+            VotingMode::MerkleAllowlist { root } => {
+                if !verify_allowlist_proof(&proof, root, &voter) {
+                    return Err(VotingErrorCode::NotAllowlisted.into());
+                }
+                1
+            }
+        };
+
         let candidate = &mut ctx.accounts.candidate_account;
         let current_time = Clock::get()?.unix_timestamp;
         if current_time > (ctx.accounts.poll_account.poll_vote_end as i64) {
@@ -45,12 +97,176 @@ pub mod voting {
             return Err(VotingErrorCode::VotingNotStarted.into());
         }
 
-        candidate.candidate_votes += 1;
+        // The receipt is created on the first vote; a set voter means this wallet already voted.
+        let receipt = &mut ctx.accounts.receipt;
+        if receipt.voter != Pubkey::default() {
+            return Err(VotingErrorCode::AlreadyVoted.into());
+        }
+        receipt.voter = voter;
+        receipt.candidate = candidate.candidate_name.clone();
+        receipt.voted_at = current_time;
+        receipt.weight = weight;
+        let poll = &mut ctx.accounts.poll_account;
+        poll.receipt_count = poll.receipt_count.checked_add(1).ok_or(VotingErrorCode::VoteOverflow)?;
+
+        candidate.candidate_votes = candidate
+            .candidate_votes
+            .checked_add(weight)
+            .ok_or(VotingErrorCode::VoteOverflow)?;
+
+        Ok(())
+    }
+
+    /// Moves the voting window. Only the poll authority can call it, and only
+    /// before voting ends; once voting has started the start time is fixed.
+    pub fn update_poll_window(ctx: Context<UpdatePoll>, _poll_id: u64, start: u64, end: u64) -> Result<()> {
+        let poll = &mut ctx.accounts.poll_account;
+        let current_time = Clock::get()?.unix_timestamp;
+        if poll.finalized {
+            return Err(VotingErrorCode::PollFinalized.into());
+        }
+        if current_time > (poll.poll_vote_end as i64) {
+            return Err(VotingErrorCode::VotingEnded.into());
+        }
+        if current_time >= (poll.poll_vote_start as i64) && start != poll.poll_vote_start {
+            return Err(VotingErrorCode::VotingStarted.into());
+        }
+        if start >= end || (end as i64) < current_time {
+            return Err(VotingErrorCode::InvalidPollWindow.into());
+        }
+        poll.poll_vote_start = start;
+        poll.poll_vote_end = end;
+        Ok(())
+    }
+
+    /// Records the winning candidate once voting has ended. Every candidate of
+    /// the poll must be passed in `remaining_accounts`; ties go to the
+    /// lexicographically smaller name.
+    pub fn finalize_results<'info>(ctx: Context<'_, '_, 'info, 'info, FinalizeResults<'info>>, poll_id: u64) -> Result<()> {
+        let poll = &mut ctx.accounts.poll_account;
+        let current_time = Clock::get()?.unix_timestamp;
+        if poll.finalized {
+            return Err(VotingErrorCode::PollFinalized.into());
+        }
+        if current_time <= (poll.poll_vote_end as i64) {
+            return Err(VotingErrorCode::VotingNotEnded.into());
+        }
+        if ctx.remaining_accounts.len() as u64 != poll.poll_vote_index {
+            return Err(VotingErrorCode::CandidateMismatch.into());
+        }
+
+        let mut seen: Vec<Pubkey> = Vec::with_capacity(ctx.remaining_accounts.len());
+        let mut winner = String::new();
+        let mut winner_votes = 0u64;
+        for info in ctx.remaining_accounts.iter() {
+            let candidate = load_candidate(info, poll_id)?;
+            if seen.contains(info.key) {
+                return Err(VotingErrorCode::CandidateMismatch.into());
+            }
+            seen.push(*info.key);
+            if winner.is_empty()
+                || candidate.candidate_votes > winner_votes
+                || (candidate.candidate_votes == winner_votes && candidate.candidate_name < winner)
+            {
+                winner = candidate.candidate_name.clone();
+                winner_votes = candidate.candidate_votes;
+            }
+        }
+
+        poll.finalized = true;
+        poll.winner = winner;
+        poll.winner_votes = winner_votes;
+        Ok(())
+    }
+
+    /// Closes vote receipts, returning each receipt's rent to the voter who paid
+    /// it. `remaining_accounts` holds `[receipt, voter]` pairs; large polls call
+    /// it in batches before `close_poll`. Not allowed while voting is open.
+    pub fn close_receipts<'info>(ctx: Context<'_, '_, 'info, 'info, CloseReceipts<'info>>, poll_id: u64) -> Result<()> {
+        let poll = &mut ctx.accounts.poll_account;
+        let current_time = Clock::get()?.unix_timestamp;
+        if current_time >= (poll.poll_vote_start as i64) && current_time <= (poll.poll_vote_end as i64) {
+            return Err(VotingErrorCode::VotingActive.into());
+        }
+        if ctx.remaining_accounts.len() % 2 != 0 {
+            return Err(VotingErrorCode::ReceiptMismatch.into());
+        }
+
+        for pair in ctx.remaining_accounts.chunks(2) {
+            let (info, voter) = (&pair[0], &pair[1]);
+            let receipt: Account<'info, VoteReceipt> = Account::try_from(info)?;
+            let (expected, _) = Pubkey::find_program_address(
+                &[poll_id.to_le_bytes().as_ref(), receipt.voter.as_ref()],
+                &crate::ID,
+            );
+            if expected != *info.key || !info.is_writable || receipt.voter != *voter.key || !voter.is_writable {
+                return Err(VotingErrorCode::ReceiptMismatch.into());
+            }
+            receipt.close(voter.clone())?;
+            poll.receipt_count = poll.receipt_count.saturating_sub(1);
+        }
+        Ok(())
+    }
 
+    /// Closes the poll and all of its candidate accounts, which must be passed
+    /// in `remaining_accounts`, returning their rent to the authority. Not
+    /// allowed while voting is open, or before `close_receipts` has closed every
+    /// vote receipt. Receipts or candidates left behind would carry over into a
+    /// new poll created with the same id.
+    pub fn close_poll<'info>(ctx: Context<'_, '_, 'info, 'info, ClosePoll<'info>>, poll_id: u64) -> Result<()> {
+        let poll = &ctx.accounts.poll_account;
+        let current_time = Clock::get()?.unix_timestamp;
+        if current_time >= (poll.poll_vote_start as i64) && current_time <= (poll.poll_vote_end as i64) {
+            return Err(VotingErrorCode::VotingActive.into());
+        }
+        if poll.receipt_count > 0 {
+            return Err(VotingErrorCode::ReceiptsOpen.into());
+        }
+        if ctx.remaining_accounts.len() as u64 != poll.poll_vote_index {
+            return Err(VotingErrorCode::CandidateMismatch.into());
+        }
+
+        let mut seen: Vec<Pubkey> = Vec::with_capacity(ctx.remaining_accounts.len());
+        for info in ctx.remaining_accounts.iter() {
+            let candidate = load_candidate(info, poll_id)?;
+            if seen.contains(info.key) {
+                return Err(VotingErrorCode::CandidateMismatch.into());
+            }
+            seen.push(*info.key);
+            candidate.close(ctx.accounts.authority.to_account_info())?;
+        }
         Ok(())
     }
+}
 
+/// Checks that `voter` is a leaf of the allowlist tree with `root`. Leaves are
+/// sha256(0x00 || voter) and inner nodes sha256(0x01 || min(a, b) || max(a, b)),
+/// so a proof is just the sibling hashes from leaf to root.
+fn verify_allowlist_proof(proof: &[[u8; 32]], root: &[u8; 32], voter: &Pubkey) -> bool {
+    let mut node = hashv(&[&[0u8], voter.as_ref()]).to_bytes();
+    for sibling in proof {
+        node = if node <= *sibling {
+            hashv(&[&[1u8], &node, sibling])
+        } else {
+            hashv(&[&[1u8], sibling, &node])
+        }
+        .to_bytes();
+    }
+    node == *root
+}
 
+/// Loads a candidate account from `remaining_accounts`, checking that it is a
+/// writable `CandidateAccount` at the `[poll_id_le, name]` PDA of this poll.
+fn load_candidate<'info>(info: &'info AccountInfo<'info>, poll_id: u64) -> Result<Account<'info, CandidateAccount>> {
+    let candidate: Account<'info, CandidateAccount> = Account::try_from(info)?;
+    let (expected, _) = Pubkey::find_program_address(
+        &[poll_id.to_le_bytes().as_ref(), candidate.candidate_name.as_ref()],
+        &crate::ID,
+    );
+    if expected != *info.key || !info.is_writable {
+        return Err(VotingErrorCode::CandidateMismatch.into());
+    }
+    Ok(candidate)
 }
 
 #[derive(Accounts)]
@@ -74,7 +290,35 @@ pub struct Vote<'info>{
     )]
     pub candidate_account: Account<'info,CandidateAccount>,
 
+    /// One receipt per voter and poll, paid for by the voter.
+    #[account(
+        init_if_needed,
+        payer = signer,
+        space = 8 + VoteReceipt::INIT_SPACE,
+        seeds = [poll_id.to_le_bytes().as_ref(), signer.key().as_ref()],
+        bump
+    )]
+    pub receipt: Account<'info, VoteReceipt>,
 
+    pub system_program: Program<'info, System>,
+
+    /// The signer's token account for the poll mint; only used by token-weighted polls.
+    pub voter_token_account: Option<Account<'info, TokenAccount>>,
+}
+
+/// Proof that `voter` voted in a poll, at PDA `[poll_id_le, voter]`.
+#[account]
+#[derive(InitSpace)]
+pub struct VoteReceipt {
+    pub voter: Pubkey,
+
+    #[max_len(10)]
+    pub candidate: String,
+
+    pub voted_at: i64,
+
+    /// Votes added to the candidate: 1, or the token balance in token-weighted polls.
+    pub weight: u64,
 }
 
 
@@ -85,8 +329,10 @@ pub struct InitializePoll<'info> {
     #[account(mut)]
     pub signer: Signer<'info>,
 
+    /// `init` fails if the poll already exists, so a live or finalized poll
+    /// cannot be reset; a closed poll's id can be used again.
     #[account(
-        init_if_needed,
+        init,
          payer = signer, 
          space = 8 + Poll::INIT_SPACE,
          seeds = [b"poll", poll_id.to_le_bytes().as_ref()],
@@ -111,20 +357,115 @@ pub struct Poll {
     pub poll_vote_end: u64,
 
     pub poll_vote_index: u64,
+
+    /// Signer of initialize_poll; the only key allowed to update or close the poll.
+    pub authority: Pubkey,
+
+    /// Set by finalize_results once voting has ended.
+    pub finalized: bool,
+
+    /// Winning candidate name, empty until finalized (or if the poll had no candidates).
+    #[max_len(10)]
+    pub winner: String,
+
+    pub winner_votes: u64,
+
+    /// Who may vote and how votes are weighted.
+    pub mode: VotingMode,
+
+    /// Open vote receipts; close_poll requires close_receipts to bring it to zero.
+    pub receipt_count: u64,
+}
+
+/// How votes in a poll are counted and who may cast them.
+#[derive(AnchorSerialize, AnchorDeserialize, Clone, PartialEq, Eq, InitSpace)]
+pub enum VotingMode {
+    /// Every wallet casts one vote.
+    OneWalletOneVote,
+    /// A vote weighs the voter's balance of `mint`.
+    TokenWeighted { mint: Pubkey },
+    /// Only wallets in the Merkle tree with `root` may vote, one vote each.
+    MerkleAllowlist { root: [u8; 32] },
+}
+
+#[derive(Accounts)]
+#[instruction(poll_id: u64)]
+pub struct UpdatePoll<'info> {
+
+    pub authority: Signer<'info>,
+
+    #[account(
+        mut,
+        seeds = [b"poll", poll_id.to_le_bytes().as_ref()],
+        bump,
+        has_one = authority @ VotingErrorCode::Unauthorized,
+    )]
+    pub poll_account: Account<'info, Poll>,
+}
+
+#[derive(Accounts)]
+#[instruction(poll_id: u64)]
+pub struct FinalizeResults<'info> {
+
+    pub signer: Signer<'info>,
+
+    #[account(
+        mut,
+        seeds = [b"poll", poll_id.to_le_bytes().as_ref()],
+        bump
+    )]
+    pub poll_account: Account<'info, Poll>,
+}
+
+#[derive(Accounts)]
+#[instruction(poll_id: u64)]
+pub struct CloseReceipts<'info> {
+
+    pub authority: Signer<'info>,
+
+    #[account(
+        mut,
+        seeds = [b"poll", poll_id.to_le_bytes().as_ref()],
+        bump,
+        has_one = authority @ VotingErrorCode::Unauthorized,
+    )]
+    pub poll_account: Account<'info, Poll>,
+}
+
+#[derive(Accounts)]
+#[instruction(poll_id: u64)]
+pub struct ClosePoll<'info> {
+
+    #[account(mut)]
+    pub authority: Signer<'info>,
+
+    #[account(
+        mut,
+        close = authority,
+        seeds = [b"poll", poll_id.to_le_bytes().as_ref()],
+        bump,
+        has_one = authority @ VotingErrorCode::Unauthorized,
+    )]
+    pub poll_account: Account<'info, Poll>,
 }
 #[derive(Accounts)]
 #[instruction(poll_id:u64,candidate:String)]
 pub struct InitializeCandidate<'info>{
     
     #[account(mut)]
-    pub signer: Signer<'info>,
-
+    pub authority: Signer<'info>,
 
+    #[account(
+        mut,
+        seeds = [b"poll", poll_id.to_le_bytes().as_ref()],
+        bump,
+        has_one = authority @ VotingErrorCode::Unauthorized,
+    )]
     pub poll_account: Account<'info, Poll>,
 
     #[account(
         init_if_needed,
-        payer = signer,
+        payer = authority,
         space = 8 + CandidateAccount::INIT_SPACE,
         seeds = [poll_id.to_le_bytes().as_ref(),candidate.as_ref()],
         bump
@@ -158,5 +499,50 @@ pub enum VotingErrorCode {
     VotingNotStarted,
 
     #[msg("Voting has ended")]
-    VotingEnded
-}
\ No newline at end of file
+    VotingEnded,
+
+    #[msg("Voting has not ended yet")]
+    VotingNotEnded,
+
+    #[msg("Voting has already started")]
+    VotingStarted,
+
+    #[msg("Voting is in progress")]
+    VotingActive,
+
+    #[msg("Poll start must be before its end, and the end in the future")]
+    InvalidPollWindow,
+
+    #[msg("Signer is not the poll authority")]
+    Unauthorized,
+
+    #[msg("Poll results are already finalized")]
+    PollFinalized,
+
+    #[msg("Candidate accounts do not match the poll")]
+    CandidateMismatch,
+
+    #[msg("This wallet has already voted in this poll")]
+    AlreadyVoted,
+
+    #[msg("Token-weighted polls need the voter's token account")]
+    MissingTokenAccount,
+
+    #[msg("Token account is not the voter's account for the poll mint")]
+    InvalidTokenAccount,
+
+    #[msg("Voter holds no tokens of the poll mint")]
+    NoVotingPower,
+
+    #[msg("Voter is not on the poll allowlist")]
+    NotAllowlisted,
+
+    #[msg("Candidate vote count overflowed")]
+    VoteOverflow,
+
+    #[msg("Vote receipt accounts do not match the poll")]
+    ReceiptMismatch,
+
+    #[msg("Close the poll's vote receipts first")]
+    ReceiptsOpen
+}
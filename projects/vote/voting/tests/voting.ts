@@ -1,16 +1,464 @@
 import * as anchor from "@coral-xyz/anchor";
 import { Program } from "@coral-xyz/anchor";
+import { ACCOUNT_SIZE, AccountLayout, AccountState, MINT_SIZE, MintLayout, TOKEN_PROGRAM_ID } from "@solana/spl-token";
+import { BankrunProvider } from "anchor-bankrun";
+import { Clock, ProgramTestContext, startAnchor } from "solana-bankrun";
 import { Voting } from "../target/types/voting";
+import { assert } from 'chai';
+import { createHash } from 'crypto';
+
+const IDL = require("../target/idl/voting.json");
 
 describe("voting", () => {
-  // Configure the client to use the local cluster.
-  anchor.setProvider(anchor.AnchorProvider.env());
+  // The program runs in bankrun so tests can set the clock instead of sleeping.
+  let context: ProgramTestContext;
+  let provider: BankrunProvider;
+  let program: Program<Voting>;
+  let authority: anchor.web3.PublicKey;
+
+  const T0 = 1_700_000_000;
+  const pollId = new anchor.BN(1);
+  const candidates = ['alice', 'bob'];
+
+  // Each voter pays for its own receipt, so they start with lamports.
+  const voters = [0, 1, 2].map(() => anchor.web3.Keypair.generate());
+  const funded = (key: anchor.web3.PublicKey) => ({
+    address: key,
+    info: {
+      lamports: anchor.web3.LAMPORTS_PER_SOL,
+      data: Buffer.alloc(0),
+      owner: anchor.web3.SystemProgram.programId,
+      executable: false,
+    },
+  });
+  const other = anchor.web3.Keypair.generate();
+  const late = anchor.web3.Keypair.generate();
+
+  before(async () => {
+    context = await startAnchor("", [], [...voters, other, late].map((kp) => funded(kp.publicKey)));
+    provider = new BankrunProvider(context);
+    anchor.setProvider(provider);
+    program = new Program<Voting>(IDL, provider);
+    authority = provider.wallet.publicKey;
+    await setTime(T0);
+  });
+
+  // setTime moves the bank clock to unixTimestamp. The slot advances too, so
+  // a retried transaction gets a fresh blockhash.
+  const setTime = async (unixTimestamp: number) => {
+    const clock = await context.banksClient.getClock();
+    context.warpToSlot(clock.slot + BigInt(1));
+    context.setClock(new Clock(
+      clock.slot + BigInt(1),
+      clock.epochStartTimestamp,
+      clock.epoch,
+      clock.leaderScheduleEpoch,
+      BigInt(unixTimestamp)));
+  };
+
+  const pollPda = (id = pollId) => anchor.web3.PublicKey.findProgramAddressSync(
+    [Buffer.from('poll'), id.toArrayLike(Buffer, 'le', 8)],
+    program.programId)[0];
+  const candidatePda = (name: string, id = pollId) => anchor.web3.PublicKey.findProgramAddressSync(
+    [id.toArrayLike(Buffer, 'le', 8), Buffer.from(name)],
+    program.programId)[0];
+  const receiptPda = (voter: anchor.web3.PublicKey, id = pollId) => anchor.web3.PublicKey.findProgramAddressSync(
+    [id.toArrayLike(Buffer, 'le', 8), voter.toBuffer()],
+    program.programId)[0];
+  const candidateMetas = (names = candidates, id = pollId) => names.map((name) => ({
+    pubkey: candidatePda(name, id), isSigner: false, isWritable: true,
+  }));
+
+  // expectError runs fn and asserts it fails with the given VotingErrorCode.
+  const expectError = async (fn: () => Promise<unknown>, code: string) => {
+    try {
+      await fn();
+    } catch (err) {
+      assert.equal(err.error?.errorCode?.code, code);
+      return;
+    }
+    assert.fail(`expected ${code}`);
+  };
+
+  const vote = (name: string, voter = voters[0], id = pollId, proof: number[][] = []) => program.methods
+    .vote(id, name, proof)
+    .accounts({ signer: voter.publicKey, voterTokenAccount: null })
+    .signers([voter])
+    .rpc();
+
+  it("creates a future poll with candidates", async () => {
+    await program.methods
+      .initializePoll(pollId, new anchor.BN(T0 + 3600), new anchor.BN(T0 + 7200), 'lifecycle', 'poll lifecycle test', { oneWalletOneVote: {} })
+      .accounts({ signer: authority })
+      .rpc();
+    for (const name of candidates) {
+      await program.methods
+        .initializeCandidate(pollId, name)
+        .accountsPartial({ authority })
+        .rpc();
+    }
+
+    const poll = await program.account.poll.fetch(pollPda());
+    assert.equal(poll.authority.toBase58(), authority.toBase58());
+    assert.equal(poll.pollVoteIndex.toNumber(), candidates.length);
+    assert.isFalse(poll.finalized);
+  });
+
+  it("cannot re-initialize an existing poll", async () => {
+    try {
+      await program.methods
+        .initializePoll(pollId, new anchor.BN(T0 - 10), new anchor.BN(T0 + 60), 'reset', 'reset attempt', { oneWalletOneVote: {} })
+        .accounts({ signer: authority })
+        .rpc();
+    } catch (err) {
+      // The system program refuses to create an account that already exists.
+      assert.match(String(err.logs ?? err), /already in use/);
+      return;
+    }
+    assert.fail('expected initialize_poll to fail');
+  });
+
+  it("only lets the authority add candidates", async () => {
+    await expectError(() => program.methods
+      .initializeCandidate(pollId, 'mallory')
+      .accountsPartial({ authority: other.publicKey })
+      .signers([other])
+      .rpc(), 'Unauthorized');
+  });
+
+  it("rejects votes and finalize before the window", async () => {
+    await expectError(() => vote('alice'), 'VotingNotStarted');
+    await expectError(() => program.methods
+      .finalizeResults(pollId)
+      .accounts({ signer: authority })
+      .remainingAccounts(candidateMetas())
+      .rpc(), 'VotingNotEnded');
+  });
+
+  it("only lets the authority move the window", async () => {
+    await expectError(() => program.methods
+      .updatePollWindow(pollId, new anchor.BN(T0 + 100), new anchor.BN(T0 + 200))
+      .accountsPartial({ authority: other.publicKey })
+      .signers([other])
+      .rpc(), 'Unauthorized');
+
+    await program.methods
+      .updatePollWindow(pollId, new anchor.BN(T0 + 100), new anchor.BN(T0 + 200))
+      .accountsPartial({ authority })
+      .rpc();
+  });
+
+  it("fixes the start once voting has started", async () => {
+    await setTime(T0 + 100);
+    await expectError(() => program.methods
+      .updatePollWindow(pollId, new anchor.BN(T0 + 150), new anchor.BN(T0 + 200))
+      .accountsPartial({ authority })
+      .rpc(), 'VotingStarted');
+    await expectError(() => program.methods
+      .closePoll(pollId)
+      .accountsPartial({ authority })
+      .rpc(), 'VotingActive');
+    await expectError(() => program.methods
+      .initializeCandidate(pollId, 'carol')
+      .accountsPartial({ authority })
+      .rpc(), 'VotingStarted');
+
+    // The end can still move while voting is open.
+    await program.methods
+      .updatePollWindow(pollId, new anchor.BN(T0 + 100), new anchor.BN(T0 + 300))
+      .accountsPartial({ authority })
+      .rpc();
+  });
+
+  it("counts one vote per wallet", async () => {
+    await vote('bob', voters[0]);
+    await vote('bob', voters[1]);
+    await vote('alice', voters[2]);
+    await expectError(() => vote('alice', voters[0]), 'AlreadyVoted');
+
+    const receipt = await program.account.voteReceipt.fetch(receiptPda(voters[0].publicKey));
+    assert.equal(receipt.voter.toBase58(), voters[0].publicKey.toBase58());
+    assert.equal(receipt.candidate, 'bob');
+  });
+
+  it("accepts votes until the last second of the window", async () => {
+    await setTime(T0 + 300);
+    await vote('alice', late);
+
+    await setTime(T0 + 301);
+    await expectError(() => vote('alice', voters[0]), 'VotingEnded');
+  });
+
+  it("finalizes the winner after voting ends", async () => {
+    await expectError(() => program.methods
+      .updatePollWindow(pollId, new anchor.BN(T0 + 400), new anchor.BN(T0 + 500))
+      .accountsPartial({ authority })
+      .rpc(), 'VotingEnded');
+    await expectError(() => program.methods
+      .finalizeResults(pollId)
+      .accounts({ signer: authority })
+      .remainingAccounts(candidateMetas().slice(1))
+      .rpc(), 'CandidateMismatch');
+
+    await program.methods
+      .finalizeResults(pollId)
+      .accounts({ signer: authority })
+      .remainingAccounts(candidateMetas())
+      .rpc();
+
+    // bob has voters 0 and 1; alice has voter 2 and the late voter, and wins the tie by name.
+    const poll = await program.account.poll.fetch(pollPda());
+    assert.isTrue(poll.finalized);
+    assert.equal(poll.winner, 'alice');
+    assert.equal(poll.winnerVotes.toNumber(), 2);
+  });
+
+  it("closes the receipts, then the poll and its candidates", async () => {
+    const closeables = [...voters, late];
+    await expectError(() => program.methods
+      .closePoll(pollId)
+      .accountsPartial({ authority })
+      .remainingAccounts(candidateMetas())
+      .rpc(), 'ReceiptsOpen');
+    // A receipt must be paired with its own voter.
+    await expectError(() => program.methods
+      .closeReceipts(pollId)
+      .accountsPartial({ authority })
+      .remainingAccounts([
+        { pubkey: receiptPda(voters[0].publicKey), isSigner: false, isWritable: true },
+        { pubkey: voters[1].publicKey, isSigner: false, isWritable: true },
+      ])
+      .rpc(), 'ReceiptMismatch');
+
+    const before = await context.banksClient.getBalance(voters[0].publicKey);
+    await program.methods
+      .closeReceipts(pollId)
+      .accountsPartial({ authority })
+      .remainingAccounts(closeables.flatMap((voter) => [
+        { pubkey: receiptPda(voter.publicKey), isSigner: false, isWritable: true },
+        { pubkey: voter.publicKey, isSigner: false, isWritable: true },
+      ]))
+      .rpc();
+    assert.isAbove(Number(await context.banksClient.getBalance(voters[0].publicKey)), Number(before));
+
+    await program.methods
+      .closePoll(pollId)
+      .accountsPartial({ authority })
+      .remainingAccounts(candidateMetas())
+      .rpc();
+
+    assert.isNull(await context.banksClient.getAccount(pollPda()));
+    for (const name of candidates) {
+      assert.isNull(await context.banksClient.getAccount(candidatePda(name)));
+    }
+    for (const voter of closeables) {
+      assert.isNull(await context.banksClient.getAccount(receiptPda(voter.publicKey)));
+    }
+  });
+
+  it("lets earlier voters vote again when a closed poll id is reused", async () => {
+    const now = T0 + 500;
+    await setTime(now);
+    await program.methods
+      .initializePoll(pollId, new anchor.BN(now + 10), new anchor.BN(now + 100), 'reused', 'reused poll id', { oneWalletOneVote: {} })
+      .accounts({ signer: authority })
+      .rpc();
+    await program.methods
+      .initializeCandidate(pollId, 'alice')
+      .accountsPartial({ authority })
+      .rpc();
+    await setTime(now + 10);
+
+    await vote('alice', voters[0]);
+    const candidate = await program.account.candidateAccount.fetch(candidatePda('alice'));
+    assert.equal(candidate.candidateVotes.toNumber(), 1);
+  });
+
+  it("only closes a poll with all of its candidates, so a reused id starts clean", async () => {
+    const closeId = pollId.addn(3);
+    const now = T0 + 600;
+    await setTime(now);
+    await program.methods
+      .initializePoll(closeId, new anchor.BN(now + 10), new anchor.BN(now + 20), 'partial', 'partial close test', { oneWalletOneVote: {} })
+      .accounts({ signer: authority })
+      .rpc();
+    for (const name of candidates) {
+      await program.methods
+        .initializeCandidate(closeId, name)
+        .accountsPartial({ authority })
+        .rpc();
+    }
+    await setTime(now + 10);
+    await vote('bob', voters[0], closeId);
+    await setTime(now + 21);
+    await program.methods
+      .closeReceipts(closeId)
+      .accountsPartial({ authority })
+      .remainingAccounts([
+        { pubkey: receiptPda(voters[0].publicKey, closeId), isSigner: false, isWritable: true },
+        { pubkey: voters[0].publicKey, isSigner: false, isWritable: true },
+      ])
+      .rpc();
+
+    // Leaving bob open would carry his vote into the next poll with this id.
+    await expectError(() => program.methods
+      .closePoll(closeId)
+      .accountsPartial({ authority })
+      .remainingAccounts(candidateMetas(['alice'], closeId))
+      .rpc(), 'CandidateMismatch');
+    await expectError(() => program.methods
+      .closePoll(closeId)
+      .accountsPartial({ authority })
+      .remainingAccounts(candidateMetas(['alice', 'alice'], closeId))
+      .rpc(), 'CandidateMismatch');
+    await program.methods
+      .closePoll(closeId)
+      .accountsPartial({ authority })
+      .remainingAccounts(candidateMetas(candidates, closeId))
+      .rpc();
+
+    await program.methods
+      .initializePoll(closeId, new anchor.BN(now + 30), new anchor.BN(now + 100), 'reused', 'reused after a full close', { oneWalletOneVote: {} })
+      .accounts({ signer: authority })
+      .rpc();
+    await program.methods
+      .initializeCandidate(closeId, 'bob')
+      .accountsPartial({ authority })
+      .rpc();
+    const poll = await program.account.poll.fetch(pollPda(closeId));
+    assert.equal(poll.pollVoteIndex.toNumber(), 1);
+    const bob = await program.account.candidateAccount.fetch(candidatePda('bob', closeId));
+    assert.equal(bob.candidateVotes.toNumber(), 0);
+    assert.isNull(await context.banksClient.getAccount(candidatePda('alice', closeId)));
+  });
+
+  // Same hashing as verify_allowlist_proof: sha256(0x00 || key) leaves and
+  // sha256(0x01 || min || max) nodes.
+  const sha256 = (...parts: Buffer[]) => createHash('sha256').update(Buffer.concat(parts)).digest();
+  const leaf = (key: anchor.web3.PublicKey) => sha256(Buffer.from([0]), key.toBuffer());
+  const node = (a: Buffer, b: Buffer) =>
+    Buffer.compare(a, b) <= 0 ? sha256(Buffer.from([1]), a, b) : sha256(Buffer.from([1]), b, a);
+
+  it("hashes the allowlist like sdk/merkle", () => {
+    // Known-answer vector shared with sdk/merkle/merkle_test.go: keys whose 32
+    // bytes are all 1, 2 and 3. Sorted leaves are [3, 2, 1]; the odd leaf is
+    // promoted, so the root is node(node(leaf3, leaf2), leaf1).
+    const key = (b: number) => new anchor.web3.PublicKey(Buffer.alloc(32, b));
+    const [l1, l2, l3] = [1, 2, 3].map((b) => leaf(key(b)));
+    assert.equal(l1.toString('hex'), 'dcffe786ded16d283c663846ad0c4ff26558fccde36ca9d30b2ea19eade9fc0e');
+    assert.equal(l2.toString('hex'), 'cba8c596120bdb69debbd923d92cba948bde7c7d06a465a1bb7d98d3116038fa');
+    assert.equal(l3.toString('hex'), 'acaa04663a8547a2f70c60cc18f9378796b13c4f9a08f70d6adae662365b30c6');
+    assert.equal(node(l1, l2).toString('hex'), 'eba78221b5ef7ed38c4b246fda4b1a3b283ce4c19047907b43676eb2863585d6');
+    assert.equal(node(node(l3, l2), l1).toString('hex'), 'b9fa49a224871b2afd7bf8352832868661aa627cbb176cc8934edd575d35c701');
+  });
+
+  it("only counts allowlisted voters in allowlist polls", async () => {
+    const allowId = pollId.addn(1);
+    const now = T0 + 1000;
+    await setTime(now);
+    // Two allowed voters: each proof is the other voter's leaf.
+    const root = node(leaf(voters[0].publicKey), leaf(voters[1].publicKey));
+    await program.methods
+      .initializePoll(allowId, new anchor.BN(now + 10), new anchor.BN(now + 3600), 'allowlist', 'merkle allowlist test', { merkleAllowlist: { root: [...root] } })
+      .accounts({ signer: authority })
+      .rpc();
+    await program.methods
+      .initializeCandidate(allowId, 'alice')
+      .accountsPartial({ authority })
+      .rpc();
+    await setTime(now + 10);
+
+    await vote('alice', voters[0], allowId, [[...leaf(voters[1].publicKey)]]);
+    await expectError(() => vote('alice', voters[2], allowId, [[...leaf(voters[0].publicKey)]]), 'NotAllowlisted');
+    await expectError(() => vote('alice', voters[1], allowId, []), 'NotAllowlisted');
+
+    const candidate = await program.account.candidateAccount.fetch(candidatePda('alice', allowId));
+    assert.equal(candidate.candidateVotes.toNumber(), 1);
+  });
+
+  // setMint and setTokenAccount write SPL accounts straight into the bank, so
+  // the tests need no mint authority or token transactions.
+  const setMint = (mint: anchor.web3.PublicKey) => {
+    const data = Buffer.alloc(MINT_SIZE);
+    MintLayout.encode({
+      mintAuthorityOption: 0,
+      mintAuthority: anchor.web3.PublicKey.default,
+      supply: BigInt(1_000_000),
+      decimals: 0,
+      isInitialized: true,
+      freezeAuthorityOption: 0,
+      freezeAuthority: anchor.web3.PublicKey.default,
+    }, data);
+    context.setAccount(mint, { lamports: anchor.web3.LAMPORTS_PER_SOL, data, owner: TOKEN_PROGRAM_ID, executable: false });
+  };
+  const setTokenAccount = (mint: anchor.web3.PublicKey, owner: anchor.web3.PublicKey, amount: number) => {
+    const address = anchor.web3.Keypair.generate().publicKey;
+    const data = Buffer.alloc(ACCOUNT_SIZE);
+    AccountLayout.encode({
+      mint,
+      owner,
+      amount: BigInt(amount),
+      delegateOption: 0,
+      delegate: anchor.web3.PublicKey.default,
+      state: AccountState.Initialized,
+      isNativeOption: 0,
+      isNative: BigInt(0),
+      delegatedAmount: BigInt(0),
+      closeAuthorityOption: 0,
+      closeAuthority: anchor.web3.PublicKey.default,
+    }, data);
+    context.setAccount(address, { lamports: anchor.web3.LAMPORTS_PER_SOL, data, owner: TOKEN_PROGRAM_ID, executable: false });
+    return address;
+  };
+
+  const tokenVote = (voter: anchor.web3.Keypair, id: anchor.BN, tokenAccount: anchor.web3.PublicKey) => program.methods
+    .vote(id, 'alice', [])
+    .accounts({ signer: voter.publicKey, voterTokenAccount: tokenAccount })
+    .signers([voter])
+    .rpc();
+
+  describe("token-weighted polls", () => {
+    const tokenId = pollId.addn(2);
+    const mint = anchor.web3.Keypair.generate().publicKey;
+    const otherMint = anchor.web3.Keypair.generate().publicKey;
+
+    before(async () => {
+      const now = T0 + 2000;
+      await setTime(now);
+      setMint(mint);
+      setMint(otherMint);
+      await program.methods
+        .initializePoll(tokenId, new anchor.BN(now + 10), new anchor.BN(now + 3600), 'tokens', 'token-weighted test', { tokenWeighted: { mint } })
+        .accounts({ signer: authority })
+        .rpc();
+      await program.methods
+        .initializeCandidate(tokenId, 'alice')
+        .accountsPartial({ authority })
+        .rpc();
+      await setTime(now + 10);
+    });
+
+    it("weighs a vote by the voter's token balance", async () => {
+      await tokenVote(voters[0], tokenId, setTokenAccount(mint, voters[0].publicKey, 250));
+
+      const candidate = await program.account.candidateAccount.fetch(candidatePda('alice', tokenId));
+      assert.equal(candidate.candidateVotes.toNumber(), 250);
+      const receipt = await program.account.voteReceipt.fetch(receiptPda(voters[0].publicKey, tokenId));
+      assert.equal(receipt.weight.toNumber(), 250);
+    });
+
+    it("rejects a token account of another mint", async () => {
+      await expectError(() => tokenVote(voters[1], tokenId, setTokenAccount(otherMint, voters[1].publicKey, 100)), 'InvalidTokenAccount');
+    });
 
-  const program = anchor.workspace.voting as Program<Voting>;
+    it("rejects a token account owned by another wallet", async () => {
+      await expectError(() => tokenVote(voters[1], tokenId, setTokenAccount(mint, voters[2].publicKey, 100)), 'InvalidTokenAccount');
+    });
 
-  it("Is initialized!", async () => {
-    // Add your test here.
-    const tx = await program.methods.initialize().rpc();
-    console.log("Your transaction signature", tx);
+    it("rejects voters without tokens", async () => {
+      await expectError(() => tokenVote(voters[1], tokenId, setTokenAccount(mint, voters[1].publicKey, 0)), 'NoVotingPower');
+      const candidate = await program.account.candidateAccount.fetch(candidatePda('alice', tokenId));
+      assert.equal(candidate.candidateVotes.toNumber(), 250);
+    });
   });
 });